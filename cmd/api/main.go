@@ -4,57 +4,261 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
+	"oracle-golang/internal/audit"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/cache"
 	"oracle-golang/internal/config"
 	"oracle-golang/internal/database"
+	"oracle-golang/internal/datasource"
 	"oracle-golang/internal/handler"
+	"oracle-golang/internal/health"
+	"oracle-golang/internal/idempotency"
+	"oracle-golang/internal/job"
+	"oracle-golang/internal/logger"
+	"oracle-golang/internal/metrics"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/openapi"
+	"oracle-golang/internal/ratelimit"
+	"oracle-golang/internal/redact"
+	"oracle-golang/internal/replica"
 	"oracle-golang/internal/repository"
+	"oracle-golang/internal/resilience"
+	"oracle-golang/internal/scheduler"
 	"oracle-golang/internal/service"
+	"oracle-golang/internal/session"
+	"oracle-golang/internal/tenant"
+	"oracle-golang/internal/tracing"
+	"oracle-golang/internal/webhook"
 	"os"
 	"os/signal"
+	"slices"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	_ "github.com/sijms/go-ora/v2"
 )
 
 func main() {
-	err := godotenv.Load()
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML configuration file")
+	flag.Parse()
+
+	envErr := godotenv.Load()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fatal("Failed to load configuration", err)
+	}
+
+	appLogger, err := logger.New(os.Stdout, cfg.Logging.Level)
 	if err != nil {
-		log.Println("No .env file found")
+		fatal("Failed to configure logging", err)
+	}
+	slog.SetDefault(appLogger)
+	if envErr != nil {
+		slog.Info("No .env file found")
 	}
 
-	cfg := config.NewConfig()
-	conn, err := database.NewOracleDatabase().Connect(cfg.OracleDatabase.DSN())
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName)
 	if err != nil {
-		log.Fatal(err)
+		fatal("Failed to set up tracing", err)
 	}
-	defer func(conn *sql.DB) {
-		err := conn.Close()
+
+	if cfg.Sessions.Enabled() && !cfg.Sessions.Required {
+		slog.Warn("Database sessions are not required, unauthenticated callers use the shared pool")
+	}
+
+	dataSources := cfg.DataSources()
+	conns := make(map[string]*sql.DB, len(dataSources))
+	replicaConns := make(map[string]*sql.DB)
+	// Per-user session pools, keyed like conns and replicaConns. They are only set when
+	// sessions are enabled.
+	sessionPools := make(map[string]*session.Pool)
+	replicaSessionPools := make(map[string]*session.Pool)
+	for name, ds := range dataSources {
+		conn, err := connect(ds.Database)
 		if err != nil {
-			log.Fatal(err)
+			fatal("Failed to connect to datasource "+name, err)
+		}
+		defer func(conn *sql.DB) {
+			err := conn.Close()
+			if err != nil {
+				slog.Error("Database close encountered an error", "datasource", name, "error", err)
+			}
+		}(conn)
+		conns[name] = conn
+		slog.Info("Connected to Database", "datasource", name)
+		if cfg.Sessions.Enabled() {
+			sessionPools[name] = newSessionPool(cfg.Sessions, ds.Database)
+			defer closeSessionPool(name, sessionPools[name])
 		}
-	}(conn)
-	log.Println("Connected to Database")
 
-	r := setupRouter(conn)
+		if ds.Replica == nil {
+			continue
+		}
+		replicaConn, err := connect(ds.Replica.OracleDatabase)
+		if err != nil {
+			fatal("Failed to connect to the replica of datasource "+name, err)
+		}
+		defer func(conn *sql.DB) {
+			err := conn.Close()
+			if err != nil {
+				slog.Error("Replica close encountered an error", "datasource", name, "error", err)
+			}
+		}(replicaConn)
+		replicaConns[name] = replicaConn
+		slog.Info("Connected to replica", "datasource", name)
+		if cfg.Sessions.Enabled() {
+			replicaSessionPools[name] = newSessionPool(cfg.Sessions, ds.Replica.OracleDatabase)
+			defer closeSessionPool(name, replicaSessionPools[name])
+		}
+	}
+	// The default data source also holds the audit table and the scheduler locks.
+	conn := conns[cfg.DefaultDatasource]
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	for name, c := range conns {
+		dbName := "oracle"
+		if len(cfg.Datasources) > 0 {
+			dbName = name
+		}
+		registry.MustRegister(collectors.NewDBStatsCollector(c, dbName))
+		if rc, ok := replicaConns[name]; ok {
+			registry.MustRegister(collectors.NewDBStatsCollector(rc, dbName+"_replica"))
+		}
+	}
+	procedureMetrics := metrics.New(registry)
+
+	auditSink, err := newAuditSink(cfg.Audit, conn)
+	if err != nil {
+		fatal("Failed to open audit sink", err)
+	}
+	if auditSink != nil {
+		defer func(auditSink audit.Sink) {
+			err := auditSink.Close()
+			if err != nil {
+				slog.Error("Audit sink close encountered an error", "error", err)
+			}
+		}(auditSink)
+	}
+
+	limiter := newLimiter(cfg.Policy)
+	procedureServices := make(map[string]datasource.Service, len(dataSources))
+	for name, ds := range dataSources {
+		primary := node{conn: conns[name], sessions: sessionPools[name]}
+		var standby *node
+		if rc, ok := replicaConns[name]; ok {
+			standby = &node{conn: rc, sessions: replicaSessionPools[name]}
+		}
+		procedureServices[name], err = newProcedureService(cfg, ds.Policy, primary, standby, procedureMetrics, auditSink, limiter.Scoped(procedureRules(ds.Policy)))
+		if err != nil {
+			fatal("Failed to configure datasource "+name, err)
+		}
+	}
+	procedureService := datasource.NewRouter(procedureServices, cfg.DefaultDatasource)
+
+	var jobManager *job.Manager
+	if cfg.Features.Jobs {
+		jobStore, err := newJobStore(cfg.Job)
+		if err != nil {
+			fatal("Failed to open job store", err)
+		}
+		defer func(jobStore job.Store) {
+			err := jobStore.Close()
+			if err != nil {
+				slog.Error("Job store close encountered an error", "error", err)
+			}
+		}(jobStore)
+
+		// Callbacks are never sent unsigned: without a secret, jobs asking for one are refused.
+		var jobOpts []job.Option
+		if cfg.Webhook.Secret != "" {
+			jobOpts = append(jobOpts, job.WithNotifier(webhook.NewNotifier(webhook.Options{
+				Secret:               cfg.Webhook.Secret,
+				AllowPrivateNetworks: cfg.Webhook.AllowPrivateNetworks,
+				MaxAttempts:          cfg.Webhook.MaxAttempts,
+				BaseDelay:            cfg.Webhook.BaseDelay,
+				MaxDelay:             cfg.Webhook.MaxDelay,
+				Timeout:              cfg.Webhook.Timeout,
+			}, webhook.NewFileDeadLetterStore(cfg.Webhook.DeadLetterPath))))
+		} else {
+			slog.Info("No webhook secret is configured, job callbacks are disabled")
+		}
+
+		jobManager = job.NewManager(procedureService, jobStore, cfg.Job.Workers, cfg.Job.QueueSize, jobOpts...)
+		if err := jobManager.Start(context.Background()); err != nil {
+			fatal("Failed to start job manager", err)
+		}
+	}
+
+	var procedureScheduler *scheduler.Scheduler
+	if cfg.Features.Scheduler {
+		procedureScheduler, err = newScheduler(cfg.Scheduler, procedureService, conn)
+		if err != nil {
+			fatal("Failed to create scheduler", err)
+		}
+		procedureScheduler.Start()
+	}
+
+	trustedProxies, err := auth.ParseNetworks(cfg.Auth.TrustedProxies)
+	if err != nil {
+		fatal("Failed to parse trusted proxies", err)
+	}
+
+	procedures := policyProcedures(dataSources)
+	r := setupRouter(services{
+		procedure:   procedureService,
+		job:         jobManager,
+		schedule:    procedureScheduler,
+		dbStats:     conn,
+		readiness:   newReadinessChecker(cfg.Health, conns, cfg.DefaultDatasource),
+		audit:       auditSink,
+		principal:   auth.Middleware(cfg.Auth.PrincipalHeader, trustedProxies),
+		realIP:      auth.RealIP(trustedProxies),
+		features:    cfg.Features,
+		idempotency: idempotency.Middleware(idempotency.NewMemoryStore(), cfg.Idempotency.TTL),
+		tenant:      newTenantMiddleware(cfg.Tenants),
+		openapi:     newOpenAPIGenerator(procedures, procedureService, cfg.DefaultDatasource),
+		rest:        handler.NewRestHandler(procedureService, procedures, cfg.DefaultDatasource),
+		endpoints:   cfg.Endpoints,
+		metrics:     promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
+		timeout:     cfg.Server.RequestTimeout,
+	})
 
 	server := &http.Server{
-		Addr:         cfg.Server.Port,
+		Addr:         cfg.Server.Addr(),
 		Handler:      r,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
 	go func() {
-		log.Println("Starting server on:", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatal("Server failed to start", "error", err)
+		slog.Info("Starting server", "addr", server.Addr, "tls", cfg.Server.TLSEnabled())
+		var err error
+		if cfg.Server.TLSEnabled() {
+			err = server.ListenAndServeTLS(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Server failed to start", err)
 		}
 	}()
 
@@ -62,38 +266,514 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Println("Server shutdown encountered an error", "error", err)
+		slog.Error("Server shutdown encountered an error", "error", err)
 		return
 	}
 
-	log.Println("Server stopped")
+	if procedureScheduler != nil {
+		if err := procedureScheduler.Stop(ctx); err != nil {
+			slog.Error("Scheduler shutdown encountered an error", "error", err)
+			return
+		}
+	}
+
+	if jobManager != nil {
+		if err := jobManager.Shutdown(ctx); err != nil {
+			slog.Error("Job manager shutdown encountered an error", "error", err)
+			return
+		}
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Tracing shutdown encountered an error", "error", err)
+	}
+
+	slog.Info("Server stopped")
+}
+
+// fatal logs err and exits. Deferred cleanups do not run, as with log.Fatal.
+func fatal(message string, err error) {
+	slog.Error(message, "error", err)
+	os.Exit(1)
+}
+
+func connect(cfg *config.OracleDatabase) (*sql.DB, error) {
+	dsn, err := cfg.DSN()
+	if err != nil {
+		return nil, err
+	}
+	return database.NewOracleDatabase(database.PoolOptions{
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.ConnMaxIdleTime,
+	}).Connect(dsn)
+}
+
+// node is one database of a data source: its shared pool and, when sessions are enabled,
+// the pools of individual users.
+type node struct {
+	conn     *sql.DB
+	sessions *session.Pool
+}
+
+// newProcedureService builds the repository chain of one data source, from the outside in:
+// audit, rate limits, the response cache, then the primary and, when standby is set, the
+// replica, each with its own retries, circuit breaker and metrics. Procedure names in the
+// policy are matched on the object they resolve to in the primary, so that synonyms of a
+// listed procedure get its limits, retries, routing and caching.
+func newProcedureService(cfg *config.Config, policy *config.Policy, primary node, standby *node, m *metrics.Metrics, auditSink audit.Sink, limiter *ratelimit.Limiter) (*service.ProcedureService, error) {
+	redactor, err := newRedactor(policy)
+	if err != nil {
+		return nil, fmt.Errorf("configure redaction: %w", err)
+	}
+
+	base := newOracleRepository(cfg, primary, redactor)
+	resolver := service.NewNameResolver(base,
+		cache.NewLRU[string](cfg.Cache.MaxEntries, int64(cfg.Cache.MaxBytes)),
+		cfg.Cache.MetadataTTL,
+	)
+	idempotent := service.NewNames(resolver, policy.IdempotentProcedures())
+
+	repo := newNodeRepository(cfg, base, m, idempotent)
+	if standby != nil {
+		repo = replica.NewRepository(repo,
+			newNodeRepository(cfg, newOracleRepository(cfg, *standby, redactor), m, idempotent),
+			service.NewNames(resolver, policy.ReadOnlyProcedures()),
+		)
+	}
+	cached := service.NewNames(resolver, slices.Collect(maps.Keys(policy.CacheTTLs())))
+	repo = service.NewCachingRepository(repo,
+		cache.NewLRU[response.CallProcedureResponse](cfg.Cache.MaxEntries, int64(cfg.Cache.MaxBytes)),
+		policy.CacheTTLs(),
+		cached,
+		cfg.Sessions.Enabled(),
+	)
+	exposed := service.NewNames(resolver, slices.Collect(maps.Keys(policy.Procedures)))
+	repo = ratelimit.NewRepository(repo, limiter, exposed)
+	if auditSink != nil {
+		repo = audit.NewRepository(repo, auditSink, redactor)
+	}
+
+	opts := []service.Option{service.WithCacheTTLs(policy.CacheTTLs(), cached), service.WithCatalog(exposed)}
+	if cfg.Cache.MetadataTTL > 0 {
+		opts = append(opts, service.WithMetadataCache(
+			cache.NewLRU[*response.GetProcedureInfoResponse](cfg.Cache.MaxEntries, int64(cfg.Cache.MaxBytes)),
+			cfg.Cache.MetadataTTL,
+		))
+	}
+	return service.NewProcedureService(repo, opts...), nil
+}
+
+// newOracleRepository returns the repository of one database node, scoped to the tenant
+// schema when tenants are enabled.
+func newOracleRepository(cfg *config.Config, n node, redactor *redact.Policy) service.Repository {
+	opts := []repository.Option{repository.WithRedactor(redactor)}
+	if n.sessions != nil {
+		opts = append(opts, repository.WithSessions(n.sessions))
+	}
+	if cfg.Tenants.Enabled() {
+		opts = append(opts, repository.WithCurrentSchema(tenant.SchemaFrom), repository.WithTargetCheck(tenant.TargetCheck(cfg.Tenants.SchemaNames())))
+		return tenant.NewRepository(repository.NewOracleRepository(n.conn, opts...), tenant.Mode(cfg.Tenants.Mode), cfg.Tenants.SchemaNames())
+	}
+	return repository.NewOracleRepository(n.conn, opts...)
+}
+
+func newNodeRepository(cfg *config.Config, repo service.Repository, m *metrics.Metrics, idempotent *service.Names) service.Repository {
+	return resilience.NewRepository(
+		metrics.NewRepository(repo, m),
+		resilience.NewBreaker(resilience.BreakerOptions{
+			FailureThreshold: cfg.Resilience.BreakerFailureThreshold,
+			OpenTimeout:      cfg.Resilience.BreakerOpenTimeout,
+		}),
+		resilience.RetryOptions{
+			MaxAttempts: cfg.Resilience.RetryMaxAttempts,
+			BaseDelay:   cfg.Resilience.RetryBaseDelay,
+			MaxDelay:    cfg.Resilience.RetryMaxDelay,
+		},
+		idempotent,
+	)
+}
+
+// newTenantMiddleware resolves the tenant of requests, or passes them through when tenants
+// are disabled.
+func newTenantMiddleware(cfg *config.Tenants) func(http.Handler) http.Handler {
+	if !cfg.Enabled() {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return tenant.Middleware(tenant.Config{
+		Source:               tenant.Source(cfg.Source),
+		Header:               cfg.Header,
+		Claim:                cfg.Claim,
+		JWTSecret:            []byte(cfg.JWTSecret),
+		TrustGatewayVerified: cfg.TrustGatewayVerified,
+		Domain:               cfg.Domain,
+		Required:             cfg.Required,
+		Schemas:              cfg.Schemas,
+	})
+}
+
+// policyProcedures lists the procedures named in the policy of each data source, with
+// whether each is read-only, under their upper-case names.
+func policyProcedures(dataSources map[string]*config.Datasource) map[string]map[string]bool {
+	procedures := make(map[string]map[string]bool, len(dataSources))
+	for name, ds := range dataSources {
+		procedures[name] = make(map[string]bool, len(ds.Policy.Procedures))
+		for procedure, policy := range ds.Policy.Procedures {
+			procedures[name][strings.ToUpper(strings.TrimSpace(procedure))] = policy.ReadOnly
+		}
+	}
+	return procedures
+}
+
+// newOpenAPIGenerator documents the procedures named in the policy of each data source.
+func newOpenAPIGenerator(procedures map[string]map[string]bool, svc openapi.Service, fallback string) *openapi.Generator {
+	documented := make(map[string][]openapi.Procedure, len(procedures))
+	for name, readOnly := range procedures {
+		names := make([]string, 0, len(readOnly))
+		for procedure := range readOnly {
+			names = append(names, procedure)
+		}
+		sort.Strings(names)
+
+		documented[name] = make([]openapi.Procedure, 0, len(names))
+		for _, procedure := range names {
+			documented[name] = append(documented[name], openapi.Procedure{Name: procedure, ReadOnly: readOnly[procedure]})
+		}
+	}
+	return openapi.NewGenerator(svc, documented, fallback)
 }
 
-func setupRouter(conn *sql.DB) *chi.Mux {
+// newEndpointHandler serves a route of the endpoint mapping.
+func newEndpointHandler(svc handler.ProcedureService, e config.Endpoint, endpoints *config.Endpoints) *handler.EndpointHandler {
+	params := make([]handler.EndpointParam, 0, len(e.Params))
+	for _, p := range e.Params {
+		params = append(params, handler.EndpointParam{
+			Name:  p.Name,
+			From:  handler.ParamSource(p.From),
+			Key:   p.SourceKey(),
+			Value: p.Value,
+		})
+	}
+	return handler.NewEndpointHandler(svc, handler.Endpoint{
+		Procedure:            e.Procedure,
+		Datasource:           e.Datasource,
+		Params:               params,
+		Outputs:              e.Outputs,
+		Cursor:               e.Cursor,
+		Status:               e.Status,
+		JWTSecret:            []byte(endpoints.JWTSecret),
+		TrustGatewayVerified: endpoints.TrustGatewayVerified,
+	})
+}
+
+// newSessionPool opens per-user pools to db, as proxy sessions through db's user or with
+// the user's own credentials. Pools are not pinged when opened, so a failed login is
+// reported on the call that needed it.
+func newSessionPool(cfg *config.Sessions, db *config.OracleDatabase) *session.Pool {
+	credentials := make(map[string]session.Credential, len(cfg.Credentials))
+	for principal, c := range cfg.Credentials {
+		credentials[principal] = session.Credential{User: c.User, Password: c.Password}
+	}
+
+	return session.New(session.Config{
+		Mode:          session.Mode(cfg.Mode),
+		Required:      cfg.Required,
+		Users:         cfg.Users,
+		AllowUnmapped: cfg.AllowUnmapped,
+		Credentials:   credentials,
+		MaxUsers:      cfg.MaxUsers,
+		IdleTimeout:   cfg.IdleTimeout,
+	}, func(id session.Identity) (*sql.DB, error) {
+		target := db.ProxyFor(id.ProxyUser)
+		if id.ProxyUser == "" {
+			target = db.As(config.Credential{User: id.User, Password: id.Password})
+		}
+		dsn, err := target.DSN()
+		if err != nil {
+			return nil, err
+		}
+		return database.NewOracleDatabase(database.PoolOptions{
+			MaxOpenConns:    cfg.MaxOpenConnsPerUser,
+			MaxIdleConns:    cfg.MaxOpenConnsPerUser,
+			ConnMaxLifetime: db.ConnMaxLifetime,
+			ConnMaxIdleTime: db.ConnMaxIdleTime,
+		}).Open(dsn)
+	})
+}
+
+func closeSessionPool(name string, p *session.Pool) {
+	if err := p.Close(); err != nil {
+		slog.Error("Session pool close encountered an error", "datasource", name, "error", err)
+	}
+}
+
+func newRedactor(policy *config.Policy) (*redact.Policy, error) {
+	patterns := policy.Redaction.Patterns
+	if len(patterns) == 0 {
+		patterns = redact.DefaultPatterns
+	}
+
+	procedures := make(map[string][]string, len(policy.Procedures))
+	for name, p := range policy.Procedures {
+		procedures[name] = p.SensitiveParams
+	}
+
+	return redact.New(redact.Config{
+		Mode:       redact.Mode(policy.Redaction.Mode),
+		Patterns:   patterns,
+		HashKey:    policy.Redaction.HashKey,
+		Procedures: procedures,
+	})
+}
+
+// newLimiter builds the principal limits, which the top-level policy sets for every data
+// source, and the procedure limits of that policy. Data sources with a policy of their
+// own get a limiter scoped from it.
+func newLimiter(policy *config.Policy) *ratelimit.Limiter {
+	principals := make(map[string]ratelimit.Rule, len(policy.RateLimits.Principals))
+	for name, limit := range policy.RateLimits.Principals {
+		principals[name] = ratelimit.Rule(limit)
+	}
+
+	return ratelimit.New(ratelimit.Config{
+		PerPrincipal: ratelimit.Rule(policy.RateLimits.PerPrincipal),
+		Principals:   principals,
+		Procedures:   procedureRules(policy),
+	})
+}
+
+func procedureRules(policy *config.Policy) map[string]ratelimit.ProcedureRule {
+	procedures := make(map[string]ratelimit.ProcedureRule, len(policy.Procedures))
+	for name, p := range policy.Procedures {
+		procedures[name] = ratelimit.ProcedureRule{
+			Rule:          ratelimit.Rule(p.RateLimit),
+			MaxConcurrent: p.MaxConcurrent,
+		}
+	}
+	return procedures
+}
+
+func newJobStore(cfg *config.Job) (job.Store, error) {
+	switch cfg.Store {
+	case "memory":
+		return job.NewMemoryStore(job.StoreOptions{Retention: cfg.Retention, MaxFinished: cfg.MaxFinished}), nil
+	case "bolt":
+		return job.NewBoltStore(cfg.StorePath, job.StoreOptions{Retention: cfg.Retention, MaxFinished: cfg.MaxFinished})
+	default:
+		return nil, fmt.Errorf("unknown job store: %s", cfg.Store)
+	}
+}
+
+// newAuditSink returns nil when auditing is disabled.
+func newAuditSink(cfg *config.Audit, conn *sql.DB) (audit.Sink, error) {
+	switch cfg.Sink {
+	case "file":
+		return audit.NewFileSink(cfg.FilePath)
+	case "oracle":
+		return audit.NewOracleSink(conn, audit.OracleOptions{
+			Table:         cfg.Table,
+			BatchSize:     cfg.BatchSize,
+			FlushInterval: cfg.FlushInterval,
+			BufferSize:    cfg.BufferSize,
+		})
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown audit sink: %s", cfg.Sink)
+	}
+}
+
+func newScheduler(cfg *config.Scheduler, executor scheduler.Executor, conn *sql.DB) (*scheduler.Scheduler, error) {
+	var defs []scheduler.Definition
+	if cfg.File != "" {
+		var err error
+		defs, err = scheduler.LoadFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var locker scheduler.Locker
+	switch cfg.Lock {
+	case "dbms_lock":
+		locker = scheduler.NewOracleLocker(conn, cfg.LockPrefix)
+	case "none":
+		locker = scheduler.NoopLocker{}
+	default:
+		return nil, fmt.Errorf("unknown scheduler lock: %s", cfg.Lock)
+	}
+
+	opts := scheduler.Options{
+		LockHold:    cfg.LockHold,
+		HistorySize: cfg.HistorySize,
+		Replicated:  cfg.Lock == "dbms_lock",
+	}
+	if cfg.State == "oracle" {
+		state, err := scheduler.NewOracleState(conn, cfg.StateTable, cfg.RunsTable)
+		if err != nil {
+			return nil, err
+		}
+		opts.State = state
+	}
+	return scheduler.New(executor, locker, defs, opts)
+}
+
+// newReadinessChecker pings every data source. The configured procedure checks run against
+// the default one. With several data sources, check names are prefixed with theirs.
+func newReadinessChecker(cfg *config.Health, conns map[string]*sql.DB, defaultName string) *health.Checker {
+	names := make([]string, 0, len(conns))
+	for name := range conns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var checks []health.Check
+	for _, name := range names {
+		conn := conns[name]
+		dsChecks := []health.Check{health.PingCheck(conn), health.QueryCheck(conn)}
+		if name == defaultName {
+			for _, procedure := range cfg.CheckProcedures {
+				dsChecks = append(dsChecks, health.ProcedureCheck(conn, procedure))
+			}
+		}
+		if len(conns) > 1 {
+			for i := range dsChecks {
+				dsChecks[i].Name = name + "." + dsChecks[i].Name
+			}
+		}
+		checks = append(checks, dsChecks...)
+	}
+	return health.NewChecker(cfg.Timeout, checks...)
+}
+
+type services struct {
+	procedure   handler.ProcedureService
+	job         handler.JobService
+	schedule    handler.ScheduleService
+	dbStats     handler.DBStatsProvider
+	readiness   handler.ReadinessChecker
+	audit       handler.AuditQuerier
+	principal   func(http.Handler) http.Handler
+	realIP      func(http.Handler) http.Handler
+	features    *config.Features
+	idempotency func(http.Handler) http.Handler
+	tenant      func(http.Handler) http.Handler
+	openapi     handler.OpenAPIGenerator
+	rest        *handler.RestHandler
+	endpoints   *config.Endpoints
+	metrics     http.Handler
+	timeout     time.Duration
+}
+
+func setupRouter(s services) *chi.Mux {
 	r := chi.NewRouter()
 
-	r.Use(middleware.Logger)
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(logger.Middleware)
+	r.Use(tracing.Middleware)
+	// The principal is read before RealIP rewrites the peer address it is checked against.
+	r.Use(s.principal)
+	r.Use(s.realIP)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(middleware.Timeout(s.timeout))
 	r.Use(middleware.Heartbeat("/health"))
 
+	if s.features.Metrics {
+		r.Method(http.MethodGet, "/metrics", s.metrics)
+	}
+
+	r.Route("/health", func(r chi.Router) {
+		healthHandler := handler.NewHealthHandler(s.readiness)
+		r.Get("/live", healthHandler.Live)
+		r.Get("/ready", healthHandler.Ready)
+	})
+
+	if s.features.Admin {
+		r.Route("/admin", func(r chi.Router) {
+			adminHandler := handler.NewAdminHandler(s.dbStats)
+			r.Get("/db/stats", adminHandler.GetDBStats)
+			if s.audit != nil {
+				auditHandler := handler.NewAuditHandler(s.audit)
+				r.Get("/audit", auditHandler.GetAuditRecords)
+			}
+		})
+	}
+
+	if len(s.endpoints.Routes) > 0 {
+		r.Group(func(r chi.Router) {
+			r.Use(s.tenant)
+			r.Use(replica.Middleware)
+			for _, e := range s.endpoints.Routes {
+				h := newEndpointHandler(s.procedure, e, s.endpoints)
+				method := strings.ToUpper(e.Method)
+				if method == http.MethodGet {
+					r.Method(method, e.Path, h)
+				} else {
+					r.With(s.idempotency).Method(method, e.Path, h)
+				}
+			}
+		})
+	}
+
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
-			r.Route("/procedures", func(r chi.Router) {
-				oracleRepository := repository.NewOracleRepository(conn)
-				procedureService := service.NewProcedureService(oracleRepository)
-				procedureHandler := handler.NewProcedureHandler(procedureService)
-				r.Post("/call", procedureHandler.CallProcedure)
+			procedureRoutes := func(r chi.Router) {
+				r.Use(s.tenant)
+				r.Use(replica.Middleware)
+				procedureHandler := handler.NewProcedureHandler(s.procedure)
+				r.Get("/", procedureHandler.ListProcedures)
+				r.With(s.idempotency).Post("/call", procedureHandler.CallProcedure)
 				r.Get("/info", procedureHandler.GetProcedureInfo)
-			})
+			}
+			r.Route("/procedures", procedureRoutes)
+			r.Route("/{ds}/procedures", procedureRoutes)
+			restRoutes := func(r chi.Router) {
+				r.Use(s.tenant)
+				r.Use(replica.Middleware)
+				for _, path := range []string{"/{schema}/{proc}", "/{schema}/{package}/{proc}"} {
+					r.Get(path, s.rest.Call)
+					r.With(s.idempotency).Post(path, s.rest.Call)
+				}
+			}
+			r.Route("/p", restRoutes)
+			r.Route("/{ds}/p", restRoutes)
+			if s.features.OpenAPI {
+				openAPIHandler := handler.NewOpenAPIHandler(s.openapi)
+				for _, prefix := range []string{"", "/{ds}"} {
+					r.With(s.tenant).Get(prefix+"/openapi.json", openAPIHandler.Spec)
+					r.Get(prefix+"/docs", openAPIHandler.UI)
+				}
+			}
+			if s.features.Jobs {
+				r.Route("/jobs", func(r chi.Router) {
+					r.Use(s.tenant)
+					jobHandler := handler.NewJobHandler(s.job)
+					r.Post("/", jobHandler.CreateJob)
+					r.Get("/{id}", jobHandler.GetJob)
+					r.Delete("/{id}", jobHandler.CancelJob)
+				})
+			}
+			if s.features.Scheduler {
+				r.Route("/schedules", func(r chi.Router) {
+					scheduleHandler := handler.NewScheduleHandler(s.schedule)
+					r.Get("/", scheduleHandler.ListSchedules)
+					r.Get("/{name}", scheduleHandler.GetSchedule)
+					// Running and pausing schedules is administration, off with the admin
+					// routes.
+					if s.features.Admin {
+						r.Post("/{name}/run", scheduleHandler.TriggerSchedule)
+						r.Post("/{name}/pause", scheduleHandler.PauseSchedule)
+						r.Post("/{name}/resume", scheduleHandler.ResumeSchedule)
+					}
+				})
+			}
 		})
 	})
 
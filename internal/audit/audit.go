@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"oracle-golang/internal/model/request"
+	"strings"
+	"time"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeError   Outcome = "error"
+)
+
+// Record describes a single procedure invocation. Params are redacted before the record
+// reaches a sink.
+type Record struct {
+	Time       time.Time                `json:"time"`
+	Principal  string                   `json:"principal,omitempty"`
+	RequestID  string                   `json:"request_id,omitempty"`
+	Procedure  string                   `json:"procedure"`
+	Params     []request.ProcedureParam `json:"params"`
+	Outcome    Outcome                  `json:"outcome"`
+	DurationMs int64                    `json:"duration_ms"`
+	ErrorCode  string                   `json:"error_code,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+// Filter selects records for Query. Zero fields match everything.
+type Filter struct {
+	Procedure string
+	Principal string
+	Outcome   Outcome
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+func (f Filter) Match(r Record) bool {
+	if f.Procedure != "" && !strings.EqualFold(f.Procedure, r.Procedure) {
+		return false
+	}
+	if f.Principal != "" && f.Principal != r.Principal {
+		return false
+	}
+	if f.Outcome != "" && f.Outcome != r.Outcome {
+		return false
+	}
+	if !f.From.IsZero() && r.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !r.Time.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// Sink stores audit records. Query returns the newest records first.
+type Sink interface {
+	Write(ctx context.Context, r Record) error
+	Query(ctx context.Context, f Filter) ([]Record, error)
+	Close() error
+}
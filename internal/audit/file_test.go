@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink_Query(t *testing.T) {
+	sink, err := NewFileSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Time: base, Principal: "alice", Procedure: "pkg.pay", Outcome: OutcomeSuccess},
+		{Time: base.Add(time.Minute), Principal: "bob", Procedure: "pkg.pay", Outcome: OutcomeError, ErrorCode: "ORA-01403"},
+		{Time: base.Add(2 * time.Minute), Principal: "alice", Procedure: "pkg.refund", Outcome: OutcomeSuccess},
+		{Time: base.Add(3 * time.Minute), Principal: "alice", Procedure: "PKG.PAY", Outcome: OutcomeSuccess},
+	}
+	for _, r := range records {
+		require.NoError(t, sink.Write(context.Background(), r))
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []time.Time
+	}{
+		{
+			name:     "no filter returns newest first",
+			filter:   Filter{},
+			expected: []time.Time{base.Add(3 * time.Minute), base.Add(2 * time.Minute), base.Add(time.Minute), base},
+		},
+		{
+			name:     "procedure is case-insensitive",
+			filter:   Filter{Procedure: "pkg.pay", Principal: "alice"},
+			expected: []time.Time{base.Add(3 * time.Minute), base},
+		},
+		{
+			name:     "outcome",
+			filter:   Filter{Outcome: OutcomeError},
+			expected: []time.Time{base.Add(time.Minute)},
+		},
+		{
+			name:     "time range includes from and excludes to",
+			filter:   Filter{From: base.Add(time.Minute), To: base.Add(3 * time.Minute)},
+			expected: []time.Time{base.Add(2 * time.Minute), base.Add(time.Minute)},
+		},
+		{
+			name:     "limit keeps the newest",
+			filter:   Filter{Limit: 2},
+			expected: []time.Time{base.Add(3 * time.Minute), base.Add(2 * time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := sink.Query(context.Background(), tt.filter)
+			require.NoError(t, err)
+
+			times := make([]time.Time, 0, len(result))
+			for _, r := range result {
+				times = append(times, r.Time.UTC())
+			}
+			assert.Equal(t, tt.expected, times)
+		})
+	}
+}
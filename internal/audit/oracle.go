@@ -0,0 +1,242 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrBufferFull = errors.New("audit buffer is full")
+
+var tableName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*(\.[A-Za-z][A-Za-z0-9_$#]*)?$`)
+
+type OracleOptions struct {
+	Table         string
+	BatchSize     int
+	FlushInterval time.Duration
+	BufferSize    int
+}
+
+// OracleSink writes records to a table in batches from a background goroutine, so a call
+// never waits for the insert. The table is expected to look like:
+//
+//	CREATE TABLE API_AUDIT_LOG (
+//	    EVENT_TIME     TIMESTAMP WITH TIME ZONE NOT NULL,
+//	    PRINCIPAL      VARCHAR2(256),
+//	    REQUEST_ID     VARCHAR2(128),
+//	    PROCEDURE_NAME VARCHAR2(512) NOT NULL,
+//	    PARAMS         CLOB,
+//	    OUTCOME        VARCHAR2(16) NOT NULL,
+//	    DURATION_MS    NUMBER NOT NULL,
+//	    ERROR_CODE     VARCHAR2(16),
+//	    ERROR_MESSAGE  VARCHAR2(4000)
+//	);
+//
+// Records still in the buffer do not show up in Query until they are flushed.
+type OracleSink struct {
+	db   *sql.DB
+	opts OracleOptions
+
+	mu      sync.RWMutex
+	closed  bool
+	records chan Record
+	done    chan struct{}
+}
+
+func NewOracleSink(db *sql.DB, opts OracleOptions) (*OracleSink, error) {
+	if !tableName.MatchString(opts.Table) {
+		return nil, fmt.Errorf("invalid audit table name: %q", opts.Table)
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	if opts.BufferSize < opts.BatchSize {
+		opts.BufferSize = opts.BatchSize
+	}
+
+	s := &OracleSink{
+		db:      db,
+		opts:    opts,
+		records: make(chan Record, opts.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go s.loop()
+	return s, nil
+}
+
+// Write queues r for the next batch. It returns ErrBufferFull instead of blocking when
+// the database cannot keep up.
+func (s *OracleSink) Write(_ context.Context, r Record) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return errors.New("audit sink is closed")
+	}
+	select {
+	case s.records <- r:
+		return nil
+	default:
+		return ErrBufferFull
+	}
+}
+
+func (s *OracleSink) Query(ctx context.Context, f Filter) ([]Record, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Procedure != "" {
+		add("UPPER(PROCEDURE_NAME) = UPPER(:%d)", f.Procedure)
+	}
+	if f.Principal != "" {
+		add("PRINCIPAL = :%d", f.Principal)
+	}
+	if f.Outcome != "" {
+		add("OUTCOME = :%d", string(f.Outcome))
+	}
+	if !f.From.IsZero() {
+		add("EVENT_TIME >= :%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("EVENT_TIME < :%d", f.To)
+	}
+
+	query := "SELECT EVENT_TIME, PRINCIPAL, REQUEST_ID, PROCEDURE_NAME, PARAMS, OUTCOME, DURATION_MS, ERROR_CODE, ERROR_MESSAGE FROM " + s.opts.Table
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY EVENT_TIME DESC"
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" FETCH FIRST :%d ROWS ONLY", len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit records: %w", err)
+	}
+	defer rows.Close()
+
+	result := []Record{}
+	for rows.Next() {
+		var r Record
+		var principal, requestID, params, errorCode, errorMessage sql.NullString
+		var outcome string
+		if err := rows.Scan(&r.Time, &principal, &requestID, &r.Procedure, &params, &outcome, &r.DurationMs, &errorCode, &errorMessage); err != nil {
+			return nil, fmt.Errorf("scan audit record: %w", err)
+		}
+		r.Principal = principal.String
+		r.RequestID = requestID.String
+		r.Outcome = Outcome(outcome)
+		r.ErrorCode = errorCode.String
+		r.Error = errorMessage.String
+		if params.Valid && params.String != "" {
+			if err := json.Unmarshal([]byte(params.String), &r.Params); err != nil {
+				return nil, fmt.Errorf("decode audit params: %w", err)
+			}
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// Close flushes the buffered records and stops the background goroutine.
+func (s *OracleSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.records)
+	s.mu.Unlock()
+
+	<-s.done
+	return nil
+}
+
+func (s *OracleSink) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, s.opts.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.insert(batch); err != nil {
+			slog.Error("failed to write audit records", "count", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case r, ok := <-s.records:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= s.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (s *OracleSink) insert(batch []Record) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO "+s.opts.Table+
+		" (EVENT_TIME, PRINCIPAL, REQUEST_ID, PROCEDURE_NAME, PARAMS, OUTCOME, DURATION_MS, ERROR_CODE, ERROR_MESSAGE)"+
+		" VALUES (:1, :2, :3, :4, :5, :6, :7, :8, :9)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range batch {
+		params, err := json.Marshal(r.Params)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, r.Time, r.Principal, r.RequestID, r.Procedure, string(params),
+			string(r.Outcome), r.DurationMs, r.ErrorCode, truncate(r.Error, 4000)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
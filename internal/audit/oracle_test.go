@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOracleSink_WritesInBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sink, err := NewOracleSink(db, OracleOptions{Table: "API_AUDIT_LOG", BatchSize: 2, FlushInterval: time.Hour, BufferSize: 10})
+	require.NoError(t, err)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`INSERT INTO API_AUDIT_LOG`)
+	prepare.ExpectExec().
+		WithArgs(now, "alice", "req-1", "pkg.pay", `null`, "success", int64(5), "", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().
+		WithArgs(now, "bob", "req-2", "pkg.pay", `null`, "error", int64(7), "ORA-01403", "no data found").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare(`INSERT INTO API_AUDIT_LOG`).ExpectExec().
+		WithArgs(now, "carol", "req-3", "pkg.refund", `null`, "success", int64(1), "", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx := context.Background()
+	require.NoError(t, sink.Write(ctx, Record{Time: now, Principal: "alice", RequestID: "req-1", Procedure: "pkg.pay", Outcome: OutcomeSuccess, DurationMs: 5}))
+	require.NoError(t, sink.Write(ctx, Record{Time: now, Principal: "bob", RequestID: "req-2", Procedure: "pkg.pay", Outcome: OutcomeError, DurationMs: 7, ErrorCode: "ORA-01403", Error: "no data found"}))
+	require.NoError(t, sink.Write(ctx, Record{Time: now, Principal: "carol", RequestID: "req-3", Procedure: "pkg.refund", Outcome: OutcomeSuccess, DurationMs: 1}))
+
+	// Close flushes the last, incomplete batch.
+	require.NoError(t, sink.Close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Error(t, sink.Write(ctx, Record{}))
+}
+
+func TestOracleSink_BufferFull(t *testing.T) {
+	// Without the background goroutine nothing drains the buffer.
+	sink := &OracleSink{records: make(chan Record, 1), done: make(chan struct{})}
+
+	require.NoError(t, sink.Write(context.Background(), Record{}))
+	assert.ErrorIs(t, sink.Write(context.Background(), Record{}), ErrBufferFull)
+}
+
+func TestOracleSink_Query(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	sink, err := NewOracleSink(db, OracleOptions{Table: "AUDIT.API_AUDIT_LOG"})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"EVENT_TIME", "PRINCIPAL", "REQUEST_ID", "PROCEDURE_NAME", "PARAMS", "OUTCOME", "DURATION_MS", "ERROR_CODE", "ERROR_MESSAGE"}).
+		AddRow(from, "alice", nil, "pkg.pay", `[{"name":"p_pin","type":"VARCHAR2","value":"***","direction":"IN"}]`, "error", 12, "ORA-01403", "no data found")
+	mock.ExpectQuery(`SELECT .* FROM AUDIT\.API_AUDIT_LOG WHERE UPPER\(PROCEDURE_NAME\) = UPPER\(:1\) AND OUTCOME = :2 AND EVENT_TIME >= :3 ORDER BY EVENT_TIME DESC FETCH FIRST :4 ROWS ONLY`).
+		WithArgs("pkg.pay", "error", from, 10).
+		WillReturnRows(rows)
+
+	result, err := sink.Query(context.Background(), Filter{Procedure: "pkg.pay", Outcome: OutcomeError, From: from, Limit: 10})
+
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "alice", result[0].Principal)
+	assert.Equal(t, "ORA-01403", result[0].ErrorCode)
+	require.Len(t, result[0].Params, 1)
+	assert.Equal(t, "***", result[0].Params[0].Value)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewOracleSink_InvalidTable(t *testing.T) {
+	_, err := NewOracleSink(nil, OracleOptions{Table: "LOG; DROP TABLE X"})
+	assert.Error(t, err)
+}
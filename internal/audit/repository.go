@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/redact"
+	"oracle-golang/internal/service"
+	"oracle-golang/pkg/util"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Repository records every procedure call made through a service.Repository.
+type Repository struct {
+	next     service.Repository
+	sink     Sink
+	redactor *redact.Policy
+	now      func() time.Time
+}
+
+func NewRepository(next service.Repository, sink Sink, redactor *redact.Policy) *Repository {
+	return &Repository{next: next, sink: sink, redactor: redactor, now: time.Now}
+}
+
+func (r *Repository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	start := r.now()
+	result, err := r.next.CallProcedure(ctx, name, params)
+
+	record := Record{
+		Time:       start,
+		Principal:  auth.PrincipalFrom(ctx),
+		RequestID:  middleware.GetReqID(ctx),
+		Procedure:  name,
+		Params:     r.redactor.Params(name, params),
+		Outcome:    OutcomeSuccess,
+		DurationMs: r.now().Sub(start).Milliseconds(),
+	}
+	if err != nil {
+		record.Outcome = OutcomeError
+		record.ErrorCode = util.OraErrorCode(err)
+		record.Error = err.Error()
+	}
+
+	if werr := r.sink.Write(context.WithoutCancel(ctx), record); werr != nil {
+		slog.ErrorContext(ctx, "failed to write audit record", "error", werr)
+	}
+	return result, err
+}
+
+func (r *Repository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	return r.next.GetProcedureInfo(ctx, procedureName)
+}
+
+func (r *Repository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return r.next.ListProcedures(ctx, filter)
+}
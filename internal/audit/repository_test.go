@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/redact"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	result map[string]any
+	err    error
+}
+
+func (f *fakeRepository) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	return f.result, f.err
+}
+
+func (f *fakeRepository) GetProcedureInfo(context.Context, string) (*response.GetProcedureInfoResponse, error) {
+	return nil, f.err
+}
+
+func (f *fakeRepository) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{}, f.err
+}
+
+type memorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (s *memorySink) Write(_ context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *memorySink) Query(context.Context, Filter) ([]Record, error) {
+	return nil, nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func TestRepository_CallProcedure(t *testing.T) {
+	tests := []struct {
+		name              string
+		repo              *fakeRepository
+		expectedOutcome   Outcome
+		expectedErrorCode string
+	}{
+		{
+			name:            "success",
+			repo:            &fakeRepository{result: map[string]any{"p_id": 1}},
+			expectedOutcome: OutcomeSuccess,
+		},
+		{
+			name:              "oracle error",
+			repo:              &fakeRepository{err: errors.New("execution failed for procedure 'pkg.pay': ORA-20001: insufficient funds")},
+			expectedOutcome:   OutcomeError,
+			expectedErrorCode: "ORA-20001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &memorySink{}
+			repo := NewRepository(tt.repo, sink, redact.Default())
+			start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			calls := 0
+			repo.now = func() time.Time {
+				calls++
+				return start.Add(time.Duration(calls-1) * 250 * time.Millisecond)
+			}
+
+			ctx := auth.WithPrincipal(context.Background(), "alice")
+			ctx = context.WithValue(ctx, middleware.RequestIDKey, "req-1")
+			_, err := repo.CallProcedure(ctx, "pkg.pay", []request.ProcedureParam{
+				{Name: "p_amount", Type: "NUMBER", Value: 100, Direction: "IN"},
+				{Name: "p_pin", Type: "VARCHAR2", Value: "1234", Direction: "IN"},
+			})
+			assert.Equal(t, tt.repo.err, err)
+
+			require.Len(t, sink.records, 1)
+			record := sink.records[0]
+			assert.Equal(t, start, record.Time)
+			assert.Equal(t, "alice", record.Principal)
+			assert.Equal(t, "req-1", record.RequestID)
+			assert.Equal(t, "pkg.pay", record.Procedure)
+			assert.Equal(t, tt.expectedOutcome, record.Outcome)
+			assert.Equal(t, int64(250), record.DurationMs)
+			assert.Equal(t, tt.expectedErrorCode, record.ErrorCode)
+			assert.Equal(t, 100, record.Params[0].Value)
+			assert.Equal(t, redact.Mask, record.Params[1].Value)
+		})
+	}
+}
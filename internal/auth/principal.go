@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"oracle-golang/internal/logger"
+	"strings"
+)
+
+type (
+	principalKey     struct{}
+	authenticatedKey struct{}
+	trustedPeerKey   struct{}
+)
+
+// WithPrincipal returns a copy of ctx carrying the caller's identity.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the caller's identity, or "" for internal callers such as the scheduler.
+func PrincipalFrom(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
+
+// WithAuthenticatedPrincipal is WithPrincipal for identities vouched for by the API gateway.
+func WithAuthenticatedPrincipal(ctx context.Context, principal string) context.Context {
+	return WithPrincipal(context.WithValue(ctx, authenticatedKey{}, true), principal)
+}
+
+// Authenticated reports whether the principal was passed by the API gateway rather than
+// derived from the caller's IP address.
+func Authenticated(ctx context.Context) bool {
+	authenticated, _ := ctx.Value(authenticatedKey{}).(bool)
+	return authenticated
+}
+
+// Middleware identifies the caller by the given header, which the API gateway sets after
+// authenticating the request. The header is only taken from peers in trusted, so that
+// clients reaching the service directly cannot name themselves; it is ignored when trusted
+// is empty. Other callers are identified by their IP address, read from the forwarding
+// headers when the peer is trusted. Middleware must run before anything that rewrites
+// RemoteAddr.
+func Middleware(header string, trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ctx context.Context
+			principal := strings.TrimSpace(r.Header.Get(header))
+			if principal != "" && contains(trusted, remoteHost(r.RemoteAddr)) {
+				ctx = WithAuthenticatedPrincipal(r.Context(), principal)
+			} else {
+				principal = ClientIP(r, trusted)
+				ctx = WithPrincipal(r.Context(), principal)
+			}
+
+			ctx = logger.With(ctx, "principal", principal)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP returns the address of the client. Behind trusted proxies it is the last
+// X-Forwarded-For address not in trusted, or X-Real-IP; otherwise the peer address.
+func ClientIP(r *http.Request, trusted []*net.IPNet) string {
+	peer := remoteHost(r.RemoteAddr)
+	if !contains(trusted, peer) {
+		return peer
+	}
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			if !contains(trusted, hop) || i == 0 {
+				return hop
+			}
+		}
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return peer
+}
+
+// RealIP replaces RemoteAddr with the client address from ClientIP, so that forwarding
+// headers are only believed when a trusted proxy sent them. Whether the peer was trusted
+// is kept for FromTrustedProxy, as RemoteAddr no longer tells.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if contains(trusted, remoteHost(r.RemoteAddr)) {
+				r = r.WithContext(context.WithValue(r.Context(), trustedPeerKey{}, true))
+			}
+			r.RemoteAddr = ClientIP(r, trusted)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FromTrustedProxy reports whether the request came through one of the trusted proxies
+// given to RealIP, so that headers the API gateway sets can be believed.
+func FromTrustedProxy(ctx context.Context) bool {
+	trusted, _ := ctx.Value(trustedPeerKey{}).(bool)
+	return trusted
+}
+
+// ParseNetworks parses CIDR blocks such as 10.0.0.0/8. Single addresses are taken as
+// networks of one.
+func ParseNetworks(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q", cidr)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func contains(networks []*net.IPNet, host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
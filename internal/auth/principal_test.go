@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	trusted, err := ParseNetworks([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		header        string
+		forwardedFor  string
+		remoteAddr    string
+		expected      string
+		authenticated bool
+	}{
+		{
+			name:          "principal from a trusted proxy",
+			header:        " alice ",
+			remoteAddr:    "10.0.0.1:51234",
+			expected:      "alice",
+			authenticated: true,
+		},
+		{
+			name:          "principal from a trusted address",
+			header:        "alice",
+			remoteAddr:    "192.168.1.1:51234",
+			expected:      "alice",
+			authenticated: true,
+		},
+		{
+			name:       "principal from an untrusted peer is ignored",
+			header:     "alice",
+			remoteAddr: "203.0.113.7:51234",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "falls back to remote address",
+			remoteAddr: "10.0.0.1:51234",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "remote address without port",
+			remoteAddr: "10.0.0.1",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:         "client address forwarded by a trusted proxy",
+			forwardedFor: "198.51.100.1, 203.0.113.9, 10.1.1.1",
+			remoteAddr:   "10.0.0.1:51234",
+			expected:     "203.0.113.9",
+		},
+		{
+			name:         "forwarded address from an untrusted peer is ignored",
+			forwardedFor: "198.51.100.1",
+			remoteAddr:   "203.0.113.7:51234",
+			expected:     "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal string
+			var authenticated bool
+			handler := Middleware("X-Principal", trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal = PrincipalFrom(r.Context())
+				authenticated = Authenticated(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.header != "" {
+				req.Header.Set("X-Principal", tt.header)
+			}
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expected, principal)
+			assert.Equal(t, tt.authenticated, authenticated)
+		})
+	}
+}
+
+func TestMiddleware_NoTrustedProxies(t *testing.T) {
+	var authenticated bool
+	handler := Middleware("X-Principal", nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated = Authenticated(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Principal", "alice")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.False(t, authenticated)
+}
+
+func TestRealIP(t *testing.T) {
+	trusted, err := ParseNetworks([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	var remoteAddr string
+	var viaProxy bool
+	handler := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+		viaProxy = FromTrustedProxy(r.Context())
+	}))
+	serve := func(peer string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = peer
+		req.Header.Set("X-Forwarded-For", "198.51.100.7")
+		req.Header.Set("X-Real-IP", "198.51.100.8")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return remoteAddr
+	}
+
+	assert.Equal(t, "198.51.100.7", serve("10.1.2.3:5000"))
+	assert.True(t, viaProxy)
+	// Clients reaching the service directly cannot pick the address they are limited by.
+	assert.Equal(t, "203.0.113.9", serve("203.0.113.9:5000"))
+	assert.False(t, viaProxy)
+}
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks([]string{"10.0.0.0/8", "::1"})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", networks[0].String())
+	assert.Equal(t, "::1/128", networks[1].String())
+
+	_, err = ParseNetworks([]string{"10.0.0.0/33"})
+	assert.EqualError(t, err, `invalid network "10.0.0.0/33"`)
+	_, err = ParseNetworks([]string{"gateway"})
+	assert.EqualError(t, err, `invalid address "gateway"`)
+}
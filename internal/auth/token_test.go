@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func token(t *testing.T, secret string, claims map[string]any) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestClaims(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		authorization string
+		secret        string
+		want          map[string]any
+		wantErr       string
+	}{
+		{name: "no token"},
+		{name: "basic auth", authorization: "Basic dXNlcjpwYXNz"},
+		{
+			name:          "signed token",
+			authorization: "Bearer " + token(t, "secret", map[string]any{"sub": "alice"}),
+			secret:        "secret",
+			want:          map[string]any{"sub": "alice"},
+		},
+		{
+			name:          "verified by the gateway",
+			authorization: "bearer " + token(t, "other", map[string]any{"sub": "alice"}),
+			want:          map[string]any{"sub": "alice"},
+		},
+		{
+			name:          "wrong key",
+			authorization: "Bearer " + token(t, "other", map[string]any{"sub": "alice"}),
+			secret:        "secret",
+			wantErr:       ErrInvalidToken.Error(),
+		},
+		{
+			name:          "expired",
+			authorization: "Bearer " + token(t, "secret", map[string]any{"exp": float64(now.Add(-time.Minute).Unix())}),
+			secret:        "secret",
+			wantErr:       "bearer token has expired",
+		},
+		{name: "malformed", authorization: "Bearer abc", wantErr: ErrInvalidToken.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := Claims(tt.authorization, []byte(tt.secret), now)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, claims)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	key       string
+	value     V
+	size      int64
+	expiresAt time.Time
+}
+
+// LRU is an in-memory cache bounded by entry count and total size. When either limit is
+// reached the least recently used entries are evicted. Expired entries are dropped on access.
+type LRU[V any] struct {
+	maxEntries int
+	maxBytes   int64
+	now        func() time.Time
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	bytes int64
+}
+
+func NewLRU[V any](maxEntries int, maxBytes int64) *LRU[V] {
+	return &LRU[V]{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		now:        time.Now,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := el.Value.(*entry[V])
+	if !c.now().Before(e.expiresAt) {
+		c.remove(el)
+		return zero, false
+	}
+
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value with its approximate size in bytes. Values larger than the whole cache
+// are not stored.
+func (c *LRU[V]) Set(key string, value V, size int64, ttl time.Duration) {
+	if ttl <= 0 || (c.maxBytes > 0 && size > c.maxBytes) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+
+	el := c.ll.PushFront(&entry[V]{key: key, value: value, size: size, expiresAt: c.now().Add(ttl)})
+	c.items[key] = el
+	c.bytes += size
+
+	for c.overLimit() {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *LRU[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRU[V]) overLimit() bool {
+	return (c.maxEntries > 0 && c.ll.Len() > c.maxEntries) ||
+		(c.maxBytes > 0 && c.bytes > c.maxBytes)
+}
+
+func (c *LRU[V]) remove(el *list.Element) {
+	e := el.Value.(*entry[V])
+	c.ll.Remove(el)
+	delete(c.items, e.key)
+	c.bytes -= e.size
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string](2, 0)
+
+	c.Set("a", "1", 1, time.Minute)
+	c.Set("b", "2", 1, time.Minute)
+	_, _ = c.Get("a")
+	c.Set("c", "3", 1, time.Minute)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok, "b was least recently used")
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_SizeLimit(t *testing.T) {
+	c := NewLRU[string](0, 100)
+
+	c.Set("a", "1", 60, time.Minute)
+	c.Set("b", "2", 60, time.Minute)
+	c.Set("huge", "3", 101, time.Minute)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("b")
+	assert.True(t, ok)
+	_, ok = c.Get("huge")
+	assert.False(t, ok, "values larger than the cache are not stored")
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU[string](10, 0)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", "1", 1, time.Minute)
+	c.Set("a", "2", 1, 2*time.Minute)
+
+	now = now.Add(90 * time.Second)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "2", v)
+
+	now = now.Add(30 * time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Audit struct {
+	Sink          string        `yaml:"sink"`
+	FilePath      string        `yaml:"file_path"`
+	Table         string        `yaml:"table"`
+	BatchSize     int           `yaml:"batch_size"`
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	BufferSize    int           `yaml:"buffer_size"`
+}
+
+func defaultAudit() *Audit {
+	return &Audit{
+		Sink:          "file",
+		FilePath:      "audit.jsonl",
+		Table:         "API_AUDIT_LOG",
+		BatchSize:     100,
+		FlushInterval: 2 * time.Second,
+		BufferSize:    10000,
+	}
+}
+
+func (a *Audit) applyEnv(e *env) {
+	e.string("AUDIT_SINK", &a.Sink)
+	e.string("AUDIT_FILE_PATH", &a.FilePath)
+	e.string("AUDIT_TABLE", &a.Table)
+	e.int("AUDIT_BATCH_SIZE", &a.BatchSize)
+	e.duration("AUDIT_FLUSH_INTERVAL", &a.FlushInterval)
+	e.int("AUDIT_BUFFER_SIZE", &a.BufferSize)
+}
+
+func (a *Audit) validate() error {
+	return errors.Join(
+		check(oneOf(a.Sink, "file", "oracle", "none"), "audit.sink", "must be file, oracle or none, got %q", a.Sink),
+		check(a.Sink != "file" || a.FilePath != "", "audit.file_path", "is required for the file sink"),
+		check(a.BatchSize > 0, "audit.batch_size", "must be positive"),
+		check(a.FlushInterval > 0, "audit.flush_interval", "must be positive"),
+		check(a.BufferSize >= a.BatchSize, "audit.buffer_size", "must not be less than batch_size"),
+	)
+}
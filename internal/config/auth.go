@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+type Auth struct {
+	// PrincipalHeader names the header the API gateway uses to pass the authenticated caller.
+	PrincipalHeader string `yaml:"principal_header"`
+	// TrustedProxies lists the networks of the API gateway, as CIDR blocks or addresses.
+	// PrincipalHeader and the forwarding headers are only read from these peers; without
+	// them every caller is identified by its own address.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+func defaultAuth() *Auth {
+	return &Auth{
+		PrincipalHeader: "X-Principal",
+	}
+}
+
+func (a *Auth) applyEnv(e *env) {
+	e.string("AUTH_PRINCIPAL_HEADER", &a.PrincipalHeader)
+	e.list("AUTH_TRUSTED_PROXIES", &a.TrustedProxies)
+}
+
+// validate requires trusted proxies when database sessions depend on the principal.
+func (a *Auth) validate(sessions bool) error {
+	errs := []error{
+		check(!sessions || len(a.TrustedProxies) > 0, "auth.trusted_proxies", "is required when sessions are enabled, as principals are only accepted from the API gateway"),
+	}
+	for _, proxy := range a.TrustedProxies {
+		var valid bool
+		if strings.Contains(proxy, "/") {
+			_, _, err := net.ParseCIDR(proxy)
+			valid = err == nil
+		} else {
+			valid = net.ParseIP(proxy) != nil
+		}
+		errs = append(errs, check(valid, "auth.trusted_proxies", "%q is not a CIDR block or IP address", proxy))
+	}
+	return errors.Join(errs...)
+}
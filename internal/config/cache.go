@@ -0,0 +1,36 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Cache struct {
+	MaxEntries int `yaml:"max_entries"`
+	MaxBytes   int `yaml:"max_bytes"`
+	// MetadataTTL is how long procedure metadata is kept, per data source and tenant.
+	// Zero looks it up on every request.
+	MetadataTTL time.Duration `yaml:"metadata_ttl"`
+}
+
+func defaultCache() *Cache {
+	return &Cache{
+		MaxEntries:  10000,
+		MaxBytes:    64 << 20,
+		MetadataTTL: time.Minute,
+	}
+}
+
+func (c *Cache) applyEnv(e *env) {
+	e.int("CACHE_MAX_ENTRIES", &c.MaxEntries)
+	e.int("CACHE_MAX_BYTES", &c.MaxBytes)
+	e.duration("CACHE_METADATA_TTL", &c.MetadataTTL)
+}
+
+func (c *Cache) validate() error {
+	return errors.Join(
+		check(c.MaxEntries >= 0, "cache.max_entries", "must not be negative"),
+		check(c.MaxBytes >= 0, "cache.max_bytes", "must not be negative"),
+		check(c.MetadataTTL >= 0, "cache.metadata_ttl", "must not be negative"),
+	)
+}
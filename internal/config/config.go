@@ -1,23 +1,287 @@
 package config
 
-import "os"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
 
 type Config struct {
-	Server         *Server
-	OracleDatabase *OracleDatabase
+	Server         *Server         `yaml:"server"`
+	OracleDatabase *OracleDatabase `yaml:"database"`
+	Replica        *Replica        `yaml:"replica"`
+	Job            *Job            `yaml:"jobs"`
+	Webhook        *Webhook        `yaml:"webhook"`
+	Scheduler      *Scheduler      `yaml:"scheduler"`
+	Health         *Health         `yaml:"health"`
+	Tracing        *Tracing        `yaml:"tracing"`
+	Logging        *Logging        `yaml:"logging"`
+	Auth           *Auth           `yaml:"auth"`
+	Audit          *Audit          `yaml:"audit"`
+	Resilience     *Resilience     `yaml:"resilience"`
+	Idempotency    *Idempotency    `yaml:"idempotency"`
+	Cache          *Cache          `yaml:"cache"`
+	Features       *Features       `yaml:"features"`
+	Sessions       *Sessions       `yaml:"sessions"`
+	Tenants        *Tenants        `yaml:"tenants"`
+	Endpoints      *Endpoints      `yaml:"endpoints"`
+	// Policy can be written inline or kept in the JSON file named by PolicyFile, which wins.
+	Policy     *Policy `yaml:"policy"`
+	PolicyFile string  `yaml:"policy_file"`
+	// Datasources replaces Database with several named databases. DefaultDatasource serves
+	// requests that do not name one.
+	Datasources       map[string]*Datasource `yaml:"datasources"`
+	DefaultDatasource string                 `yaml:"default_datasource"`
 }
 
-func NewConfig() *Config {
+func defaultConfig() *Config {
 	return &Config{
-		Server:         newServer(),
-		OracleDatabase: newOracleDatabase(),
+		Server:         defaultServer(),
+		OracleDatabase: defaultOracleDatabase(),
+		Job:            defaultJob(),
+		Webhook:        defaultWebhook(),
+		Scheduler:      defaultScheduler(),
+		Health:         defaultHealth(),
+		Tracing:        defaultTracing(),
+		Logging:        defaultLogging(),
+		Auth:           defaultAuth(),
+		Audit:          defaultAudit(),
+		Resilience:     defaultResilience(),
+		Idempotency:    defaultIdempotency(),
+		Cache:          defaultCache(),
+		Features:       defaultFeatures(),
+		Sessions:       defaultSessions(),
+		Tenants:        defaultTenants(),
+		Endpoints:      &Endpoints{},
+		Policy:         &Policy{},
+	}
+}
+
+// Load builds the configuration from defaults, the optional YAML file at path and then
+// environment variables, which override the file. The result is validated, and every
+// problem found is reported in the returned error. Only YAML files are read; TOML files are
+// refused rather than misread.
+func Load(path string) (*Config, error) {
+	cfg := defaultConfig()
+
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return nil, fmt.Errorf("config file %s: TOML is not supported, use YAML", path)
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+		cfg.fillDefaults()
+	}
+
+	e := &env{}
+	cfg.applyEnv(e)
+
+	if cfg.PolicyFile != "" {
+		policy, err := LoadPolicy(cfg.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Policy = policy
+	}
+	cfg.Policy.applyEnv(e)
+
+	if cfg.Endpoints.File != "" {
+		routes, err := LoadEndpoints(cfg.Endpoints.File)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Endpoints.Routes = routes
+	}
+
+	if err := cfg.resolveDatasources(e); err != nil {
+		return nil, err
+	}
+	if err := errors.Join(e.errs...); err != nil {
+		return nil, fmt.Errorf("invalid environment: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// fillDefaults restores sections that the file set to null, such as an empty "server:" key.
+func (c *Config) fillDefaults() {
+	d := defaultConfig()
+	fill(&c.Server, d.Server)
+	fill(&c.OracleDatabase, d.OracleDatabase)
+	fill(&c.Job, d.Job)
+	fill(&c.Webhook, d.Webhook)
+	fill(&c.Scheduler, d.Scheduler)
+	fill(&c.Health, d.Health)
+	fill(&c.Tracing, d.Tracing)
+	fill(&c.Logging, d.Logging)
+	fill(&c.Auth, d.Auth)
+	fill(&c.Audit, d.Audit)
+	fill(&c.Resilience, d.Resilience)
+	fill(&c.Idempotency, d.Idempotency)
+	fill(&c.Cache, d.Cache)
+	fill(&c.Features, d.Features)
+	fill(&c.Sessions, d.Sessions)
+	fill(&c.Tenants, d.Tenants)
+	fill(&c.Endpoints, d.Endpoints)
+	fill(&c.Policy, d.Policy)
+}
+
+func fill[T any](dst **T, def *T) {
+	if *dst == nil {
+		*dst = def
+	}
+}
+
+func (c *Config) applyEnv(e *env) {
+	c.Server.applyEnv(e)
+	c.OracleDatabase.applyEnv(e)
+	c.Job.applyEnv(e)
+	c.Webhook.applyEnv(e)
+	c.Scheduler.applyEnv(e)
+	c.Health.applyEnv(e)
+	c.Tracing.applyEnv(e)
+	c.Logging.applyEnv(e)
+	c.Auth.applyEnv(e)
+	c.Audit.applyEnv(e)
+	c.Resilience.applyEnv(e)
+	c.Idempotency.applyEnv(e)
+	c.Cache.applyEnv(e)
+	c.Features.applyEnv(e)
+	c.Sessions.applyEnv(e)
+	c.Tenants.applyEnv(e)
+	c.Endpoints.applyEnv(e)
+	e.string("POLICY_FILE", &c.PolicyFile)
+	e.string("DEFAULT_DATASOURCE", &c.DefaultDatasource)
+}
+
+func (c *Config) Validate() error {
+	return errors.Join(
+		c.Server.validate(),
+		c.validateDatasources(),
+		c.Job.validate(),
+		c.Webhook.validate(),
+		c.Scheduler.validate(),
+		c.Health.validate(),
+		c.Tracing.validate(),
+		c.Logging.validate(),
+		c.Audit.validate(),
+		c.Resilience.validate(),
+		c.Idempotency.validate(),
+		c.Cache.validate(),
+		c.Sessions.validate(),
+		c.Auth.validate(c.Sessions.Enabled()),
+		c.Tenants.validate(),
+		check(c.Tenants.Source != "header" || len(c.Auth.TrustedProxies) > 0, "tenants.source", "header needs auth.trusted_proxies, as the header is only accepted from the API gateway"),
+		c.Endpoints.validate(c.DataSources()),
+	)
+}
+
+// env reads overrides from environment variables. Values that cannot be parsed are
+// collected instead of being ignored, so a typo fails startup.
+type env struct {
+	prefix string
+	errs   []error
+}
+
+// prefixed runs apply with every variable name prefixed, for example BILLING_ORACLE_HOST.
+func (e *env) prefixed(prefix string, apply func(e *env)) {
+	sub := &env{prefix: e.prefix + prefix}
+	apply(sub)
+	e.errs = append(e.errs, sub.errs...)
+}
+
+func (e *env) lookup(key string) (string, string, bool) {
+	key = e.prefix + key
+	value, exists := os.LookupEnv(key)
+	return key, value, exists
+}
+
+func (e *env) string(key string, dst *string) {
+	if _, value, exists := e.lookup(key); exists {
+		*dst = value
+	}
+}
+
+func (e *env) int(key string, dst *int) {
+	if key, value, exists := e.lookup(key); exists {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %q is not an integer", key, value))
+			return
+		}
+		*dst = i
 	}
 }
 
-func getEnv(key string, defaultVal string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+func (e *env) duration(key string, dst *time.Duration) {
+	if key, value, exists := e.lookup(key); exists {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %q is not a duration such as 30s or 5m", key, value))
+			return
+		}
+		*dst = d
 	}
+}
 
-	return defaultVal
+func (e *env) bool(key string, dst *bool) {
+	if key, value, exists := e.lookup(key); exists {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: %q is not true or false", key, value))
+			return
+		}
+		*dst = b
+	}
+}
+
+// list splits a comma separated variable, dropping empty items.
+func (e *env) list(key string, dst *[]string) {
+	_, value, exists := e.lookup(key)
+	if !exists {
+		return
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	*dst = result
+}
+
+// check returns an error for field when ok is false.
+func check(ok bool, field, format string, args ...any) error {
+	if ok {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...))
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
 }
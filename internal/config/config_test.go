@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, ":8080", cfg.Server.Addr())
+	assert.Equal(t, 30*time.Second, cfg.Server.ReadTimeout)
+	assert.False(t, cfg.Server.TLSEnabled())
+	assert.Equal(t, 1521, cfg.OracleDatabase.Port)
+	assert.True(t, cfg.Features.Jobs)
+	assert.True(t, cfg.Features.OpenAPI)
+	assert.False(t, cfg.Features.Admin)
+	assert.NotNil(t, cfg.Policy)
+}
+
+func TestLoad_FileAndEnvOverrides(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  host: 127.0.0.1
+  port: 9090
+  read_timeout: 5s
+  tls:
+    cert_file: server.crt
+    key_file: server.key
+database:
+  host: db.internal
+  sid: ORCL
+  max_open_conns: 50
+  options:
+    PREFETCH_ROWS: "500"
+features:
+  scheduler: false
+policy:
+  procedures:
+    pkg.get_rates:
+      cache_ttl: 5m
+      idempotent: true
+`)
+	t.Setenv("SERVER_PORT", "9191")
+	t.Setenv("ORACLE_HOST", "db.override")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1:9191", cfg.Server.Addr())
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, 30*time.Second, cfg.Server.WriteTimeout)
+	assert.True(t, cfg.Server.TLSEnabled())
+	assert.Equal(t, "db.override", cfg.OracleDatabase.Host)
+	assert.Equal(t, "ORCL", cfg.OracleDatabase.Sid)
+	assert.Equal(t, 50, cfg.OracleDatabase.MaxOpenConns)
+	dsn, err := cfg.OracleDatabase.DSN()
+	require.NoError(t, err)
+	assert.Contains(t, dsn, "PREFETCH_ROWS=500")
+	assert.False(t, cfg.Features.Scheduler)
+	assert.True(t, cfg.Features.Jobs)
+	assert.True(t, cfg.Features.OpenAPI)
+	assert.Equal(t, map[string]time.Duration{"pkg.get_rates": 5 * time.Minute}, cfg.Policy.CacheTTLs())
+	assert.Equal(t, []string{"pkg.get_rates"}, cfg.Policy.IdempotentProcedures())
+}
+
+func TestLoad_NullSection(t *testing.T) {
+	path := writeFile(t, "config.yaml", "server:\npolicy:\n")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.NotNil(t, cfg.Policy)
+}
+
+func TestLoad_PolicyFile(t *testing.T) {
+	policyPath := writeFile(t, "policy.json", `{"procedures": {"pkg.proc": {"max_concurrent": 2}}}`)
+	path := writeFile(t, "config.yaml", "policy_file: "+policyPath+"\n")
+	t.Setenv("REDACT_MODE", "hash")
+	t.Setenv("REDACT_HASH_KEY", "key")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Policy.Procedures["pkg.proc"].MaxConcurrent)
+	assert.Equal(t, "hash", cfg.Policy.Redaction.Mode)
+	assert.Equal(t, "key", cfg.Policy.Redaction.HashKey)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		env    map[string]string
+		errors []string
+	}{
+		{
+			name:   "unknown field",
+			file:   "server:\n  prot: 80\n",
+			errors: []string{"field prot not found"},
+		},
+		{
+			name:   "malformed duration",
+			file:   "server:\n  read_timeout: soon\n",
+			errors: []string{"parse config file"},
+		},
+		{
+			name:   "bad env value",
+			env:    map[string]string{"ORACLE_PORT": "abc", "SERVER_IDLE_TIMEOUT": "10"},
+			errors: []string{`ORACLE_PORT: "abc" is not an integer`, `SERVER_IDLE_TIMEOUT: "10" is not a duration`},
+		},
+		{
+			name:   "shared lock with local state",
+			file:   "scheduler:\n  lock: dbms_lock\n",
+			errors: []string{"scheduler.state: must be oracle with lock dbms_lock, as pause flags kept in memory are not shared between instances"},
+		},
+		{
+			name: "invalid values",
+			file: `
+server:
+  port: 70000
+  tls:
+    cert_file: server.crt
+database:
+  max_open_conns: 2
+  max_idle_conns: 5
+scheduler:
+  state: redis
+policy:
+  redaction:
+    mode: hash
+  procedures:
+    pkg.proc:
+      cache_ttl: later
+`,
+			errors: []string{
+				"server.port: must be between 1 and 65535, got 70000",
+				"server.tls: cert_file and key_file must be set together",
+				"database.max_idle_conns: must not exceed max_open_conns",
+				`scheduler.state: must be memory or oracle, got "redis"`,
+				"policy.redaction: REDACT_HASH_KEY is required in hash mode",
+				`policy.procedures.pkg.proc.cache_ttl: must be a positive duration such as 5m, got "later"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			path := ""
+			if tt.file != "" {
+				path = writeFile(t, "config.yaml", tt.file)
+			}
+
+			_, err := Load(path)
+			require.Error(t, err)
+			for _, msg := range tt.errors {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "config.toml", "[server]\nport = 9090\n")
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "TOML is not supported, use YAML")
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "read config file")
+}
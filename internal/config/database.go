@@ -1,34 +1,184 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"maps"
 	"strconv"
+	"strings"
+	"time"
 
 	goora "github.com/sijms/go-ora/v2"
 )
 
+// OracleDatabase describes how to reach the database. The target is picked in this order:
+// TNSAlias, looked up in TNSAdmin/tnsnames.ora (TNSAdmin defaults to WalletPath, which is
+// where Autonomous Database wallets keep it); ConnectString, a full connect descriptor;
+// then Host and Port with InstanceSID, Sid or ServiceName.
 type OracleDatabase struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Sid      string
+	Host        string `yaml:"host"`
+	Port        int    `yaml:"port"`
+	User        string `yaml:"user"`
+	Password    string `yaml:"password"`
+	ServiceName string `yaml:"service_name"`
+	// Sid is the service name under its original key, which was always passed to go-ora as
+	// a service name. It takes precedence over ServiceName so existing configurations keep
+	// connecting to the same database.
+	Sid string `yaml:"sid"`
+	// InstanceSID connects by instance SID rather than service name and takes precedence
+	// over both.
+	InstanceSID string `yaml:"instance_sid"`
+
+	ConnectString string `yaml:"connect_string"`
+	TNSAlias      string `yaml:"tns_alias"`
+	TNSAdmin      string `yaml:"tns_admin"`
+
+	WalletPath     string `yaml:"wallet_path"`
+	WalletPassword string `yaml:"wallet_password"`
+	// SSL enables TCPS. It is switched on automatically for descriptors using PROTOCOL=TCPS.
+	SSL       bool `yaml:"ssl"`
+	SSLVerify bool `yaml:"ssl_verify"`
+
+	// Options are passed to go-ora as URL options, for example "PREFETCH_ROWS": "500" or
+	// "TRACE FILE": "/tmp/trace.log".
+	Options map[string]string `yaml:"options"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
 }
 
-func newOracleDatabase() *OracleDatabase {
+func defaultOracleDatabase() *OracleDatabase {
 	return &OracleDatabase{
-		Host:     getEnv("ORACLE_HOST", "localhost"),
-		Port:     getEnv("ORACLE_PORT", "1521"),
-		User:     getEnv("ORACLE_USER", "app"),
-		Password: getEnv("ORACLE_PASSWORD", "password"),
-		Sid:      getEnv("ORACLE_SID", "FREEPDB1"),
+		Host:        "localhost",
+		Port:        1521,
+		User:        "app",
+		Password:    "password",
+		ServiceName: "FREEPDB1",
+		SSLVerify:   true,
+
+		MaxOpenConns:    20,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+}
+
+func (o *OracleDatabase) applyEnv(e *env) {
+	e.string("ORACLE_HOST", &o.Host)
+	e.int("ORACLE_PORT", &o.Port)
+	e.string("ORACLE_USER", &o.User)
+	e.string("ORACLE_PASSWORD", &o.Password)
+	e.string("ORACLE_SERVICE_NAME", &o.ServiceName)
+	e.string("ORACLE_SID", &o.Sid)
+	e.string("ORACLE_INSTANCE_SID", &o.InstanceSID)
+
+	e.string("ORACLE_CONNECT_STRING", &o.ConnectString)
+	e.string("ORACLE_TNS_ALIAS", &o.TNSAlias)
+	e.string("TNS_ADMIN", &o.TNSAdmin)
+
+	e.string("ORACLE_WALLET_PATH", &o.WalletPath)
+	e.string("ORACLE_WALLET_PASSWORD", &o.WalletPassword)
+	e.bool("ORACLE_SSL", &o.SSL)
+	e.bool("ORACLE_SSL_VERIFY", &o.SSLVerify)
+
+	e.int("ORACLE_MAX_OPEN_CONNS", &o.MaxOpenConns)
+	e.int("ORACLE_MAX_IDLE_CONNS", &o.MaxIdleConns)
+	e.duration("ORACLE_CONN_MAX_LIFETIME", &o.ConnMaxLifetime)
+	e.duration("ORACLE_CONN_MAX_IDLE_TIME", &o.ConnMaxIdleTime)
+}
+
+func (o *OracleDatabase) validate() error {
+	return o.validateSection("database")
+}
+
+// validateSection reports errors under section, such as "database" or "replica".
+func (o *OracleDatabase) validateSection(section string) error {
+	errs := []error{
+		check(o.User != "", section+".user", "is required"),
+		check(o.TNSAlias == "" || o.ConnectString == "", section, "tns_alias and connect_string are mutually exclusive"),
+		check(o.TNSAlias == "" || o.TNSAdmin != "" || o.WalletPath != "", section+".tns_admin", "is required to look up tns_alias"),
+		check(o.ConnectString == "" || strings.HasPrefix(strings.TrimSpace(o.ConnectString), "("),
+			section+".connect_string", "must be a connect descriptor such as (DESCRIPTION=...)"),
+		check(o.MaxOpenConns >= 0, section+".max_open_conns", "must not be negative"),
+		check(o.MaxIdleConns >= 0, section+".max_idle_conns", "must not be negative"),
+		check(o.MaxOpenConns == 0 || o.MaxIdleConns <= o.MaxOpenConns, section+".max_idle_conns", "must not exceed max_open_conns"),
+		check(o.ConnMaxLifetime >= 0, section+".conn_max_lifetime", "must not be negative"),
+		check(o.ConnMaxIdleTime >= 0, section+".conn_max_idle_time", "must not be negative"),
+	}
+	if o.TNSAlias == "" && o.ConnectString == "" {
+		errs = append(errs,
+			check(o.Host != "", section+".host", "is required"),
+			check(o.Port > 0 && o.Port <= 65535, section+".port", "must be between 1 and 65535, got %d", o.Port),
+			check(o.InstanceSID != "" || o.Sid != "" || o.ServiceName != "", section+".service_name", "service_name, sid or instance_sid is required"),
+		)
+	}
+	for key := range o.Options {
+		errs = append(errs, check(!reservedOption(key), section+".options."+key, "is set by a dedicated database field"))
 	}
+	return errors.Join(errs...)
 }
 
-func (o *OracleDatabase) DSN() string {
-	portInt, err := strconv.Atoi(o.Port)
-	if err != nil {
-		panic(err)
+// reservedOption reports whether a go-ora URL option is derived from other fields.
+func reservedOption(key string) bool {
+	switch strings.ToUpper(key) {
+	case "CONNSTR", "SID", "SERVICE NAME", "WALLET", "WALLET PASSWORD", "SSL", "SSL VERIFY", "PROXY CLIENT NAME":
+		return true
 	}
+	return false
+}
+
+// DSN builds the go-ora connection URL. It fails when the TNS alias cannot be resolved.
+func (o *OracleDatabase) DSN() (string, error) {
+	options := maps.Clone(o.Options)
+	if options == nil {
+		options = make(map[string]string)
+	}
+
+	descriptor := o.ConnectString
+	if o.TNSAlias != "" {
+		dir := o.TNSAdmin
+		if dir == "" {
+			dir = o.WalletPath
+		}
+		var err error
+		descriptor, err = lookupTNSAlias(dir, o.TNSAlias)
+		if err != nil {
+			return "", fmt.Errorf("resolve tns alias: %w", err)
+		}
+	}
+
+	if o.WalletPath != "" {
+		options["WALLET"] = o.WalletPath
+	}
+	if o.WalletPassword != "" {
+		options["WALLET PASSWORD"] = o.WalletPassword
+	}
+	if o.SSL || usesTCPS(descriptor) {
+		options["SSL"] = "enable"
+		options["SSL VERIFY"] = strconv.FormatBool(o.SSLVerify)
+	}
+
+	if descriptor != "" {
+		return goora.BuildJDBC(o.User, o.Password, descriptor, options), nil
+	}
+
+	service := o.ServiceName
+	if o.Sid != "" {
+		service = o.Sid
+	}
+	if o.InstanceSID != "" {
+		options["SID"] = o.InstanceSID
+		service = ""
+	}
+	if len(options) == 0 {
+		options = nil
+	}
+	return goora.BuildUrl(o.Host, o.Port, service, o.User, o.Password, options), nil
+}
 
-	return goora.BuildUrl(o.Host, portInt, o.Sid, o.User, o.Password, nil)
+func usesTCPS(descriptor string) bool {
+	compact := strings.ToUpper(strings.Join(strings.Fields(descriptor), ""))
+	return strings.Contains(compact, "(PROTOCOL=TCPS)")
 }
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sijms/go-ora/v2/configurations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tnsnames = `
+# Autonomous Database aliases
+ADB_HIGH, ADB_DEFAULT.WORLD =
+  (DESCRIPTION =
+    (ADDRESS = (PROTOCOL = TCPS)(HOST = adb.example.com)(PORT = 1522))
+    (CONNECT_DATA = (SERVICE_NAME = adb_high.example.com)))
+
+IFILE = /etc/oracle/other.ora
+
+rac =
+  (DESCRIPTION =
+    (ADDRESS_LIST =
+      (ADDRESS = (PROTOCOL = TCP)(HOST = rac1)(PORT = 1521))
+      (ADDRESS = (PROTOCOL = TCP)(HOST = rac2)(PORT = 1521)))
+    (CONNECT_DATA = (SERVICE_NAME = sales)))
+`
+
+func TestParseTNSNames(t *testing.T) {
+	entries, err := parseTNSNames(strings.NewReader(tnsnames))
+	require.NoError(t, err)
+
+	assert.Len(t, entries, 3)
+	assert.Equal(t, entries["ADB_HIGH"], entries["ADB_DEFAULT.WORLD"])
+	assert.Contains(t, entries["RAC"], "(HOST = rac2)")
+	assert.NotContains(t, entries, "IFILE")
+
+	_, err = parseTNSNames(strings.NewReader("broken = (DESCRIPTION = (ADDRESS = "))
+	assert.ErrorContains(t, err, "entry broken: unbalanced parentheses")
+}
+
+func TestOracleDatabase_DSN(t *testing.T) {
+	tnsAdmin := filepath.Dir(writeFile(t, "tnsnames.ora", tnsnames))
+
+	tests := []struct {
+		name   string
+		db     OracleDatabase
+		verify func(t *testing.T, cfg *configurations.ConnectionConfig)
+	}{
+		{
+			name: "service name",
+			db:   OracleDatabase{Host: "db", Port: 1521, User: "app", Password: "p@ss", ServiceName: "FREEPDB1"},
+			verify: func(t *testing.T, cfg *configurations.ConnectionConfig) {
+				assert.Equal(t, "FREEPDB1", cfg.ServiceName)
+				assert.Empty(t, cfg.SID)
+				assert.Equal(t, "p@ss", cfg.Password)
+				assert.False(t, cfg.SSL)
+			},
+		},
+		{
+			name: "sid is the service name it always was",
+			db:   OracleDatabase{Host: "db", Port: 1521, User: "app", ServiceName: "FREEPDB1", Sid: "ORCLPDB"},
+			verify: func(t *testing.T, cfg *configurations.ConnectionConfig) {
+				assert.Equal(t, "ORCLPDB", cfg.ServiceName)
+				assert.Empty(t, cfg.SID)
+			},
+		},
+		{
+			name: "instance sid wins over service names",
+			db:   OracleDatabase{Host: "db", Port: 1521, User: "app", ServiceName: "FREEPDB1", Sid: "ORCLPDB", InstanceSID: "ORCL"},
+			verify: func(t *testing.T, cfg *configurations.ConnectionConfig) {
+				assert.Equal(t, "ORCL", cfg.SID)
+				assert.Empty(t, cfg.ServiceName)
+			},
+		},
+		{
+			name: "connect string with options",
+			db: OracleDatabase{
+				User:          "app",
+				ConnectString: "(DESCRIPTION=(ADDRESS=(PROTOCOL=TCP)(HOST=rac1)(PORT=1521))(CONNECT_DATA=(SERVICE_NAME=sales)))",
+				Options:       map[string]string{"PREFETCH_ROWS": "500"},
+			},
+			verify: func(t *testing.T, cfg *configurations.ConnectionConfig) {
+				assert.Equal(t, "sales", cfg.ServiceName)
+				assert.Equal(t, 500, cfg.PrefetchRows)
+				require.Len(t, cfg.Servers, 1)
+				assert.Equal(t, "rac1", cfg.Servers[0].Addr)
+			},
+		},
+		{
+			name: "tns alias with several addresses",
+			db:   OracleDatabase{User: "app", TNSAlias: "rac", TNSAdmin: tnsAdmin},
+			verify: func(t *testing.T, cfg *configurations.ConnectionConfig) {
+				assert.Equal(t, "sales", cfg.ServiceName)
+				assert.Len(t, cfg.Servers, 2)
+				assert.False(t, cfg.SSL)
+			},
+		},
+		{
+			name: "tns alias over tcps without domain",
+			db:   OracleDatabase{User: "app", TNSAlias: "adb_default", TNSAdmin: tnsAdmin, SSLVerify: false},
+			verify: func(t *testing.T, cfg *configurations.ConnectionConfig) {
+				assert.Equal(t, "adb_high.example.com", cfg.ServiceName)
+				assert.True(t, cfg.SSL)
+				assert.False(t, cfg.SSLVerify)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn, err := tt.db.DSN()
+			require.NoError(t, err)
+
+			cfg, err := configurations.ParseConfig(dsn)
+			require.NoError(t, err)
+			tt.verify(t, cfg)
+		})
+	}
+}
+
+func TestOracleDatabase_DSNWallet(t *testing.T) {
+	db := OracleDatabase{Host: "db", Port: 1522, User: "app", ServiceName: "svc", WalletPath: "/wallet", WalletPassword: "secret", SSL: true, SSLVerify: true}
+
+	dsn, err := db.DSN()
+	require.NoError(t, err)
+
+	u, err := url.Parse(dsn)
+	require.NoError(t, err)
+	query := u.Query()
+	assert.Equal(t, "/wallet", query.Get("WALLET"))
+	assert.Equal(t, "secret", query.Get("WALLET PASSWORD"))
+	assert.Equal(t, "enable", query.Get("SSL"))
+	assert.Equal(t, "true", query.Get("SSL VERIFY"))
+}
+
+func TestOracleDatabase_DSNUnknownAlias(t *testing.T) {
+	tnsAdmin := filepath.Dir(writeFile(t, "tnsnames.ora", tnsnames))
+	db := OracleDatabase{User: "app", TNSAlias: "missing", TNSAdmin: tnsAdmin}
+
+	_, err := db.DSN()
+	assert.ErrorContains(t, err, "alias MISSING not found")
+}
+
+func TestOracleDatabase_Validate(t *testing.T) {
+	db := defaultOracleDatabase()
+	db.ConnectString = "db:1521/svc"
+	db.TNSAlias = "rac"
+	db.Options = map[string]string{"WALLET": "/wallet"}
+
+	err := db.validate()
+	assert.ErrorContains(t, err, "database: tns_alias and connect_string are mutually exclusive")
+	assert.ErrorContains(t, err, "database.tns_admin: is required to look up tns_alias")
+	assert.ErrorContains(t, err, "database.connect_string: must be a connect descriptor")
+	assert.ErrorContains(t, err, "database.options.WALLET: is set by a dedicated database field")
+}
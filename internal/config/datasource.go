@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDatasourceName names the data source formed by the top-level database and policy
+// when no datasources section is configured.
+const DefaultDatasourceName = "default"
+
+// Datasource is a named database with its own connection pool and policy. A data source
+// without a policy shares the top-level one.
+type Datasource struct {
+	Database   *OracleDatabase `yaml:"database"`
+	Replica    *Replica        `yaml:"replica"`
+	Policy     *Policy         `yaml:"policy"`
+	PolicyFile string          `yaml:"policy_file"`
+}
+
+// UnmarshalYAML starts from the default database settings, so a data source only needs to
+// list what differs. Unknown fields are still rejected.
+func (d *Datasource) UnmarshalYAML(node *yaml.Node) error {
+	type plain Datasource
+	p := plain{Database: defaultOracleDatabase()}
+	if err := decodeStrict(node, &p); err != nil {
+		return err
+	}
+	*d = Datasource(p)
+	return nil
+}
+
+// Replica is a read-only standby, such as an Active Data Guard database, that serves the
+// procedures marked read_only in the policy. Settings it leaves out are taken from the
+// primary after environment overrides, so usually only the host or service name differ.
+// Its own overrides use the REPLICA_ prefix, for example REPLICA_ORACLE_HOST.
+type Replica struct {
+	*OracleDatabase
+	node yaml.Node
+}
+
+// UnmarshalYAML keeps the node until the primary is final. The fields are checked now so
+// that typos are reported with the rest of the file.
+func (r *Replica) UnmarshalYAML(node *yaml.Node) error {
+	r.node = *node
+	return decodeStrict(node, &OracleDatabase{})
+}
+
+func (r *Replica) resolve(primary *OracleDatabase, e *env, prefix string) error {
+	db := *primary
+	db.Options = maps.Clone(primary.Options)
+	if err := decodeStrict(&r.node, &db); err != nil {
+		return err
+	}
+	e.prefixed(prefix+"REPLICA_", db.applyEnv)
+	r.OracleDatabase = &db
+	return nil
+}
+
+// decodeStrict decodes node into v, rejecting unknown fields. yaml.Node.Decode does not
+// carry over the KnownFields setting of the outer decoder.
+func decodeStrict(node *yaml.Node, v any) error {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(v)
+}
+
+// DataSources returns the configured data sources. Without a datasources section, the
+// top-level database and policy form a single one named DefaultDatasourceName.
+func (c *Config) DataSources() map[string]*Datasource {
+	if len(c.Datasources) > 0 {
+		return c.Datasources
+	}
+	return map[string]*Datasource{
+		DefaultDatasourceName: {Database: c.OracleDatabase, Replica: c.Replica, Policy: c.Policy},
+	}
+}
+
+// resolveDatasources fills in data sources after the top-level policy is final. Each one
+// reads overrides from variables prefixed with its upper-cased name, such as
+// BILLING_ORACLE_PASSWORD.
+func (c *Config) resolveDatasources(e *env) error {
+	if len(c.Datasources) == 0 {
+		if c.DefaultDatasource == "" {
+			c.DefaultDatasource = DefaultDatasourceName
+		}
+		if c.Replica != nil {
+			if err := c.Replica.resolve(c.OracleDatabase, e, ""); err != nil {
+				return fmt.Errorf("replica: %w", err)
+			}
+		}
+		return nil
+	}
+
+	for name, ds := range c.Datasources {
+		if ds == nil {
+			ds = &Datasource{}
+			c.Datasources[name] = ds
+		}
+		if ds.Database == nil {
+			ds.Database = defaultOracleDatabase()
+		}
+
+		prefix := envPrefix(name)
+		e.prefixed(prefix, ds.Database.applyEnv)
+		if ds.Replica != nil {
+			if err := ds.Replica.resolve(ds.Database, e, prefix); err != nil {
+				return fmt.Errorf("datasource %s replica: %w", name, err)
+			}
+		}
+		e.prefixed(prefix, func(e *env) { e.string("POLICY_FILE", &ds.PolicyFile) })
+
+		if ds.PolicyFile != "" {
+			policy, err := LoadPolicy(ds.PolicyFile)
+			if err != nil {
+				return fmt.Errorf("datasource %s: %w", name, err)
+			}
+			ds.Policy = policy
+		}
+		if ds.Policy == nil {
+			ds.Policy = c.Policy
+			continue
+		}
+		ds.Policy.applyEnv(e)
+		e.prefixed(prefix, ds.Policy.applyEnv)
+	}
+
+	if c.DefaultDatasource == "" && len(c.Datasources) == 1 {
+		for name := range c.Datasources {
+			c.DefaultDatasource = name
+		}
+	}
+	return nil
+}
+
+// reservedDatasourceNames are the fixed segments of the /api/v1 routes that data source
+// names share a position with, as in /api/v1/{ds}/procedures.
+var reservedDatasourceNames = []string{"procedures", "p", "jobs", "schedules", "openapi.json", "docs"}
+
+func (c *Config) validateDatasources() error {
+	if len(c.Datasources) == 0 {
+		return errors.Join(c.OracleDatabase.validate(), c.Replica.validate(), c.Policy.validate())
+	}
+
+	errs := []error{c.Policy.validate()}
+	for name, ds := range c.Datasources {
+		prefix := "datasources." + name + "."
+		errs = append(errs, check(!slices.Contains(reservedDatasourceNames, name), "datasources."+name, "is reserved by the API's routes"))
+		errs = append(errs, prefixErrors(prefix, ds.Database.validate()), prefixErrors(prefix, ds.Replica.validate()))
+		if ds.Policy != c.Policy {
+			errs = append(errs, prefixErrors(prefix, ds.Policy.validate()))
+			limits := ds.Policy.RateLimits
+			errs = append(errs, check(limits.PerPrincipal == (RateLimit{}) && len(limits.Principals) == 0,
+				prefix+"policy.rate_limits", "principal limits apply to every data source and belong in the top-level policy"))
+		}
+	}
+	if c.DefaultDatasource == "" {
+		errs = append(errs, check(false, "default_datasource", "is required with several data sources"))
+	} else {
+		_, ok := c.Datasources[c.DefaultDatasource]
+		errs = append(errs, check(ok, "default_datasource", "%q is not a configured data source", c.DefaultDatasource))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Replica) validate() error {
+	if r == nil {
+		return nil
+	}
+	return r.validateSection("replica")
+}
+
+func envPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name) + "_"
+}
+
+// prefixErrors prefixes every error joined in err with the field path of its section.
+func prefixErrors(prefix string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, prefixErrors(prefix, e))
+		}
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("%s%w", prefix, err)
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_ImplicitDatasource(t *testing.T) {
+	cfg, err := Load("")
+	require.NoError(t, err)
+
+	sources := cfg.DataSources()
+	require.Len(t, sources, 1)
+	assert.Equal(t, DefaultDatasourceName, cfg.DefaultDatasource)
+	assert.Same(t, cfg.OracleDatabase, sources[DefaultDatasourceName].Database)
+	assert.Same(t, cfg.Policy, sources[DefaultDatasourceName].Policy)
+}
+
+func TestLoad_Datasources(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+default_datasource: core
+policy:
+  procedures:
+    pkg.shared:
+      max_concurrent: 1
+datasources:
+  core:
+    database:
+      host: core-db
+  billing:
+    database:
+      host: billing-db
+      port: 1522
+      max_open_conns: 5
+    policy:
+      procedures:
+        pkg.invoice:
+          idempotent: true
+  dwh:
+`)
+	t.Setenv("BILLING_ORACLE_PASSWORD", "billing-secret")
+	t.Setenv("ORACLE_PASSWORD", "top-level")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	sources := cfg.DataSources()
+	require.Len(t, sources, 3)
+	assert.Equal(t, "core", cfg.DefaultDatasource)
+
+	core := sources["core"]
+	assert.Equal(t, "core-db", core.Database.Host)
+	assert.Equal(t, 1521, core.Database.Port)
+	assert.Equal(t, "FREEPDB1", core.Database.ServiceName)
+	assert.Same(t, cfg.Policy, core.Policy)
+
+	billing := sources["billing"]
+	assert.Equal(t, 1522, billing.Database.Port)
+	assert.Equal(t, 5, billing.Database.MaxOpenConns)
+	assert.Equal(t, "billing-secret", billing.Database.Password)
+	assert.Equal(t, []string{"pkg.invoice"}, billing.Policy.IdempotentProcedures())
+
+	assert.Equal(t, "localhost", sources["dwh"].Database.Host)
+	assert.Equal(t, "password", sources["dwh"].Database.Password)
+}
+
+func TestLoad_DatasourceErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		errors []string
+	}{
+		{
+			name: "missing default",
+			file: "datasources:\n  core:\n  billing:\n",
+			errors: []string{
+				"default_datasource: is required with several data sources",
+			},
+		},
+		{
+			name: "reserved name",
+			file: "default_datasource: core\ndatasources:\n  core:\n  jobs:\n",
+			errors: []string{
+				"datasources.jobs: is reserved by the API's routes",
+			},
+		},
+		{
+			name: "unknown default",
+			file: "default_datasource: dwh\ndatasources:\n  core:\n",
+			errors: []string{
+				`default_datasource: "dwh" is not a configured data source`,
+			},
+		},
+		{
+			name: "invalid data source",
+			file: `
+datasources:
+  billing:
+    database:
+      port: 0
+    policy:
+      redaction:
+        mode: scramble
+      rate_limits:
+        per_principal:
+          rate: 5
+          burst: 10
+`,
+			errors: []string{
+				"datasources.billing.database.port: must be between 1 and 65535, got 0",
+				`datasources.billing.policy.redaction.mode: must be mask or hash, got "scramble"`,
+				"datasources.billing.policy.rate_limits: principal limits apply to every data source and belong in the top-level policy",
+			},
+		},
+		{
+			name:   "unknown field",
+			file:   "datasources:\n  billing:\n    databse:\n      host: x\n",
+			errors: []string{"field databse not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeFile(t, "config.yaml", tt.file))
+			require.Error(t, err)
+			for _, msg := range tt.errors {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
+
+func TestLoad_Replica(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+database:
+  host: primary-db
+  user: app
+  options:
+    PREFETCH_ROWS: "100"
+replica:
+  host: standby-db
+  max_open_conns: 40
+policy:
+  procedures:
+    pkg.get_report:
+      read_only: true
+`)
+	t.Setenv("ORACLE_PASSWORD", "from-env")
+	t.Setenv("REPLICA_ORACLE_SERVICE_NAME", "REPORTS")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	replica := cfg.DataSources()[DefaultDatasourceName].Replica
+	require.NotNil(t, replica)
+	assert.Equal(t, "standby-db", replica.Host)
+	assert.Equal(t, "app", replica.User)
+	assert.Equal(t, "from-env", replica.Password)
+	assert.Equal(t, "REPORTS", replica.ServiceName)
+	assert.Equal(t, "FREEPDB1", cfg.OracleDatabase.ServiceName)
+	assert.Equal(t, 40, replica.MaxOpenConns)
+	assert.Equal(t, "100", replica.Options["PREFETCH_ROWS"])
+	assert.Equal(t, []string{"pkg.get_report"}, cfg.Policy.ReadOnlyProcedures())
+}
+
+func TestLoad_DatasourceReplica(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+datasources:
+  dwh:
+    database:
+      host: dwh-primary
+    replica:
+      host: dwh-standby
+      port: 0
+`)
+	t.Setenv("DWH_ORACLE_PASSWORD", "dwh-secret")
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "datasources.dwh.replica.port: must be between 1 and 65535, got 0")
+
+	t.Setenv("DWH_REPLICA_ORACLE_PORT", "1523")
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	replica := cfg.DataSources()["dwh"].Replica
+	assert.Equal(t, "dwh-standby", replica.Host)
+	assert.Equal(t, 1523, replica.Port)
+	assert.Equal(t, "dwh-secret", replica.Password)
+
+	_, err = Load(writeFile(t, "config.yaml", "replica:\n  hots: standby\n"))
+	assert.ErrorContains(t, err, "field hots not found")
+}
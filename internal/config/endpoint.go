@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Endpoints maps custom routes, such as GET /customers/{id}/orders, to procedures. Routes
+// are written inline or kept in the YAML file named by File, which wins.
+type Endpoints struct {
+	File string `yaml:"file"`
+	// JWTSecret verifies the bearer tokens that claim parameters are read from. It is
+	// required by routes with claim parameters unless TrustGatewayVerified says the API
+	// gateway verifies tokens before they reach the service.
+	JWTSecret            string     `yaml:"jwt_secret"`
+	TrustGatewayVerified bool       `yaml:"trust_gateway_verified"`
+	Routes               []Endpoint `yaml:"routes"`
+}
+
+// Endpoint binds an HTTP method and a chi path template to a procedure. Argument types
+// and directions come from the procedure's metadata.
+type Endpoint struct {
+	Method    string `yaml:"method"`
+	Path      string `yaml:"path"`
+	Procedure string `yaml:"procedure"`
+	// Datasource names the database to call. Empty means the default data source.
+	Datasource string          `yaml:"datasource"`
+	Params     []EndpointParam `yaml:"params"`
+	// Outputs renames OUT arguments in the response. Other OUT arguments keep their
+	// lower-case names.
+	Outputs map[string]string `yaml:"outputs"`
+	// Cursor names an OUT cursor whose rows are the whole response.
+	Cursor string `yaml:"cursor"`
+	// Status is returned on success, 200 when unset.
+	Status int `yaml:"status"`
+}
+
+// EndpointParam sets a procedure argument from the request, or to Value with source const.
+type EndpointParam struct {
+	Name string `yaml:"name"`
+	// From is path, query, header, body, claim or const.
+	From string `yaml:"from"`
+	// Key names the path parameter, query parameter, header, body field or claim; the
+	// argument name when unset.
+	Key   string `yaml:"key"`
+	Value any    `yaml:"value"`
+}
+
+// reservedPrefixes are served by the API itself.
+var reservedPrefixes = []string{"/api", "/admin", "/health", "/metrics"}
+
+// pathParam matches the parameters of a chi path template, such as {id} or {id:[0-9]+}.
+var pathParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+func (e *Endpoints) applyEnv(env *env) {
+	env.string("ENDPOINTS_FILE", &e.File)
+	env.string("ENDPOINTS_JWT_SECRET", &e.JWTSecret)
+	env.bool("ENDPOINTS_TRUST_GATEWAY_VERIFIED", &e.TrustGatewayVerified)
+}
+
+// LoadEndpoints reads the routes of an endpoint mapping file, a YAML document with a
+// routes list.
+func LoadEndpoints(path string) ([]Endpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read endpoints file: %w", err)
+	}
+
+	var file struct {
+		Routes []Endpoint `yaml:"routes"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse endpoints file %s: %w", path, err)
+	}
+	return file.Routes, nil
+}
+
+func (e *Endpoints) validate(dataSources map[string]*Datasource) error {
+	var errs []error
+	routes := make(map[string]int, len(e.Routes))
+	for i, route := range e.Routes {
+		field := fmt.Sprintf("endpoints.routes[%d]", i)
+		method := strings.ToUpper(route.Method)
+		errs = append(errs,
+			check(oneOf(method, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete), field+".method", "must be GET, POST, PUT, PATCH or DELETE, got %q", route.Method),
+			check(strings.HasPrefix(route.Path, "/"), field+".path", "must start with /, got %q", route.Path),
+			check(strings.TrimSpace(route.Procedure) != "", field+".procedure", "is required"),
+			check(route.Status == 0 || (route.Status >= 200 && route.Status < 300), field+".status", "must be a 2xx status, got %d", route.Status),
+		)
+		if strings.HasPrefix(route.Path, "/") {
+			// Routes are registered at the root, where a leading parameter would also match
+			// the API's own paths.
+			first, _, _ := strings.Cut(route.Path[1:], "/")
+			errs = append(errs, check(first != "" && !strings.Contains(first, "{"), field+".path", "must start with a fixed segment such as /customers, got %q", route.Path))
+		}
+		for _, prefix := range reservedPrefixes {
+			reserved := route.Path == prefix || strings.HasPrefix(route.Path, prefix+"/")
+			errs = append(errs, check(!reserved, field+".path", "must not be under %s, which the API serves", prefix))
+		}
+		if route.Datasource != "" {
+			_, ok := dataSources[route.Datasource]
+			errs = append(errs, check(ok, field+".datasource", "unknown data source %q", route.Datasource))
+		}
+
+		key := method + " " + pathParam.ReplaceAllString(route.Path, "{}")
+		if other, ok := routes[key]; ok {
+			errs = append(errs, check(false, field, "%s %s is already mapped by endpoints.routes[%d]", method, route.Path, other))
+		}
+		routes[key] = i
+
+		pathParams := make(map[string]bool)
+		for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+			pathParams[match[1]] = true
+		}
+		names := make(map[string]bool, len(route.Params))
+		for j, param := range route.Params {
+			errs = append(errs, param.validate(fmt.Sprintf("%s.params[%d]", field, j), pathParams))
+			if param.From == "claim" {
+				errs = append(errs, check(e.JWTSecret != "" || e.TrustGatewayVerified, fmt.Sprintf("%s.params[%d].from", field, j), "claim needs endpoints.jwt_secret unless trust_gateway_verified is set"))
+			}
+			name := strings.ToLower(param.Name)
+			errs = append(errs, check(!names[name], fmt.Sprintf("%s.params[%d].name", field, j), "%q is set twice", param.Name))
+			names[name] = true
+		}
+		errs = append(errs, check(route.Cursor == "" || len(route.Outputs) == 0, field+".outputs", "cannot be combined with cursor"))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *EndpointParam) validate(field string, pathParams map[string]bool) error {
+	errs := []error{
+		check(strings.TrimSpace(p.Name) != "", field+".name", "is required"),
+		check(oneOf(p.From, "path", "query", "header", "body", "claim", "const"), field+".from", "must be path, query, header, body, claim or const, got %q", p.From),
+	}
+	if p.From == "const" {
+		errs = append(errs, check(p.Value != nil, field+".value", "is required with from const"))
+	} else {
+		errs = append(errs, check(p.Value == nil, field+".value", "is only allowed with from const"))
+	}
+	if p.From == "path" {
+		errs = append(errs, check(pathParams[p.SourceKey()], field+".key", "%q is not a parameter of the path", p.SourceKey()))
+	}
+	return errors.Join(errs...)
+}
+
+// SourceKey returns the name the parameter is read under.
+func (p *EndpointParam) SourceKey() string {
+	if p.Key != "" {
+		return p.Key
+	}
+	return p.Name
+}
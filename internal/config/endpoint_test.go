@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Endpoints(t *testing.T) {
+	_, err := Load(writeFile(t, "config.yaml", `
+endpoints:
+  routes:
+    - method: FETCH
+      path: customers
+      procedure: crm.pkg_orders.get_by_customer
+      status: 404
+    - method: GET
+      path: /api/v1/orders
+      procedure: orders
+      datasource: crm
+      cursor: p_orders
+      outputs:
+        p_total: total
+      params:
+        - {name: p_id, from: path, key: order_id}
+        - {name: p_id, from: query}
+        - {name: p_channel, from: const}
+        - {name: p_user, from: cookie, value: x}
+        - {name: p_customer, from: claim, key: sub}
+    - method: get
+      path: /customers/{id:[0-9]+}/orders
+      procedure: orders
+    - method: GET
+      path: /customers/{customer}/orders
+      procedure: orders
+    - method: GET
+      path: /{id}/orders
+      procedure: orders
+`))
+	require.Error(t, err)
+	for _, msg := range []string{
+		`endpoints.routes[0].method: must be GET, POST, PUT, PATCH or DELETE, got "FETCH"`,
+		`endpoints.routes[0].path: must start with /, got "customers"`,
+		"endpoints.routes[0].status: must be a 2xx status, got 404",
+		"endpoints.routes[1].path: must not be under /api, which the API serves",
+		`endpoints.routes[1].datasource: unknown data source "crm"`,
+		"endpoints.routes[1].outputs: cannot be combined with cursor",
+		`endpoints.routes[1].params[0].key: "order_id" is not a parameter of the path`,
+		`endpoints.routes[1].params[1].name: "p_id" is set twice`,
+		"endpoints.routes[1].params[2].value: is required with from const",
+		`endpoints.routes[1].params[3].from: must be path, query, header, body, claim or const, got "cookie"`,
+		"endpoints.routes[1].params[3].value: is only allowed with from const",
+		"endpoints.routes[1].params[4].from: claim needs endpoints.jwt_secret unless trust_gateway_verified is set",
+		"endpoints.routes[3]: GET /customers/{customer}/orders is already mapped by endpoints.routes[2]",
+		`endpoints.routes[4].path: must start with a fixed segment such as /customers, got "/{id}/orders"`,
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+
+	routes := writeFile(t, "endpoints.yaml", `
+routes:
+  - method: GET
+    path: /customers/{id}/orders
+    procedure: crm.pkg_orders.get_by_customer
+    cursor: p_orders
+    params:
+      - {name: p_customer_id, from: path, key: id}
+      - {name: p_channel, from: const, value: WEB}
+`)
+	t.Setenv("ENDPOINTS_FILE", routes)
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	require.Len(t, cfg.Endpoints.Routes, 1)
+	route := cfg.Endpoints.Routes[0]
+	assert.Equal(t, "crm.pkg_orders.get_by_customer", route.Procedure)
+	assert.Equal(t, "id", route.Params[0].SourceKey())
+	assert.Equal(t, "p_channel", route.Params[1].SourceKey())
+	assert.Equal(t, "WEB", route.Params[1].Value)
+}
@@ -0,0 +1,31 @@
+package config
+
+// Features switch optional parts of the service on and off. Admin is off by default, as
+// the /admin routes expose pool statistics and the audit trail, and the routes running,
+// pausing and resuming schedules change them, for anyone who can reach the service;
+// enable it only behind a gateway that restricts them.
+type Features struct {
+	Jobs      bool `yaml:"jobs"`
+	Scheduler bool `yaml:"scheduler"`
+	Metrics   bool `yaml:"metrics"`
+	Admin     bool `yaml:"admin"`
+	OpenAPI   bool `yaml:"openapi"`
+}
+
+func defaultFeatures() *Features {
+	return &Features{
+		Jobs:      true,
+		Scheduler: true,
+		Metrics:   true,
+		Admin:     false,
+		OpenAPI:   true,
+	}
+}
+
+func (f *Features) applyEnv(e *env) {
+	e.bool("FEATURE_JOBS", &f.Jobs)
+	e.bool("FEATURE_SCHEDULER", &f.Scheduler)
+	e.bool("FEATURE_METRICS", &f.Metrics)
+	e.bool("FEATURE_ADMIN", &f.Admin)
+	e.bool("FEATURE_OPENAPI", &f.OpenAPI)
+}
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+type Health struct {
+	Timeout         time.Duration `yaml:"timeout"`
+	CheckProcedures []string      `yaml:"check_procedures"`
+}
+
+func defaultHealth() *Health {
+	return &Health{
+		Timeout: 2 * time.Second,
+	}
+}
+
+func (h *Health) applyEnv(e *env) {
+	e.duration("HEALTH_TIMEOUT", &h.Timeout)
+	e.list("HEALTH_CHECK_PROCEDURES", &h.CheckProcedures)
+}
+
+func (h *Health) validate() error {
+	return check(h.Timeout > 0, "health.timeout", "must be positive")
+}
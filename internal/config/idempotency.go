@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+type Idempotency struct {
+	TTL time.Duration `yaml:"ttl"`
+}
+
+func defaultIdempotency() *Idempotency {
+	return &Idempotency{
+		TTL: 24 * time.Hour,
+	}
+}
+
+func (i *Idempotency) applyEnv(e *env) {
+	e.duration("IDEMPOTENCY_TTL", &i.TTL)
+}
+
+func (i *Idempotency) validate() error {
+	return check(i.TTL > 0, "idempotency.ttl", "must be positive")
+}
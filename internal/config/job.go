@@ -0,0 +1,48 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Job struct {
+	Workers   int    `yaml:"workers"`
+	QueueSize int    `yaml:"queue_size"`
+	Store     string `yaml:"store"`
+	StorePath string `yaml:"store_path"`
+	// Retention and MaxFinished bound the finished jobs the store keeps; zero turns either
+	// bound off.
+	Retention   time.Duration `yaml:"retention"`
+	MaxFinished int           `yaml:"max_finished"`
+}
+
+func defaultJob() *Job {
+	return &Job{
+		Workers:     4,
+		QueueSize:   100,
+		Store:       "memory",
+		StorePath:   "jobs.db",
+		Retention:   24 * time.Hour,
+		MaxFinished: 10000,
+	}
+}
+
+func (j *Job) applyEnv(e *env) {
+	e.int("JOB_WORKERS", &j.Workers)
+	e.int("JOB_QUEUE_SIZE", &j.QueueSize)
+	e.string("JOB_STORE", &j.Store)
+	e.string("JOB_STORE_PATH", &j.StorePath)
+	e.duration("JOB_RETENTION", &j.Retention)
+	e.int("JOB_MAX_FINISHED", &j.MaxFinished)
+}
+
+func (j *Job) validate() error {
+	return errors.Join(
+		check(j.Workers > 0, "jobs.workers", "must be positive"),
+		check(j.QueueSize > 0, "jobs.queue_size", "must be positive"),
+		check(oneOf(j.Store, "memory", "bolt"), "jobs.store", "must be memory or bolt, got %q", j.Store),
+		check(j.Store != "bolt" || j.StorePath != "", "jobs.store_path", "is required for the bolt store"),
+		check(j.Retention >= 0, "jobs.retention", "must not be negative"),
+		check(j.MaxFinished >= 0, "jobs.max_finished", "must not be negative"),
+	)
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+type Logging struct {
+	Level string `yaml:"level"`
+}
+
+func defaultLogging() *Logging {
+	return &Logging{
+		Level: "info",
+	}
+}
+
+func (l *Logging) applyEnv(e *env) {
+	e.string("LOG_LEVEL", &l.Level)
+}
+
+func (l *Logging) validate() error {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.ToUpper(l.Level)))
+	return check(err == nil, "logging.level", "must be debug, info, warn or error, got %q", l.Level)
+}
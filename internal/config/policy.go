@@ -0,0 +1,132 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Policy holds per-procedure behaviour. It is written inline in the config file or kept in
+// the JSON file named by policy_file.
+type Policy struct {
+	Redaction  RedactionPolicy            `json:"redaction" yaml:"redaction"`
+	RateLimits RateLimitPolicy            `json:"rate_limits" yaml:"rate_limits"`
+	Procedures map[string]ProcedurePolicy `json:"procedures" yaml:"procedures"`
+}
+
+type RedactionPolicy struct {
+	Mode     string   `json:"mode" yaml:"mode"`
+	Patterns []string `json:"patterns" yaml:"patterns"`
+	HashKey  string   `json:"-" yaml:"-"`
+}
+
+// RateLimit allows Rate requests per second with bursts of up to Burst requests.
+type RateLimit struct {
+	Rate  float64 `json:"rate" yaml:"rate"`
+	Burst int     `json:"burst" yaml:"burst"`
+}
+
+type RateLimitPolicy struct {
+	PerPrincipal RateLimit            `json:"per_principal" yaml:"per_principal"`
+	Principals   map[string]RateLimit `json:"principals" yaml:"principals"`
+}
+
+type ProcedurePolicy struct {
+	SensitiveParams []string  `json:"sensitive_params" yaml:"sensitive_params"`
+	RateLimit       RateLimit `json:"rate_limit" yaml:"rate_limit"`
+	MaxConcurrent   int       `json:"max_concurrent" yaml:"max_concurrent"`
+	// Idempotent procedures are retried after connection errors.
+	Idempotent bool `json:"idempotent" yaml:"idempotent"`
+	// CacheTTL, for example "5m", enables response caching for a read-only procedure.
+	CacheTTL string `json:"cache_ttl" yaml:"cache_ttl"`
+	// ReadOnly procedures run on the replica when one is configured. It is the only way to
+	// mark them: the data dictionary has no flag saying a procedure does not write, and
+	// neither DETERMINISTIC nor PRAGMA RESTRICT_REFERENCES guarantees it, so procedures
+	// without it always run on the primary.
+	ReadOnly bool `json:"read_only" yaml:"read_only"`
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	policy := &Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("parse policy file: %w", err)
+	}
+	return policy, nil
+}
+
+// applyEnv lets REDACT_MODE override the policy. The hash key is a secret, so it is only
+// ever read from REDACT_HASH_KEY.
+func (p *Policy) applyEnv(e *env) {
+	e.string("REDACT_MODE", &p.Redaction.Mode)
+	e.string("REDACT_HASH_KEY", &p.Redaction.HashKey)
+}
+
+func (p *Policy) validate() error {
+	errs := []error{
+		check(oneOf(p.Redaction.Mode, "", "mask", "hash"), "policy.redaction.mode", "must be mask or hash, got %q", p.Redaction.Mode),
+		check(p.Redaction.Mode != "hash" || p.Redaction.HashKey != "", "policy.redaction", "REDACT_HASH_KEY is required in hash mode"),
+		validateRateLimit("policy.rate_limits.per_principal", p.RateLimits.PerPrincipal),
+	}
+	for name, limit := range p.RateLimits.Principals {
+		errs = append(errs, validateRateLimit("policy.rate_limits.principals."+name, limit))
+	}
+
+	for name, procedure := range p.Procedures {
+		field := "policy.procedures." + name
+		errs = append(errs,
+			validateRateLimit(field+".rate_limit", procedure.RateLimit),
+			check(procedure.MaxConcurrent >= 0, field+".max_concurrent", "must not be negative"),
+		)
+		if procedure.CacheTTL != "" {
+			ttl, err := time.ParseDuration(procedure.CacheTTL)
+			errs = append(errs, check(err == nil && ttl > 0, field+".cache_ttl", "must be a positive duration such as 5m, got %q", procedure.CacheTTL))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateRateLimit(field string, limit RateLimit) error {
+	return errors.Join(
+		check(limit.Rate >= 0, field+".rate", "must not be negative"),
+		check(limit.Burst >= 0, field+".burst", "must not be negative"),
+	)
+}
+
+func (p *Policy) IdempotentProcedures() []string {
+	var names []string
+	for name, procedure := range p.Procedures {
+		if procedure.Idempotent {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (p *Policy) ReadOnlyProcedures() []string {
+	var names []string
+	for name, procedure := range p.Procedures {
+		if procedure.ReadOnly {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CacheTTLs returns the cache lifetime of every cacheable procedure. Validate has
+// already rejected invalid values.
+func (p *Policy) CacheTTLs() map[string]time.Duration {
+	ttls := make(map[string]time.Duration)
+	for name, procedure := range p.Procedures {
+		if ttl, err := time.ParseDuration(procedure.CacheTTL); err == nil {
+			ttls[name] = ttl
+		}
+	}
+	return ttls
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Resilience struct {
+	BreakerFailureThreshold int           `yaml:"breaker_failure_threshold"`
+	BreakerOpenTimeout      time.Duration `yaml:"breaker_open_timeout"`
+	RetryMaxAttempts        int           `yaml:"retry_max_attempts"`
+	RetryBaseDelay          time.Duration `yaml:"retry_base_delay"`
+	RetryMaxDelay           time.Duration `yaml:"retry_max_delay"`
+}
+
+func defaultResilience() *Resilience {
+	return &Resilience{
+		BreakerFailureThreshold: 5,
+		BreakerOpenTimeout:      30 * time.Second,
+		RetryMaxAttempts:        3,
+		RetryBaseDelay:          200 * time.Millisecond,
+		RetryMaxDelay:           2 * time.Second,
+	}
+}
+
+func (r *Resilience) applyEnv(e *env) {
+	e.int("BREAKER_FAILURE_THRESHOLD", &r.BreakerFailureThreshold)
+	e.duration("BREAKER_OPEN_TIMEOUT", &r.BreakerOpenTimeout)
+	e.int("RETRY_MAX_ATTEMPTS", &r.RetryMaxAttempts)
+	e.duration("RETRY_BASE_DELAY", &r.RetryBaseDelay)
+	e.duration("RETRY_MAX_DELAY", &r.RetryMaxDelay)
+}
+
+func (r *Resilience) validate() error {
+	return errors.Join(
+		check(r.BreakerFailureThreshold > 0, "resilience.breaker_failure_threshold", "must be positive"),
+		check(r.BreakerOpenTimeout > 0, "resilience.breaker_open_timeout", "must be positive"),
+		check(r.RetryMaxAttempts > 0, "resilience.retry_max_attempts", "must be positive"),
+		check(r.RetryBaseDelay >= 0, "resilience.retry_base_delay", "must not be negative"),
+		check(r.RetryMaxDelay >= r.RetryBaseDelay, "resilience.retry_max_delay", "must not be less than retry_base_delay"),
+	)
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Scheduler struct {
+	File string `yaml:"file"`
+	// Lock is none for a single instance, or dbms_lock when several instances share the
+	// schedules, which then needs State oracle.
+	Lock        string        `yaml:"lock"`
+	LockPrefix  string        `yaml:"lock_prefix"`
+	LockHold    time.Duration `yaml:"lock_hold"`
+	HistorySize int           `yaml:"history_size"`
+	// State is memory or oracle. Oracle keeps pause flags and run history in StateTable
+	// and RunsTable, shared by all instances. In memory they belong to one instance.
+	State      string `yaml:"state"`
+	StateTable string `yaml:"state_table"`
+	RunsTable  string `yaml:"runs_table"`
+}
+
+func defaultScheduler() *Scheduler {
+	return &Scheduler{
+		Lock:        "none",
+		LockPrefix:  "ORACLE_GOLANG_SCHEDULE_",
+		LockHold:    30 * time.Second,
+		HistorySize: 50,
+		State:       "memory",
+		StateTable:  "SCHEDULE_STATE",
+		RunsTable:   "SCHEDULE_RUNS",
+	}
+}
+
+func (s *Scheduler) applyEnv(e *env) {
+	e.string("SCHEDULES_FILE", &s.File)
+	e.string("SCHEDULER_LOCK", &s.Lock)
+	e.string("SCHEDULER_LOCK_PREFIX", &s.LockPrefix)
+	e.duration("SCHEDULER_LOCK_HOLD", &s.LockHold)
+	e.int("SCHEDULER_HISTORY_SIZE", &s.HistorySize)
+	e.string("SCHEDULER_STATE", &s.State)
+	e.string("SCHEDULER_STATE_TABLE", &s.StateTable)
+	e.string("SCHEDULER_RUNS_TABLE", &s.RunsTable)
+}
+
+func (s *Scheduler) validate() error {
+	return errors.Join(
+		check(oneOf(s.Lock, "dbms_lock", "none"), "scheduler.lock", "must be dbms_lock or none, got %q", s.Lock),
+		check(s.LockHold >= 0, "scheduler.lock_hold", "must not be negative"),
+		check(s.HistorySize > 0, "scheduler.history_size", "must be positive"),
+		check(oneOf(s.State, "memory", "oracle"), "scheduler.state", "must be memory or oracle, got %q", s.State),
+		check(s.State != "oracle" || (s.StateTable != "" && s.RunsTable != ""), "scheduler.state_table", "state_table and runs_table are required with state oracle"),
+		check(s.Lock != "dbms_lock" || s.State == "oracle", "scheduler.state", "must be oracle with lock dbms_lock, as pause flags kept in memory are not shared between instances"),
+	)
+}
@@ -1,11 +1,69 @@
 package config
 
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
 type Server struct {
-	Port string
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	RequestTimeout  time.Duration `yaml:"request_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	TLS TLS `yaml:"tls"`
 }
 
-func newServer() *Server {
+// TLS is enabled when both files are set.
+type TLS struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+func defaultServer() *Server {
 	return &Server{
-		Port: ":" + getEnv("SERVER_PORT", "8080"),
+		Port:            8080,
+		ReadTimeout:     30 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		IdleTimeout:     120 * time.Second,
+		RequestTimeout:  60 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
 	}
 }
+
+func (s *Server) applyEnv(e *env) {
+	e.string("SERVER_HOST", &s.Host)
+	e.int("SERVER_PORT", &s.Port)
+	e.duration("SERVER_READ_TIMEOUT", &s.ReadTimeout)
+	e.duration("SERVER_WRITE_TIMEOUT", &s.WriteTimeout)
+	e.duration("SERVER_IDLE_TIMEOUT", &s.IdleTimeout)
+	e.duration("SERVER_REQUEST_TIMEOUT", &s.RequestTimeout)
+	e.duration("SERVER_SHUTDOWN_TIMEOUT", &s.ShutdownTimeout)
+	e.string("SERVER_TLS_CERT_FILE", &s.TLS.CertFile)
+	e.string("SERVER_TLS_KEY_FILE", &s.TLS.KeyFile)
+}
+
+func (s *Server) validate() error {
+	return errors.Join(
+		check(s.Port > 0 && s.Port <= 65535, "server.port", "must be between 1 and 65535, got %d", s.Port),
+		check(s.ReadTimeout > 0, "server.read_timeout", "must be positive"),
+		check(s.WriteTimeout > 0, "server.write_timeout", "must be positive"),
+		check(s.IdleTimeout > 0, "server.idle_timeout", "must be positive"),
+		check(s.RequestTimeout > 0, "server.request_timeout", "must be positive"),
+		check(s.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive"),
+		check((s.TLS.CertFile == "") == (s.TLS.KeyFile == ""), "server.tls", "cert_file and key_file must be set together"),
+	)
+}
+
+func (s *Server) Addr() string {
+	return s.Host + ":" + strconv.Itoa(s.Port)
+}
+
+func (s *Server) TLSEnabled() bool {
+	return s.TLS.CertFile != "" && s.TLS.KeyFile != ""
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"time"
+)
+
+// Sessions opens database sessions as the calling end user instead of the application
+// user, for procedures that rely on USER or VPD policies. In proxy mode the service
+// connects as the configured database user and opens a proxy session for the principal,
+// mapped through Users; principals missing from Users are rejected unless AllowUnmapped
+// says they are database user names already. In credentials mode each principal logs in
+// with its own entry in Credentials. Callers without an authenticated principal are
+// rejected, or use the shared pool when Required is turned off.
+type Sessions struct {
+	Mode          string                `yaml:"mode"`
+	Required      bool                  `yaml:"required"`
+	Users         map[string]string     `yaml:"users"`
+	AllowUnmapped bool                  `yaml:"allow_unmapped"`
+	Credentials   map[string]Credential `yaml:"credentials"`
+
+	// MaxUsers bounds the number of per-user pools kept open; the least recently used idle
+	// pool is closed to make room.
+	MaxUsers            int           `yaml:"max_users"`
+	MaxOpenConnsPerUser int           `yaml:"max_open_conns_per_user"`
+	IdleTimeout         time.Duration `yaml:"idle_timeout"`
+}
+
+type Credential struct {
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+}
+
+func defaultSessions() *Sessions {
+	return &Sessions{
+		Mode:                "none",
+		Required:            true,
+		MaxUsers:            100,
+		MaxOpenConnsPerUser: 2,
+		IdleTimeout:         10 * time.Minute,
+	}
+}
+
+func (s *Sessions) applyEnv(e *env) {
+	e.string("SESSION_MODE", &s.Mode)
+	e.bool("SESSION_REQUIRED", &s.Required)
+	e.bool("SESSION_ALLOW_UNMAPPED", &s.AllowUnmapped)
+	e.int("SESSION_MAX_USERS", &s.MaxUsers)
+	e.int("SESSION_MAX_OPEN_CONNS_PER_USER", &s.MaxOpenConnsPerUser)
+	e.duration("SESSION_IDLE_TIMEOUT", &s.IdleTimeout)
+}
+
+func (s *Sessions) validate() error {
+	errs := []error{
+		check(oneOf(s.Mode, "none", "proxy", "credentials"), "sessions.mode", "must be none, proxy or credentials, got %q", s.Mode),
+		check(s.Mode != "credentials" || len(s.Credentials) > 0, "sessions.credentials", "is required in credentials mode"),
+		check(s.Mode != "proxy" || len(s.Users) > 0 || s.AllowUnmapped, "sessions.users", "is required in proxy mode unless allow_unmapped is set"),
+		check(s.MaxUsers > 0, "sessions.max_users", "must be positive"),
+		check(s.MaxOpenConnsPerUser > 0, "sessions.max_open_conns_per_user", "must be positive"),
+		check(s.IdleTimeout > 0, "sessions.idle_timeout", "must be positive"),
+	}
+	for principal, c := range s.Credentials {
+		errs = append(errs, check(c.User != "", "sessions.credentials."+principal+".user", "is required"))
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether sessions are opened per end user.
+func (s *Sessions) Enabled() bool {
+	return s.Mode == "proxy" || s.Mode == "credentials"
+}
+
+// ProxyFor returns a copy that opens proxy sessions for user through o's credentials.
+func (o *OracleDatabase) ProxyFor(user string) *OracleDatabase {
+	c := *o
+	c.Options = maps.Clone(o.Options)
+	if c.Options == nil {
+		c.Options = make(map[string]string)
+	}
+	c.Options["PROXY CLIENT NAME"] = user
+	return &c
+}
+
+// As returns a copy that logs in with cred instead of o's credentials.
+func (o *OracleDatabase) As(cred Credential) *OracleDatabase {
+	c := *o
+	c.User = cred.User
+	c.Password = cred.Password
+	return &c
+}
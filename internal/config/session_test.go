@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sijms/go-ora/v2/configurations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Sessions(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+auth:
+  trusted_proxies: [10.0.0.0/8]
+sessions:
+  mode: credentials
+  credentials:
+    svc-report:
+      user: report
+      password: secret
+`)
+	t.Setenv("SESSION_IDLE_TIMEOUT", "30s")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.Sessions.Enabled())
+	assert.True(t, cfg.Sessions.Required)
+	assert.Equal(t, Credential{User: "report", Password: "secret"}, cfg.Sessions.Credentials["svc-report"])
+	assert.Equal(t, 100, cfg.Sessions.MaxUsers)
+	assert.Equal(t, 30*time.Second, cfg.Sessions.IdleTimeout)
+
+	_, err = Load(writeFile(t, "config.yaml", `
+sessions:
+  mode: credentials
+  max_users: 0
+`))
+	assert.ErrorContains(t, err, "sessions.credentials: is required in credentials mode")
+	assert.ErrorContains(t, err, "sessions.max_users: must be positive")
+
+	_, err = Load(writeFile(t, "config.yaml", `
+auth:
+  trusted_proxies: [10.0.0.0/8, gateway]
+sessions:
+  mode: proxy
+`))
+	assert.ErrorContains(t, err, "sessions.users: is required in proxy mode unless allow_unmapped is set")
+	assert.ErrorContains(t, err, `auth.trusted_proxies: "gateway" is not a CIDR block or IP address`)
+
+	_, err = Load(writeFile(t, "config.yaml", "sessions:\n  mode: proxy\n  allow_unmapped: true\n"))
+	assert.ErrorContains(t, err, "auth.trusted_proxies: is required when sessions are enabled")
+
+	_, err = Load(writeFile(t, "config.yaml", "sessions:\n  mode: sudo\n"))
+	assert.ErrorContains(t, err, `sessions.mode: must be none, proxy or credentials, got "sudo"`)
+}
+
+func TestOracleDatabase_ProxyFor(t *testing.T) {
+	db := defaultOracleDatabase()
+	db.Options = map[string]string{"PREFETCH_ROWS": "100"}
+
+	proxy := db.ProxyFor("ALICE")
+	assert.Equal(t, "ALICE", proxy.Options["PROXY CLIENT NAME"])
+	assert.Equal(t, "100", proxy.Options["PREFETCH_ROWS"])
+	assert.NotContains(t, db.Options, "PROXY CLIENT NAME")
+
+	dsn, err := proxy.DSN()
+	require.NoError(t, err)
+	parsed, err := configurations.ParseConfig(dsn)
+	require.NoError(t, err)
+	assert.Equal(t, "ALICE", parsed.DatabaseInfo.ProxyClientName)
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Tenants routes each request to the schema of its tenant, for deployments with one schema
+// per tenant holding identical packages. The tenant is read from a header, a JWT claim or
+// the subdomain, and only tenants listed in Schemas are accepted.
+type Tenants struct {
+	Source    string `yaml:"source"`
+	Header    string `yaml:"header"`
+	Claim     string `yaml:"claim"`
+	JWTSecret string `yaml:"jwt_secret"`
+	// TrustGatewayVerified accepts tokens without a JWTSecret, for API gateways that verify
+	// them before they reach the service. Anyone who can reach the service directly can
+	// then choose their tenant.
+	TrustGatewayVerified bool   `yaml:"trust_gateway_verified"`
+	Domain               string `yaml:"domain"`
+
+	// Mode is prefix, to qualify procedure names with the schema, or current_schema, to set
+	// CURRENT_SCHEMA on the connection for each call.
+	Mode     string            `yaml:"mode"`
+	Required bool              `yaml:"required"`
+	Schemas  map[string]string `yaml:"schemas"`
+}
+
+// schemaName matches unquoted Oracle schema names, the only ones used in ALTER SESSION.
+var schemaName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]{0,127}$`)
+
+func defaultTenants() *Tenants {
+	return &Tenants{
+		Source:   "none",
+		Header:   "X-Tenant",
+		Claim:    "tenant",
+		Mode:     "current_schema",
+		Required: true,
+	}
+}
+
+func (t *Tenants) applyEnv(e *env) {
+	e.string("TENANT_SOURCE", &t.Source)
+	e.string("TENANT_HEADER", &t.Header)
+	e.string("TENANT_CLAIM", &t.Claim)
+	e.string("TENANT_JWT_SECRET", &t.JWTSecret)
+	e.bool("TENANT_TRUST_GATEWAY_VERIFIED", &t.TrustGatewayVerified)
+	e.string("TENANT_DOMAIN", &t.Domain)
+	e.string("TENANT_MODE", &t.Mode)
+	e.bool("TENANT_REQUIRED", &t.Required)
+}
+
+func (t *Tenants) validate() error {
+	errs := []error{
+		check(oneOf(t.Source, "none", "header", "jwt", "subdomain"), "tenants.source", "must be none, header, jwt or subdomain, got %q", t.Source),
+		check(oneOf(t.Mode, "prefix", "current_schema"), "tenants.mode", "must be prefix or current_schema, got %q", t.Mode),
+	}
+	if !t.Enabled() {
+		return errors.Join(errs...)
+	}
+
+	errs = append(errs,
+		check(t.Source != "header" || t.Header != "", "tenants.header", "is required with source header"),
+		check(t.Source != "jwt" || t.Claim != "", "tenants.claim", "is required with source jwt"),
+		check(t.Source != "jwt" || t.JWTSecret != "" || t.TrustGatewayVerified, "tenants.jwt_secret", "is required with source jwt unless trust_gateway_verified is set"),
+		check(t.Source != "subdomain" || t.Domain != "", "tenants.domain", "is required with source subdomain"),
+		check(len(t.Schemas) > 0, "tenants.schemas", "is required"),
+	)
+	owners := make(map[string]string, len(t.Schemas))
+	for _, name := range slices.Sorted(maps.Keys(t.Schemas)) {
+		schema := strings.ToUpper(t.Schemas[name])
+		errs = append(errs, check(schemaName.MatchString(schema), "tenants.schemas."+name, "must be an unquoted schema name, got %q", t.Schemas[name]))
+		if other, ok := owners[schema]; ok {
+			errs = append(errs, check(false, "tenants.schemas."+name, "schema %s is already used by tenant %s", schema, other))
+		}
+		owners[schema] = name
+	}
+	return errors.Join(errs...)
+}
+
+// Enabled reports whether requests are routed by tenant.
+func (t *Tenants) Enabled() bool {
+	return t.Source != "none"
+}
+
+// SchemaNames returns the schemas of all tenants.
+func (t *Tenants) SchemaNames() []string {
+	names := make([]string, 0, len(t.Schemas))
+	for _, name := range slices.Sorted(maps.Keys(t.Schemas)) {
+		names = append(names, strings.ToUpper(t.Schemas[name]))
+	}
+	return names
+}
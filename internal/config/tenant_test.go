@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Tenants(t *testing.T) {
+	_, err := Load(writeFile(t, "config.yaml", `
+tenants:
+  source: subdomain
+  mode: vpd
+  schemas:
+    acme: acme
+    acme_eu: ACME
+    initech: "initech; drop user x"
+`))
+	require.Error(t, err)
+	for _, msg := range []string{
+		`tenants.mode: must be prefix or current_schema, got "vpd"`,
+		"tenants.domain: is required with source subdomain",
+		"tenants.schemas.acme_eu: schema ACME is already used by tenant acme",
+		`tenants.schemas.initech: must be an unquoted schema name, got "initech; drop user x"`,
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+
+	path := writeFile(t, "config.yaml", `
+tenants:
+  source: subdomain
+  domain: api.example.com
+  schemas:
+    acme: acme
+    globex: globex_prod
+`)
+	t.Setenv("TENANT_MODE", "prefix")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.Tenants.Enabled())
+	assert.True(t, cfg.Tenants.Required)
+	assert.Equal(t, "prefix", cfg.Tenants.Mode)
+	assert.Equal(t, []string{"ACME", "GLOBEX_PROD"}, cfg.Tenants.SchemaNames())
+}
+
+func TestLoad_TenantJWT(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+tenants:
+  source: jwt
+  schemas:
+    acme: acme
+`)
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "tenants.jwt_secret: is required with source jwt unless trust_gateway_verified is set")
+
+	t.Setenv("TENANT_TRUST_GATEWAY_VERIFIED", "true")
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, cfg.Tenants.TrustGatewayVerified)
+}
+
+func TestLoad_TenantHeader(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+tenants:
+  source: header
+  schemas:
+    acme: acme
+`)
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "tenants.source: header needs auth.trusted_proxies, as the header is only accepted from the API gateway")
+
+	t.Setenv("AUTH_TRUSTED_PROXIES", "10.0.0.0/8")
+	_, err = Load(path)
+	require.NoError(t, err)
+}
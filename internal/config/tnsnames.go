@@ -0,0 +1,110 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lookupTNSAlias returns the connect descriptor of alias from dir/tnsnames.ora.
+// Aliases are matched case-insensitively, with or without their domain.
+func lookupTNSAlias(dir, alias string) (string, error) {
+	path := filepath.Join(dir, "tnsnames.ora")
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open tnsnames.ora: %w", err)
+	}
+	defer f.Close()
+
+	entries, err := parseTNSNames(f)
+	if err != nil {
+		return "", fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	alias = strings.ToUpper(strings.TrimSpace(alias))
+	if descriptor, ok := entries[alias]; ok {
+		return descriptor, nil
+	}
+	for name, descriptor := range entries {
+		if base, _, found := strings.Cut(name, "."); found && base == alias {
+			return descriptor, nil
+		}
+	}
+	return "", fmt.Errorf("alias %s not found in %s", alias, path)
+}
+
+// parseTNSNames reads "ALIAS[, ALIAS...] = (DESCRIPTION = ...)" entries into a map keyed by
+// upper-cased alias. Comments and entries without a parenthesised value, such as IFILE, are
+// skipped.
+func parseTNSNames(r io.Reader) (map[string]string, error) {
+	var sb strings.Builder
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		sb.WriteString(line)
+		sb.WriteByte(' ')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]string)
+	text := sb.String()
+	for {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return entries, nil
+		}
+
+		eq := strings.IndexByte(text, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("expected '=' after %q", text)
+		}
+		names := text[:eq]
+		text = strings.TrimSpace(text[eq+1:])
+
+		if !strings.HasPrefix(text, "(") {
+			// A plain value such as IFILE = /path; skip it.
+			end := strings.IndexAny(text, " \t")
+			if end < 0 {
+				end = len(text)
+			}
+			text = text[end:]
+			continue
+		}
+
+		end, err := closingParen(text)
+		if err != nil {
+			return nil, fmt.Errorf("entry %s: %w", strings.TrimSpace(names), err)
+		}
+		descriptor := strings.Join(strings.Fields(text[:end+1]), " ")
+		text = text[end+1:]
+
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.ToUpper(strings.TrimSpace(name)); name != "" {
+				entries[name] = descriptor
+			}
+		}
+	}
+}
+
+// closingParen returns the index of the parenthesis that closes the one at text[0].
+func closingParen(text string) (int, error) {
+	depth := 0
+	for i, c := range text {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, errors.New("unbalanced parentheses")
+}
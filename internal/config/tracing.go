@@ -0,0 +1,22 @@
+package config
+
+type Tracing struct {
+	Exporter    string `yaml:"exporter"`
+	ServiceName string `yaml:"service_name"`
+}
+
+func defaultTracing() *Tracing {
+	return &Tracing{
+		Exporter:    "none",
+		ServiceName: "oracle-golang",
+	}
+}
+
+func (t *Tracing) applyEnv(e *env) {
+	e.string("OTEL_TRACES_EXPORTER", &t.Exporter)
+	e.string("OTEL_SERVICE_NAME", &t.ServiceName)
+}
+
+func (t *Tracing) validate() error {
+	return check(oneOf(t.Exporter, "none", "stdout", "otlp"), "tracing.exporter", "must be none, stdout or otlp, got %q", t.Exporter)
+}
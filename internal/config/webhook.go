@@ -0,0 +1,48 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Webhook struct {
+	// Secret signs callback deliveries. Without it callbacks are disabled, and jobs that ask
+	// for one are refused.
+	Secret string `yaml:"secret"`
+	// AllowPrivateNetworks lets callbacks reach loopback, link-local and private addresses.
+	AllowPrivateNetworks bool          `yaml:"allow_private_networks"`
+	MaxAttempts          int           `yaml:"max_attempts"`
+	BaseDelay            time.Duration `yaml:"base_delay"`
+	MaxDelay             time.Duration `yaml:"max_delay"`
+	Timeout              time.Duration `yaml:"timeout"`
+	DeadLetterPath       string        `yaml:"dead_letter_path"`
+}
+
+func defaultWebhook() *Webhook {
+	return &Webhook{
+		MaxAttempts:    5,
+		BaseDelay:      time.Second,
+		MaxDelay:       5 * time.Minute,
+		Timeout:        10 * time.Second,
+		DeadLetterPath: "webhook-dead-letters.jsonl",
+	}
+}
+
+func (w *Webhook) applyEnv(e *env) {
+	e.string("WEBHOOK_SECRET", &w.Secret)
+	e.bool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", &w.AllowPrivateNetworks)
+	e.int("WEBHOOK_MAX_ATTEMPTS", &w.MaxAttempts)
+	e.duration("WEBHOOK_BASE_DELAY", &w.BaseDelay)
+	e.duration("WEBHOOK_MAX_DELAY", &w.MaxDelay)
+	e.duration("WEBHOOK_TIMEOUT", &w.Timeout)
+	e.string("WEBHOOK_DEAD_LETTER_PATH", &w.DeadLetterPath)
+}
+
+func (w *Webhook) validate() error {
+	return errors.Join(
+		check(w.MaxAttempts > 0, "webhook.max_attempts", "must be positive"),
+		check(w.BaseDelay >= 0, "webhook.base_delay", "must not be negative"),
+		check(w.MaxDelay >= w.BaseDelay, "webhook.max_delay", "must not be less than base_delay"),
+		check(w.Timeout > 0, "webhook.timeout", "must be positive"),
+	)
+}
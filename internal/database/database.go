@@ -3,22 +3,34 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"time"
 )
 
 type Database interface {
 	Connect(dsn string) (*sql.DB, error)
 }
 
-type OracleDatabase struct{}
+// PoolOptions mirrors the connection pool settings of sql.DB. Zero values keep the
+// database/sql defaults.
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+type OracleDatabase struct {
+	pool PoolOptions
+}
 
-func NewOracleDatabase() *OracleDatabase {
-	return &OracleDatabase{}
+func NewOracleDatabase(pool PoolOptions) *OracleDatabase {
+	return &OracleDatabase{pool: pool}
 }
 
 func (o *OracleDatabase) Connect(dsn string) (*sql.DB, error) {
-	conn, err := sql.Open("oracle", dsn)
+	conn, err := o.Open(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("db connection error: %w", err)
+		return nil, err
 	}
 
 	err = conn.Ping()
@@ -28,3 +40,30 @@ func (o *OracleDatabase) Connect(dsn string) (*sql.DB, error) {
 
 	return conn, nil
 }
+
+// Open configures a pool for dsn without connecting. The first connection is made when
+// the pool is used.
+func (o *OracleDatabase) Open(dsn string) (*sql.DB, error) {
+	conn, err := sql.Open("oracle", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("db connection error: %w", err)
+	}
+
+	o.configurePool(conn)
+	return conn, nil
+}
+
+func (o *OracleDatabase) configurePool(conn *sql.DB) {
+	if o.pool.MaxOpenConns > 0 {
+		conn.SetMaxOpenConns(o.pool.MaxOpenConns)
+	}
+	if o.pool.MaxIdleConns > 0 {
+		conn.SetMaxIdleConns(o.pool.MaxIdleConns)
+	}
+	if o.pool.ConnMaxLifetime > 0 {
+		conn.SetConnMaxLifetime(o.pool.ConnMaxLifetime)
+	}
+	if o.pool.ConnMaxIdleTime > 0 {
+		conn.SetConnMaxIdleTime(o.pool.ConnMaxIdleTime)
+	}
+}
@@ -0,0 +1,116 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"sort"
+	"time"
+)
+
+type Service interface {
+	CallProcedure(ctx context.Context, r request.CallProcedureRequest) (response.CallProcedureResponse, error)
+	GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error)
+	ListProcedures(ctx context.Context, r request.ListProceduresRequest) (response.ListProceduresResponse, error)
+	CacheTTL(ctx context.Context, r request.CallProcedureRequest) (time.Duration, bool)
+}
+
+// UnknownError is returned for a data source that is not configured, or with an empty Name
+// when the call names none and there is no default.
+type UnknownError struct {
+	Name string
+}
+
+func (e *UnknownError) Error() string {
+	if e.Name == "" {
+		return "datasource is required"
+	}
+	return fmt.Sprintf("unknown datasource: %s", e.Name)
+}
+
+func (e *UnknownError) StatusCode() int {
+	if e.Name == "" {
+		return http.StatusBadRequest
+	}
+	return http.StatusNotFound
+}
+
+type contextKey struct{}
+
+// WithName selects the data source for calls that do not carry a request, such as
+// GetProcedureInfo.
+func WithName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+func NameFrom(ctx context.Context) string {
+	name, _ := ctx.Value(contextKey{}).(string)
+	return name
+}
+
+// Router sends each call to the service of its data source. Calls name it in
+// CallProcedureRequest.Datasource or through WithName; the rest go to the fallback.
+type Router struct {
+	services map[string]Service
+	fallback string
+}
+
+func NewRouter(services map[string]Service, fallback string) *Router {
+	return &Router{services: services, fallback: fallback}
+}
+
+// Names returns the configured data sources in alphabetical order.
+func (r *Router) Names() []string {
+	names := make([]string, 0, len(r.services))
+	for name := range r.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Router) CallProcedure(ctx context.Context, req request.CallProcedureRequest) (response.CallProcedureResponse, error) {
+	svc, err := r.lookup(req.Datasource)
+	if err != nil {
+		return nil, err
+	}
+	return svc.CallProcedure(ctx, req)
+}
+
+func (r *Router) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	svc, err := r.lookup(NameFrom(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetProcedureInfo(ctx, procedureName)
+}
+
+func (r *Router) ListProcedures(ctx context.Context, req request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	svc, err := r.lookup(req.Datasource)
+	if err != nil {
+		return response.ListProceduresResponse{}, err
+	}
+	return svc.ListProcedures(ctx, req)
+}
+
+func (r *Router) CacheTTL(ctx context.Context, req request.CallProcedureRequest) (time.Duration, bool) {
+	svc, err := r.lookup(req.Datasource)
+	if err != nil {
+		return 0, false
+	}
+	return svc.CacheTTL(ctx, req)
+}
+
+func (r *Router) lookup(name string) (Service, error) {
+	if name == "" {
+		name = r.fallback
+	}
+
+	svc, ok := r.services[name]
+	if !ok {
+		return nil, &UnknownError{Name: name}
+	}
+	return svc, nil
+}
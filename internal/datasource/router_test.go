@@ -0,0 +1,107 @@
+package datasource
+
+import (
+	"context"
+	"net/http"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeService struct {
+	name string
+	ttl  time.Duration
+}
+
+func (s fakeService) CallProcedure(context.Context, request.CallProcedureRequest) (response.CallProcedureResponse, error) {
+	return response.CallProcedureResponse{"datasource": s.name}, nil
+}
+
+func (s fakeService) GetProcedureInfo(context.Context, string) (*response.GetProcedureInfoResponse, error) {
+	return &response.GetProcedureInfoResponse{Name: s.name}, nil
+}
+
+func (s fakeService) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{Items: []response.ProcedureSummary{{Owner: s.name}}}, nil
+}
+
+func (s fakeService) CacheTTL(context.Context, request.CallProcedureRequest) (time.Duration, bool) {
+	return s.ttl, s.ttl > 0
+}
+
+func newTestRouter(fallback string) *Router {
+	return NewRouter(map[string]Service{
+		"core":    fakeService{name: "core"},
+		"billing": fakeService{name: "billing", ttl: time.Minute},
+	}, fallback)
+}
+
+func TestRouter_CallProcedure(t *testing.T) {
+	r := newTestRouter("core")
+
+	tests := []struct {
+		name       string
+		datasource string
+		want       string
+		status     int
+	}{
+		{name: "named", datasource: "billing", want: "billing"},
+		{name: "default", want: "core"},
+		{name: "unknown", datasource: "dwh", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.CallProcedure(context.Background(), request.CallProcedureRequest{Name: "pkg.proc", Datasource: tt.datasource})
+			if tt.status != 0 {
+				var unknown *UnknownError
+				require.ErrorAs(t, err, &unknown)
+				assert.Equal(t, tt.status, unknown.StatusCode())
+				assert.EqualError(t, err, "unknown datasource: "+tt.datasource)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result["datasource"])
+		})
+	}
+}
+
+func TestRouter_NoDefault(t *testing.T) {
+	r := newTestRouter("")
+
+	_, err := r.CallProcedure(context.Background(), request.CallProcedureRequest{Name: "pkg.proc"})
+	var unknown *UnknownError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, http.StatusBadRequest, unknown.StatusCode())
+	assert.EqualError(t, err, "datasource is required")
+}
+
+func TestRouter_GetProcedureInfoUsesContext(t *testing.T) {
+	r := newTestRouter("core")
+
+	result, err := r.GetProcedureInfo(WithName(context.Background(), "billing"), "pkg.proc")
+	require.NoError(t, err)
+	assert.Equal(t, "billing", result.Name)
+
+	result, err = r.GetProcedureInfo(context.Background(), "pkg.proc")
+	require.NoError(t, err)
+	assert.Equal(t, "core", result.Name)
+}
+
+func TestRouter_CacheTTL(t *testing.T) {
+	r := newTestRouter("core")
+
+	ttl, ok := r.CacheTTL(context.Background(), request.CallProcedureRequest{Name: "pkg.proc", Datasource: "billing"})
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, ttl)
+
+	_, ok = r.CacheTTL(context.Background(), request.CallProcedureRequest{Name: "pkg.proc"})
+	assert.False(t, ok)
+	_, ok = r.CacheTTL(context.Background(), request.CallProcedureRequest{Name: "pkg.proc", Datasource: "dwh"})
+	assert.False(t, ok)
+	assert.Equal(t, []string{"billing", "core"}, r.Names())
+}
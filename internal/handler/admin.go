@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"database/sql"
+	"net/http"
+	"oracle-golang/internal/model/response"
+)
+
+type DBStatsProvider interface {
+	Stats() sql.DBStats
+}
+
+type AdminHandler struct {
+	db DBStatsProvider
+}
+
+func NewAdminHandler(db DBStatsProvider) *AdminHandler {
+	return &AdminHandler{
+		db: db,
+	}
+}
+
+func (ah *AdminHandler) GetDBStats(w http.ResponseWriter, r *http.Request) {
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", response.NewDBStatsResponse(ah.db.Stats())))
+}
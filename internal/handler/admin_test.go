@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDBStats struct {
+	stats sql.DBStats
+}
+
+func (f fakeDBStats) Stats() sql.DBStats {
+	return f.stats
+}
+
+func TestAdminHandler_GetDBStats(t *testing.T) {
+	handler := NewAdminHandler(fakeDBStats{stats: sql.DBStats{
+		MaxOpenConnections: 20,
+		OpenConnections:    7,
+		InUse:              5,
+		Idle:               2,
+		WaitCount:          3,
+		WaitDuration:       1500 * time.Millisecond,
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/db/stats", nil)
+	w := httptest.NewRecorder()
+
+	handler.GetDBStats(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, float64(20), resp.Data["max_open_connections"])
+	assert.Equal(t, float64(7), resp.Data["open_connections"])
+	assert.Equal(t, float64(5), resp.Data["in_use"])
+	assert.Equal(t, float64(2), resp.Data["idle"])
+	assert.Equal(t, float64(3), resp.Data["wait_count"])
+	assert.Equal(t, float64(1500), resp.Data["wait_duration_ms"])
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"oracle-golang/internal/audit"
+	"oracle-golang/internal/model/response"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditQuerier interface {
+	Query(ctx context.Context, f audit.Filter) ([]audit.Record, error)
+}
+
+type AuditHandler struct {
+	audit AuditQuerier
+}
+
+func NewAuditHandler(audit AuditQuerier) *AuditHandler {
+	return &AuditHandler{
+		audit: audit,
+	}
+}
+
+// GetAuditRecords accepts procedure, principal, outcome, from, to (RFC 3339) and limit
+// query parameters and returns the newest matching records first.
+func (ah *AuditHandler) GetAuditRecords(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	result, err := ah.audit.Query(r.Context(), filter)
+	if err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, http.StatusInternalServerError, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
+}
+
+func parseAuditFilter(q url.Values) (audit.Filter, error) {
+	filter := audit.Filter{
+		Procedure: q.Get("procedure"),
+		Principal: q.Get("principal"),
+		Outcome:   audit.Outcome(q.Get("outcome")),
+		Limit:     defaultAuditLimit,
+	}
+
+	switch filter.Outcome {
+	case "", audit.OutcomeSuccess, audit.OutcomeError:
+	default:
+		return filter, fmt.Errorf("outcome must be %s or %s", audit.OutcomeSuccess, audit.OutcomeError)
+	}
+
+	var err error
+	if filter.From, err = parseTime(q.Get("from")); err != nil {
+		return filter, fmt.Errorf("from: %w", err)
+	}
+	if filter.To, err = parseTime(q.Get("to")); err != nil {
+		return filter, fmt.Errorf("to: %w", err)
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxAuditLimit)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, errors.New("must be an RFC 3339 timestamp")
+	}
+	return t, nil
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/audit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuditQuerier struct {
+	filter  audit.Filter
+	records []audit.Record
+}
+
+func (f *fakeAuditQuerier) Query(_ context.Context, filter audit.Filter) ([]audit.Record, error) {
+	f.filter = filter
+	return f.records, nil
+}
+
+func TestAuditHandler_GetAuditRecords(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedFilter audit.Filter
+	}{
+		{
+			name:           "default limit",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedFilter: audit.Filter{Limit: defaultAuditLimit},
+		},
+		{
+			name:           "all filters",
+			query:          "?procedure=pkg.pay&principal=alice&outcome=error&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z&limit=5",
+			expectedStatus: http.StatusOK,
+			expectedFilter: audit.Filter{
+				Procedure: "pkg.pay",
+				Principal: "alice",
+				Outcome:   audit.OutcomeError,
+				From:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:        time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				Limit:     5,
+			},
+		},
+		{
+			name:           "invalid outcome",
+			query:          "?outcome=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid time",
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "limit too large",
+			query:          "?limit=100000",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &fakeAuditQuerier{records: []audit.Record{{Procedure: "pkg.pay", Outcome: audit.OutcomeError}}}
+			handler := NewAuditHandler(querier)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetAuditRecords(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.expectedFilter, querier.filter)
+
+			var resp struct {
+				Data []audit.Record `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Len(t, resp.Data, 1)
+			assert.Equal(t, "pkg.pay", resp.Data[0].Procedure)
+		})
+	}
+}
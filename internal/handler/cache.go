@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"oracle-golang/internal/model/request"
+	"strings"
+	"time"
+)
+
+// cachingService is implemented by services that cache the responses of some procedures.
+type cachingService interface {
+	CacheTTL(ctx context.Context, r request.CallProcedureRequest) (time.Duration, bool)
+}
+
+// writeCacheHeaders sets ETag and Cache-Control for cacheable procedures and reports
+// whether the client's copy is still current, in which case 304 has been written.
+func writeCacheHeaders(w http.ResponseWriter, r *http.Request, ttl time.Duration, data any) bool {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(ttl.Seconds())))
+
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
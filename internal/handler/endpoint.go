@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/logger"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tracing"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type ParamSource string
+
+const (
+	SourcePath   ParamSource = "path"
+	SourceQuery  ParamSource = "query"
+	SourceHeader ParamSource = "header"
+	SourceBody   ParamSource = "body"
+	SourceClaim  ParamSource = "claim"
+	SourceConst  ParamSource = "const"
+)
+
+// EndpointParam sets the procedure argument Name from the request value named Key, or to
+// Value with SourceConst.
+type EndpointParam struct {
+	Name  string
+	From  ParamSource
+	Key   string
+	Value any
+}
+
+// Endpoint maps a custom route to a procedure.
+type Endpoint struct {
+	Procedure  string
+	Datasource string
+	Params     []EndpointParam
+	// Outputs renames OUT arguments, keyed by lower-case argument name.
+	Outputs map[string]string
+	// Cursor names an OUT cursor whose rows are the whole response.
+	Cursor string
+	Status int
+	// JWTSecret verifies the bearer token claims are read from. Tokens are only accepted
+	// without it when TrustGatewayVerified says the API gateway has verified them.
+	JWTSecret            []byte
+	TrustGatewayVerified bool
+}
+
+// EndpointHandler serves one custom route. Unlike the /api routes, its response is the
+// bare OUT values, or the rows of Cursor, without the message envelope; errors keep it.
+type EndpointHandler struct {
+	service  ProcedureService
+	endpoint Endpoint
+}
+
+func NewEndpointHandler(service ProcedureService, endpoint Endpoint) *EndpointHandler {
+	outputs := make(map[string]string, len(endpoint.Outputs))
+	for name, renamed := range endpoint.Outputs {
+		outputs[strings.ToLower(name)] = renamed
+	}
+	endpoint.Outputs = outputs
+	endpoint.Cursor = strings.ToLower(endpoint.Cursor)
+	if endpoint.Status == 0 {
+		endpoint.Status = http.StatusOK
+	}
+	return &EndpointHandler{service: service, endpoint: endpoint}
+}
+
+func (eh *EndpointHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := otel.Tracer(tracerName).Start(r.Context(), "EndpointHandler.ServeHTTP")
+	defer span.End()
+
+	e := eh.endpoint
+	span.SetAttributes(tracing.AttrProcedure.String(e.Procedure))
+	ctx = logger.With(ctx, "procedure", e.Procedure)
+	if e.Datasource != "" {
+		span.SetAttributes(tracing.AttrDatasource.String(e.Datasource))
+		ctx = datasource.WithName(logger.With(ctx, "datasource", e.Datasource), e.Datasource)
+	}
+
+	claims, err := eh.claims(r)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusUnauthorized, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	info, err := eh.service.GetProcedureInfo(ctx, e.Procedure)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	if len(info.Overloads) == 0 {
+		// The mapping names a procedure the database does not have.
+		message := fmt.Sprintf("procedure %s not found", e.Procedure)
+		logMethod(ctx, message)
+		span.SetStatus(codes.Error, message)
+		response.WriteJSON(w, http.StatusNotFound, response.ErrorResponse(message, nil))
+		return
+	}
+
+	args, err := eh.arguments(r, claims)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	req, err := callRequest(info, e.Procedure, e.Datasource, args)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	span.SetAttributes(tracing.AttrParamCount.Int(len(req.Params)))
+
+	result, err := eh.service.CallProcedure(ctx, req)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	body, err := eh.responseBody(result)
+	if err != nil {
+		// The procedure no longer returns what the mapping expects.
+		slog.ErrorContext(ctx, "Endpoint mapping does not match the procedure", "error", err)
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusInternalServerError, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	if cs, ok := eh.service.(cachingService); ok {
+		if ttl, ok := cs.CacheTTL(r.Context(), req); ok && writeCacheHeaders(w, r, ttl, body) {
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	if e.Status == http.StatusNoContent {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.ErrorContext(ctx, "JSON encoding error", "error", err)
+	}
+}
+
+// claims returns the claims of the bearer token when a parameter is read from them. The
+// token and every claim read from it are required, so that a defaulted argument never
+// falls back to the procedure's own identity.
+func (eh *EndpointHandler) claims(r *http.Request) (map[string]any, error) {
+	var claims map[string]any
+	for _, p := range eh.endpoint.Params {
+		if p.From != SourceClaim {
+			continue
+		}
+		if claims == nil {
+			if len(eh.endpoint.JWTSecret) == 0 && !eh.endpoint.TrustGatewayVerified {
+				return nil, errors.New("bearer token cannot be verified: no JWT secret is configured")
+			}
+			var err error
+			claims, err = auth.Claims(r.Header.Get("Authorization"), eh.endpoint.JWTSecret, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			if claims == nil {
+				return nil, errors.New("bearer token is required")
+			}
+		}
+		if _, ok := claims[p.Key]; !ok {
+			return nil, fmt.Errorf("bearer token has no %s claim", p.Key)
+		}
+	}
+	return claims, nil
+}
+
+// arguments collects the argument values of a call, keyed by lower-case name. Values
+// missing from the request are left out, so the procedure's defaults apply.
+func (eh *EndpointHandler) arguments(r *http.Request, claims map[string]any) (map[string]any, error) {
+	var body map[string]any
+	args := make(map[string]any, len(eh.endpoint.Params))
+	for _, p := range eh.endpoint.Params {
+		var value any
+		var ok bool
+		switch p.From {
+		case SourcePath:
+			value = chi.URLParam(r, p.Key)
+			ok = value != ""
+		case SourceQuery:
+			if values := r.URL.Query()[p.Key]; len(values) > 0 {
+				value, ok = values[0], true
+			}
+		case SourceHeader:
+			if values := r.Header.Values(p.Key); len(values) > 0 {
+				value, ok = values[0], true
+			}
+		case SourceBody:
+			if body == nil {
+				body = make(map[string]any)
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+					return nil, errors.New("body must be a JSON object")
+				}
+			}
+			value, ok = body[p.Key]
+		case SourceClaim:
+			value, ok = claims[p.Key]
+		case SourceConst:
+			value, ok = p.Value, true
+		}
+		if ok {
+			args[strings.ToLower(p.Name)] = value
+		}
+	}
+	return args, nil
+}
+
+// responseBody returns the rows of the endpoint's cursor, or the OUT values under their
+// response names.
+func (eh *EndpointHandler) responseBody(result response.CallProcedureResponse) (any, error) {
+	if eh.endpoint.Cursor != "" {
+		rows, ok := result[eh.endpoint.Cursor]
+		if !ok {
+			return nil, fmt.Errorf("procedure %s returned no cursor %s", eh.endpoint.Procedure, eh.endpoint.Cursor)
+		}
+		return rows, nil
+	}
+	body := make(map[string]any, len(result))
+	for name, value := range result {
+		if renamed, ok := eh.endpoint.Outputs[name]; ok {
+			name = renamed
+		}
+		body[name] = value
+	}
+	return body, nil
+}
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// unsignedToken builds a bearer token for deployments where the API gateway verifies
+// signatures.
+func unsignedToken(t *testing.T, claims map[string]any) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload) + ".x"
+}
+
+func TestEndpointHandler(t *testing.T) {
+	getByCustomer := &response.GetProcedureInfoResponse{
+		Target: response.ProcedureTarget{Owner: "CRM", Package: "PKG_ORDERS", Name: "GET_BY_CUSTOMER"},
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+			{Name: "P_CUSTOMER_ID", DataType: "NUMBER", InOut: "IN"},
+			{Name: "P_STATUS", DataType: "VARCHAR2", InOut: "IN"},
+			{Name: "P_CHANNEL", DataType: "VARCHAR2", InOut: "IN"},
+			{Name: "P_USER", DataType: "VARCHAR2", InOut: "IN"},
+			{Name: "P_ORDERS", DataType: "REF CURSOR", InOut: "OUT"},
+			{Name: "P_TOTAL", DataType: "NUMBER", InOut: "OUT"},
+		}}},
+	}
+	// P_USER trails the OUT arguments and has a default, so a call without it would run
+	// with the procedure's own identity.
+	trailingUser := &response.GetProcedureInfoResponse{
+		Target: getByCustomer.Target,
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+			{Name: "P_CUSTOMER_ID", DataType: "NUMBER", InOut: "IN"},
+			{Name: "P_STATUS", DataType: "VARCHAR2", InOut: "IN"},
+			{Name: "P_CHANNEL", DataType: "VARCHAR2", InOut: "IN"},
+			{Name: "P_ORDERS", DataType: "REF CURSOR", InOut: "OUT"},
+			{Name: "P_USER", DataType: "VARCHAR2", InOut: "IN", Defaulted: true},
+		}}},
+	}
+	endpoint := Endpoint{
+		Procedure:  "crm.pkg_orders.get_by_customer",
+		Datasource: "crm",
+		Params: []EndpointParam{
+			{Name: "p_customer_id", From: SourcePath, Key: "id"},
+			{Name: "P_STATUS", From: SourceHeader, Key: "X-Status"},
+			{Name: "p_channel", From: SourceConst, Value: "WEB"},
+			{Name: "p_user", From: SourceClaim, Key: "sub"},
+		},
+		TrustGatewayVerified: true,
+	}
+	call := request.CallProcedureRequest{
+		Name:       "crm.pkg_orders.get_by_customer",
+		Datasource: "crm",
+		Params: []request.ProcedureParam{
+			{Name: "p_customer_id", Type: "NUMBER", Value: "42", Direction: "IN"},
+			{Name: "p_status", Type: "VARCHAR2", Value: "OPEN", Direction: "IN"},
+			{Name: "p_channel", Type: "VARCHAR2", Value: "WEB", Direction: "IN"},
+			{Name: "p_user", Type: "VARCHAR2", Value: "alice", Direction: "IN"},
+			{Name: "p_orders", Type: "REF CURSOR", Direction: "OUT"},
+			{Name: "p_total", Type: "NUMBER", Direction: "OUT"},
+		},
+	}
+	result := response.CallProcedureResponse{
+		"p_orders": []any{map[string]any{"ID": float64(1)}},
+		"p_total":  float64(1),
+	}
+
+	tests := []struct {
+		name         string
+		configure    func(e *Endpoint)
+		setup        func(r *http.Request)
+		setupMock    func(*MockProcedureService)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "OUT values under their response names",
+			configure: func(e *Endpoint) {
+				e.Outputs = map[string]string{"P_TOTAL": "total"}
+			},
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(getByCustomer, nil)
+				m.On("CallProcedure", mock.Anything, call).Return(result, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"p_orders":[{"ID":1}],"total":1}`,
+		},
+		{
+			name: "cursor as the whole response with a custom status",
+			configure: func(e *Endpoint) {
+				e.Cursor = "P_ORDERS"
+				e.Status = http.StatusAccepted
+			},
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(getByCustomer, nil)
+				m.On("CallProcedure", mock.Anything, call).Return(result, nil)
+			},
+			expectedCode: http.StatusAccepted,
+			expectedBody: `[{"ID":1}]`,
+		},
+		{
+			name:      "cursor the procedure does not return",
+			configure: func(e *Endpoint) { e.Cursor = "P_ITEMS" },
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(getByCustomer, nil)
+				m.On("CallProcedure", mock.Anything, call).Return(result, nil)
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"status":false,"message":"procedure crm.pkg_orders.get_by_customer returned no cursor p_items","data":{}}`,
+		},
+		{
+			name: "procedure without overloads",
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(&response.GetProcedureInfoResponse{}, nil)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:  "missing request value",
+			setup: func(r *http.Request) { r.Header.Del("X-Status") },
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(getByCustomer, nil)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid bearer token",
+			setup:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer not-a-token") },
+			setupMock:    func(m *MockProcedureService) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:      "no bearer token",
+			configure: func(e *Endpoint) { e.Cursor = "P_ORDERS" },
+			setup:     func(r *http.Request) { r.Header.Del("Authorization") },
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(trailingUser, nil).Maybe()
+				m.On("CallProcedure", mock.Anything, mock.Anything).Return(result, nil).Maybe()
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"status":false,"message":"bearer token is required","data":{}}`,
+		},
+		{
+			name:      "token without the claim",
+			configure: func(e *Endpoint) { e.Cursor = "P_ORDERS" },
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+unsignedToken(t, map[string]any{"scope": "orders"}))
+			},
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(trailingUser, nil).Maybe()
+				m.On("CallProcedure", mock.Anything, mock.Anything).Return(result, nil).Maybe()
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"status":false,"message":"bearer token has no sub claim","data":{}}`,
+		},
+		{
+			name:         "unverified bearer token",
+			configure:    func(e *Endpoint) { e.TrustGatewayVerified = false },
+			setupMock:    func(m *MockProcedureService) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "unknown data source",
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "crm.pkg_orders.get_by_customer").Return(nil, &datasource.UnknownError{Name: "crm"})
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockProcedureService{}
+			tt.setupMock(mockService)
+			e := endpoint
+			if tt.configure != nil {
+				tt.configure(&e)
+			}
+			r := chi.NewRouter()
+			r.Method(http.MethodGet, "/customers/{id}/orders", NewEndpointHandler(mockService, e))
+
+			req := httptest.NewRequest(http.MethodGet, "/customers/42/orders", nil)
+			req.Header.Set("X-Status", "OPEN")
+			req.Header.Set("Authorization", "Bearer "+unsignedToken(t, map[string]any{"sub": "alice"}))
+			if tt.setup != nil {
+				tt.setup(req)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEndpointHandler_Body(t *testing.T) {
+	mockService := &MockProcedureService{}
+	mockService.On("GetProcedureInfo", mock.Anything, "app.pay").Return(&response.GetProcedureInfoResponse{
+		Target: response.ProcedureTarget{Owner: "APP", Name: "PAY"},
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+			{Name: "P_AMOUNT", DataType: "NUMBER", InOut: "IN"},
+			{Name: "P_NOTE", DataType: "VARCHAR2", InOut: "IN", Defaulted: true},
+		}}},
+	}, nil)
+	mockService.On("CallProcedure", mock.Anything, request.CallProcedureRequest{
+		Name:   "app.pay",
+		Params: []request.ProcedureParam{{Name: "p_amount", Type: "NUMBER", Value: 9.5, Direction: "IN"}},
+	}).Return(response.CallProcedureResponse{}, nil)
+
+	h := NewEndpointHandler(mockService, Endpoint{
+		Procedure: "app.pay",
+		Params: []EndpointParam{
+			{Name: "p_amount", From: SourceBody, Key: "amount"},
+			{Name: "p_note", From: SourceBody, Key: "note"},
+		},
+		Status: http.StatusNoContent,
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(`{"amount": 9.5}`)))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+	mockService.AssertExpectations(t)
+}
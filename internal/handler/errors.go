@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statusCoder is implemented by service errors that map to a status other than 500,
+// for example rate limit rejections.
+type statusCoder interface {
+	StatusCode() int
+}
+
+type retryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+// serviceErrorStatus returns the status for err and sets Retry-After when err carries one.
+func serviceErrorStatus(w http.ResponseWriter, err error) int {
+	var ra retryAfterer
+	if errors.As(err, &ra) && ra.RetryAfter() > 0 {
+		seconds := int(math.Ceil(ra.RetryAfter().Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+
+	var sc statusCoder
+	if errors.As(err, &sc) {
+		return sc.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
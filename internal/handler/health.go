@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"oracle-golang/internal/health"
+	"oracle-golang/internal/model/response"
+)
+
+type ReadinessChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
+type HealthHandler struct {
+	checker ReadinessChecker
+}
+
+func NewHealthHandler(checker ReadinessChecker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Live only reports that the process is serving requests; it never touches the database.
+func (hh *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Alive", map[string]string{"status": health.StatusUp}))
+}
+
+func (hh *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := hh.checker.Check(r.Context())
+	if report.Status != health.StatusUp {
+		response.WriteJSON(w, http.StatusServiceUnavailable, response.ErrorResponse("Not ready", report))
+		return
+	}
+
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Ready", report))
+}
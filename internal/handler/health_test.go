@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/health"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReadinessChecker struct {
+	report health.Report
+}
+
+func (f fakeReadinessChecker) Check(context.Context) health.Report {
+	return f.report
+}
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name               string
+		report             health.Report
+		ready              bool
+		expectedStatusCode int
+		expectedMessage    string
+	}{
+		{
+			name:               "liveness ignores the database",
+			report:             health.Report{Status: health.StatusDown},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "Alive",
+		},
+		{
+			name:               "ready",
+			report:             health.Report{Status: health.StatusUp, Checks: []health.Result{{Name: "ping", Status: health.StatusUp}}},
+			ready:              true,
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "Ready",
+		},
+		{
+			name:               "not ready",
+			report:             health.Report{Status: health.StatusDown, Checks: []health.Result{{Name: "ping", Status: health.StatusDown, Error: "ORA-12541"}}},
+			ready:              true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedMessage:    "Not ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(fakeReadinessChecker{report: tt.report})
+
+			w := httptest.NewRecorder()
+			if tt.ready {
+				handler.Ready(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+			} else {
+				handler.Live(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+			}
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedMessage, resp["message"])
+			if tt.ready {
+				data := resp["data"].(map[string]any)
+				assert.Equal(t, tt.report.Status, data["status"])
+				assert.Len(t, data["checks"], len(tt.report.Checks))
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"oracle-golang/internal/job"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type JobService interface {
+	Submit(ctx context.Context, r request.CreateJobRequest) (*job.Job, error)
+	Get(ctx context.Context, id string) (*job.Job, error)
+	Cancel(ctx context.Context, id string) (*job.Job, error)
+}
+
+type JobHandler struct {
+	service JobService
+}
+
+func NewJobHandler(service JobService) *JobHandler {
+	return &JobHandler{
+		service: service,
+	}
+}
+
+func (jh *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	var req request.CreateJobRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse("Invalid JSON format", nil))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	result, err := jh.service.Submit(r.Context(), req)
+	if err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, jobErrorStatus(err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	response.WriteJSON(w, http.StatusAccepted, response.SuccessResponse("Accepted", result))
+}
+
+func (jh *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	result, err := jh.service.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, jobErrorStatus(err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
+}
+
+func (jh *JobHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	result, err := jh.service.Cancel(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, jobErrorStatus(err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
+}
+
+func jobErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, job.ErrNoCallbacks):
+		return http.StatusBadRequest
+	case errors.Is(err, job.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, job.ErrFinished):
+		return http.StatusConflict
+	case errors.Is(err, job.ErrQueueFull), errors.Is(err, job.ErrStopped):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/job"
+	"oracle-golang/internal/model/request"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockJobService is a mock implementation of the JobService interface
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) Submit(ctx context.Context, r request.CreateJobRequest) (*job.Job, error) {
+	args := m.Called(ctx, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *MockJobService) Get(ctx context.Context, id string) (*job.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func (m *MockJobService) Cancel(ctx context.Context, id string) (*job.Job, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*job.Job), args.Error(1)
+}
+
+func newJobRouter(h *JobHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/jobs", h.CreateJob)
+	r.Get("/jobs/{id}", h.GetJob)
+	r.Delete("/jobs/{id}", h.CancelJob)
+	return r
+}
+
+func TestJobHandler(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		requestBody        string
+		setupMock          func(*MockJobService)
+		expectedStatusCode int
+		expectedMessage    string
+	}{
+		{
+			name:        "create job",
+			method:      http.MethodPost,
+			target:      "/jobs",
+			requestBody: `{"name": "month_end", "params": []}`,
+			setupMock: func(m *MockJobService) {
+				m.On("Submit", mock.Anything, mock.MatchedBy(func(req request.CreateJobRequest) bool {
+					return req.Name == "month_end"
+				})).Return(&job.Job{ID: "abc", Status: job.StatusQueued}, nil)
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedMessage:    "Accepted",
+		},
+		{
+			name:        "create job with callback",
+			method:      http.MethodPost,
+			target:      "/jobs",
+			requestBody: `{"name": "month_end", "callback_url": "https://hooks.example.com/done", "callback_headers": {"X-Job": "{{.ID}}"}}`,
+			setupMock: func(m *MockJobService) {
+				m.On("Submit", mock.Anything, mock.MatchedBy(func(req request.CreateJobRequest) bool {
+					return req.Name == "month_end" &&
+						req.CallbackURL == "https://hooks.example.com/done" &&
+						req.CallbackHeaders["X-Job"] == "{{.ID}}"
+				})).Return(&job.Job{ID: "abc", Status: job.StatusQueued}, nil)
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedMessage:    "Accepted",
+		},
+		{
+			name:               "create job with invalid callback url",
+			method:             http.MethodPost,
+			target:             "/jobs",
+			requestBody:        `{"name": "month_end", "callback_url": "ftp://hooks.example.com"}`,
+			setupMock:          func(m *MockJobService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    "callback_url must be an absolute http or https URL",
+		},
+		{
+			name:        "create job with callback when callbacks are disabled",
+			method:      http.MethodPost,
+			target:      "/jobs",
+			requestBody: `{"name": "month_end", "callback_url": "https://hooks.example.com/done"}`,
+			setupMock: func(m *MockJobService) {
+				m.On("Submit", mock.Anything, mock.Anything).Return(nil, job.ErrNoCallbacks)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    job.ErrNoCallbacks.Error(),
+		},
+		{
+			name:               "create job with invalid request",
+			method:             http.MethodPost,
+			target:             "/jobs",
+			requestBody:        `{"params": []}`,
+			setupMock:          func(m *MockJobService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    "procedure name is required",
+		},
+		{
+			name:        "create job with full queue",
+			method:      http.MethodPost,
+			target:      "/jobs",
+			requestBody: `{"name": "month_end"}`,
+			setupMock: func(m *MockJobService) {
+				m.On("Submit", mock.Anything, mock.Anything).Return(nil, job.ErrQueueFull)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedMessage:    job.ErrQueueFull.Error(),
+		},
+		{
+			name:   "get job",
+			method: http.MethodGet,
+			target: "/jobs/abc",
+			setupMock: func(m *MockJobService) {
+				m.On("Get", mock.Anything, "abc").Return(&job.Job{ID: "abc", Status: job.StatusRunning}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "Success",
+		},
+		{
+			name:   "get missing job",
+			method: http.MethodGet,
+			target: "/jobs/missing",
+			setupMock: func(m *MockJobService) {
+				m.On("Get", mock.Anything, "missing").Return(nil, job.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedMessage:    job.ErrNotFound.Error(),
+		},
+		{
+			name:   "cancel finished job",
+			method: http.MethodDelete,
+			target: "/jobs/abc",
+			setupMock: func(m *MockJobService) {
+				m.On("Cancel", mock.Anything, "abc").Return(nil, job.ErrFinished)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedMessage:    job.ErrFinished.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockJobService{}
+			tt.setupMock(mockService)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			newJobRouter(NewJobHandler(mockService)).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			var resp map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedMessage, resp["message"])
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/openapi"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// OpenAPIGenerator builds the OpenAPI document of the data source selected in the context.
+type OpenAPIGenerator interface {
+	Document(ctx context.Context, basePath string) (*openapi.Document, error)
+}
+
+type OpenAPIHandler struct {
+	generator OpenAPIGenerator
+}
+
+func NewOpenAPIHandler(generator OpenAPIGenerator) *OpenAPIHandler {
+	return &OpenAPIHandler{
+		generator: generator,
+	}
+}
+
+// Spec serves the OpenAPI document. Operations are relative to the directory it is served
+// from, so /api/v1/{ds}/openapi.json documents the procedures of data source ds.
+func (oh *OpenAPIHandler) Spec(w http.ResponseWriter, r *http.Request) {
+	ctx, span := otel.Tracer(tracerName).Start(r.Context(), "OpenAPIHandler.Spec")
+	defer span.End()
+
+	if ds := chi.URLParam(r, "ds"); ds != "" {
+		ctx = datasource.WithName(ctx, ds)
+	}
+	doc, err := oh.generator.Document(ctx, strings.TrimSuffix(r.URL.Path, "/openapi.json"))
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		slog.ErrorContext(ctx, "JSON encoding error", "error", err)
+	}
+}
+
+// swaggerUI renders openapi.json from the same directory. Swagger UI itself is loaded
+// from a CDN.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Stored procedures</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// UI serves a Swagger UI page for the document next to it.
+func (oh *OpenAPIHandler) UI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(swaggerUI))
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/openapi"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGenerator struct {
+	datasource string
+	basePath   string
+}
+
+func (f *fakeGenerator) Document(ctx context.Context, basePath string) (*openapi.Document, error) {
+	f.datasource, f.basePath = datasource.NameFrom(ctx), basePath
+	if f.datasource == "crm" {
+		return nil, &datasource.UnknownError{Name: "crm"}
+	}
+	return &openapi.Document{OpenAPI: "3.0.3", Servers: []openapi.Server{{URL: basePath}}}, nil
+}
+
+func TestOpenAPIHandler_Spec(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		wantStatus     int
+		wantDatasource string
+		wantBasePath   string
+	}{
+		{name: "default data source", path: "/api/v1/openapi.json", wantStatus: http.StatusOK, wantBasePath: "/api/v1"},
+		{name: "named data source", path: "/api/v1/billing/openapi.json", wantStatus: http.StatusOK, wantDatasource: "billing", wantBasePath: "/api/v1/billing"},
+		{name: "unknown data source", path: "/api/v1/crm/openapi.json", wantStatus: http.StatusNotFound, wantDatasource: "crm", wantBasePath: "/api/v1/crm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := &fakeGenerator{}
+			h := NewOpenAPIHandler(generator)
+			r := chi.NewRouter()
+			r.Get("/api/v1/openapi.json", h.Spec)
+			r.Get("/api/v1/{ds}/openapi.json", h.Spec)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantDatasource, generator.datasource)
+			assert.Equal(t, tt.wantBasePath, generator.basePath)
+			if tt.wantStatus == http.StatusOK {
+				var doc openapi.Document
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+				assert.Equal(t, "3.0.3", doc.OpenAPI)
+			}
+		})
+	}
+}
+
+func TestOpenAPIHandler_UI(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewOpenAPIHandler(&fakeGenerator{}).UI(w, httptest.NewRequest(http.MethodGet, "/api/v1/docs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `url: "openapi.json"`)
+}
@@ -3,16 +3,28 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/logger"
 	"oracle-golang/internal/model/request"
 	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tracing"
 	"oracle-golang/pkg/util"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 )
 
+const tracerName = "oracle-golang/internal/handler"
+
 type ProcedureService interface {
 	CallProcedure(ctx context.Context, r request.CallProcedureRequest) (response.CallProcedureResponse, error)
-	GetProcedureInfo(ctx context.Context, procedureName string) (response.GetProcedureInfoResponse, error)
+	GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error)
+	ListProcedures(ctx context.Context, r request.ListProceduresRequest) (response.ListProceduresResponse, error)
 }
 
 type ProcedureHandler struct {
@@ -26,58 +38,180 @@ func NewProcedureHandler(service ProcedureService) *ProcedureHandler {
 }
 
 func (ph *ProcedureHandler) CallProcedure(w http.ResponseWriter, r *http.Request) {
+	ctx, span := otel.Tracer(tracerName).Start(r.Context(), "ProcedureHandler.CallProcedure")
+	defer span.End()
+
 	var req request.CallProcedureRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logMethod(err.Error())
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, "invalid JSON format")
 		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse("Invalid JSON format", nil))
 		return
 	}
 
+	span.SetAttributes(tracing.AttrProcedure.String(req.Name), tracing.AttrParamCount.Int(len(req.Params)))
+	ctx = logger.With(ctx, "procedure", req.Name)
+
+	ds, err := datasourceName(r, req.Datasource)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	if ds != "" {
+		req.Datasource = ds
+		span.SetAttributes(tracing.AttrDatasource.String(ds))
+		ctx = logger.With(ctx, "datasource", ds)
+	}
+
 	if err := req.Validate(); err != nil {
-		logMethod(err.Error())
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
 		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
 		return
 	}
 
-	result, err := ph.service.CallProcedure(r.Context(), req)
+	result, err := ph.service.CallProcedure(ctx, req)
 	if err != nil {
-		logMethod(err.Error())
-		response.WriteJSON(w, http.StatusInternalServerError, response.ErrorResponse(err.Error(), nil))
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
 		return
 	}
 
+	if cs, ok := ph.service.(cachingService); ok {
+		if ttl, ok := cs.CacheTTL(r.Context(), req); ok && writeCacheHeaders(w, r, ttl, result) {
+			return
+		}
+	}
+
 	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
 	return
 }
 
 func (ph *ProcedureHandler) GetProcedureInfo(w http.ResponseWriter, r *http.Request) {
+	ctx, span := otel.Tracer(tracerName).Start(r.Context(), "ProcedureHandler.GetProcedureInfo")
+	defer span.End()
+
 	var req struct {
 		ProcedureName string `json:"procedure_name"`
+		Datasource    string `json:"datasource"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logMethod(err.Error())
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, "invalid JSON format")
 		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse("Invalid JSON format", nil))
 		return
 	}
 
 	if req.ProcedureName == "" {
-		logMethod("procedure_name is required")
+		logMethod(ctx, "procedure_name is required")
+		span.SetStatus(codes.Error, "procedure_name is required")
 		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse("procedure_name is required", nil))
 		return
 	}
 
-	result, err := ph.service.GetProcedureInfo(r.Context(), req.ProcedureName)
+	span.SetAttributes(tracing.AttrProcedure.String(req.ProcedureName))
+	ctx = logger.With(ctx, "procedure", req.ProcedureName)
+
+	ds, err := datasourceName(r, req.Datasource)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	if ds != "" {
+		span.SetAttributes(tracing.AttrDatasource.String(ds))
+		ctx = datasource.WithName(logger.With(ctx, "datasource", ds), ds)
+	}
+
+	result, err := ph.service.GetProcedureInfo(ctx, req.ProcedureName)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
+}
+
+// ListProcedures pages through the procedure catalog, which holds the procedures named in
+// the data source's policy. Filters and paging come from the query string: owner,
+// package, name, type, limit and offset.
+func (ph *ProcedureHandler) ListProcedures(w http.ResponseWriter, r *http.Request) {
+	ctx, span := otel.Tracer(tracerName).Start(r.Context(), "ProcedureHandler.ListProcedures")
+	defer span.End()
+
+	query := r.URL.Query()
+	req := request.ListProceduresRequest{
+		Owner:   query.Get("owner"),
+		Package: query.Get("package"),
+		Name:    query.Get("name"),
+		Type:    query.Get("type"),
+		Limit:   request.DefaultListLimit,
+	}
+
+	var err error
+	if req.Limit, err = intParam(query.Get("limit"), req.Limit); err == nil {
+		req.Offset, err = intParam(query.Get("offset"), 0)
+	}
+	if err == nil {
+		req.Datasource, err = datasourceName(r, query.Get("datasource"))
+	}
+	if err == nil {
+		err = req.Validate()
+	}
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	if req.Datasource != "" {
+		span.SetAttributes(tracing.AttrDatasource.String(req.Datasource))
+		ctx = logger.With(ctx, "datasource", req.Datasource)
+	}
+
+	result, err := ph.service.ListProcedures(ctx, req)
 	if err != nil {
-		logMethod(err.Error())
-		response.WriteJSON(w, http.StatusInternalServerError, response.ErrorResponse(err.Error(), nil))
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
 		return
 	}
 
 	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
 }
 
-func logMethod(message string) {
-	log.Printf("[%s] %s", util.CurrentMethod(2), message)
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an integer", value)
+	}
+	return n, nil
+}
+
+// datasourceName returns the data source named by the {ds} path segment or, failing that,
+// by the request body. The two must agree when both are set.
+func datasourceName(r *http.Request, body string) (string, error) {
+	path := chi.URLParam(r, "ds")
+	if path == "" {
+		return body, nil
+	}
+	if body != "" && body != path {
+		return "", fmt.Errorf("datasource %q in the body does not match %q in the path", body, path)
+	}
+	return path, nil
+}
+
+func logMethod(ctx context.Context, message string) {
+	slog.ErrorContext(ctx, message, "method", util.CurrentMethod(2))
 }
@@ -8,13 +8,24 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"oracle-golang/internal/datasource"
 	"oracle-golang/internal/model/request"
 	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/ratelimit"
+	"oracle-golang/internal/tracing"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MockProcedureService is a mock implementation of the ProcedureService interface
@@ -30,12 +41,17 @@ func (m *MockProcedureService) CallProcedure(ctx context.Context, r request.Call
 	return args.Get(0).(response.CallProcedureResponse), args.Error(1)
 }
 
-func (m *MockProcedureService) GetProcedureInfo(ctx context.Context, procedureName string) (response.GetProcedureInfoResponse, error) {
+func (m *MockProcedureService) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
 	args := m.Called(ctx, procedureName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(response.GetProcedureInfoResponse), args.Error(1)
+	return args.Get(0).(*response.GetProcedureInfoResponse), args.Error(1)
+}
+
+func (m *MockProcedureService) ListProcedures(ctx context.Context, r request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	args := m.Called(ctx, r)
+	return args.Get(0).(response.ListProceduresResponse), args.Error(1)
 }
 
 func TestNewProcedureHandler(t *testing.T) {
@@ -263,19 +279,15 @@ func TestProcedureHandler_GetProcedureInfo(t *testing.T) {
 				"procedure_name": "test_procedure"
 			}`,
 			setupMock: func(mockService *MockProcedureService) {
-				expectedResponse := response.GetProcedureInfoResponse{
-					{
-						"argument_name": "param1",
-						"data_type":     "VARCHAR2",
-						"in_out":        "IN",
-						"position":      1,
-					},
-					{
-						"argument_name": "param2",
-						"data_type":     "NUMBER",
-						"in_out":        "OUT",
-						"position":      2,
-					},
+				expectedResponse := &response.GetProcedureInfoResponse{
+					Name: "test_procedure",
+					Overloads: []response.ProcedureOverload{{
+						Return: &response.ProcedureArgument{DataType: "NUMBER", InOut: "OUT"},
+						Arguments: []response.ProcedureArgument{
+							{Name: "PARAM1", Position: 1, DataType: "VARCHAR2", InOut: "IN"},
+							{Name: "PARAM2", Position: 2, DataType: "NUMBER", InOut: "OUT"},
+						},
+					}},
 				}
 				mockService.On("GetProcedureInfo",
 					mock.Anything,
@@ -289,11 +301,15 @@ func TestProcedureHandler_GetProcedureInfo(t *testing.T) {
 				}
 				assert.NotNil(t, resp["data"])
 
-				data := resp["data"].([]any)
-				assert.Len(t, data, 2)
-
-				firstParam := data[0].(map[string]any)
-				assert.Equal(t, "param1", firstParam["argument_name"])
+				data := resp["data"].(map[string]any)
+				assert.Equal(t, "test_procedure", data["name"])
+				overload := data["overloads"].([]any)[0].(map[string]any)
+				assert.Equal(t, "NUMBER", overload["return"].(map[string]any)["data_type"])
+
+				arguments := overload["arguments"].([]any)
+				assert.Len(t, arguments, 2)
+				firstParam := arguments[0].(map[string]any)
+				assert.Equal(t, "PARAM1", firstParam["name"])
 				assert.Equal(t, "VARCHAR2", firstParam["data_type"])
 			},
 		},
@@ -305,7 +321,10 @@ func TestProcedureHandler_GetProcedureInfo(t *testing.T) {
 			setupMock: func(mockService *MockProcedureService) {
 				mockService.On("GetProcedureInfo",
 					mock.Anything,
-					"nonexistent_procedure").Return(response.GetProcedureInfoResponse{}, nil)
+					"nonexistent_procedure").Return(&response.GetProcedureInfoResponse{
+					Name:      "nonexistent_procedure",
+					Overloads: []response.ProcedureOverload{},
+				}, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			validateResponse: func(t *testing.T, resp map[string]any) {
@@ -313,8 +332,8 @@ func TestProcedureHandler_GetProcedureInfo(t *testing.T) {
 				if success, exists := resp["success"]; exists {
 					assert.True(t, success.(bool))
 				}
-				data := resp["data"].([]any)
-				assert.Len(t, data, 0)
+				data := resp["data"].(map[string]any)
+				assert.Empty(t, data["overloads"])
 			},
 		},
 		{
@@ -519,7 +538,7 @@ func BenchmarkProcedureHandler_CallProcedure(b *testing.B) {
 
 func BenchmarkProcedureHandler_GetProcedureInfo(b *testing.B) {
 	mockService := &MockProcedureService{}
-	expectedResponse := response.GetProcedureInfoResponse{{"info": "test"}}
+	expectedResponse := &response.GetProcedureInfoResponse{Name: "test_procedure"}
 
 	mockService.On("GetProcedureInfo",
 		mock.Anything,
@@ -537,3 +556,205 @@ func BenchmarkProcedureHandler_GetProcedureInfo(b *testing.B) {
 		handler.GetProcedureInfo(w, req)
 	}
 }
+
+func TestProcedureHandler_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+
+	mockService := &MockProcedureService{}
+	mockService.On("CallProcedure", mock.Anything, mock.Anything).Return(nil, errors.New("ORA-01403: no data found"))
+
+	handler := NewProcedureHandler(mockService)
+
+	parentCtx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	req := httptest.NewRequest(http.MethodPost, "/procedure/call",
+		strings.NewReader(`{"name": "pkg.proc", "params": [{"name": "p_id", "type": "NUMBER", "value": 1, "direction": "IN"}]}`)).
+		WithContext(parentCtx)
+	w := httptest.NewRecorder()
+
+	handler.CallProcedure(w, req)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+
+	assert.Equal(t, "ProcedureHandler.CallProcedure", span.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Contains(t, span.Attributes(), tracing.AttrProcedure.String("pkg.proc"))
+	assert.Contains(t, span.Attributes(), tracing.AttrParamCount.Int(1))
+	assert.Equal(t, codes.Error, span.Status().Code)
+
+	// The service receives the handler span as its parent
+	serviceCtx := mockService.Calls[0].Arguments.Get(0).(context.Context)
+	assert.Equal(t, span.SpanContext().SpanID(), trace.SpanContextFromContext(serviceCtx).SpanID())
+}
+
+func TestProcedureHandler_CallProcedure_RateLimited(t *testing.T) {
+	mockService := &MockProcedureService{}
+	mockService.On("CallProcedure", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("call failed: %w", &ratelimit.LimitError{Reason: "too many calls to pkg.pay", Wait: 1500 * time.Millisecond}))
+
+	handler := NewProcedureHandler(mockService)
+	req := httptest.NewRequest(http.MethodPost, "/procedure/call", strings.NewReader(`{"name": "pkg.pay", "params": []}`))
+	w := httptest.NewRecorder()
+
+	handler.CallProcedure(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	mockService.AssertExpectations(t)
+}
+
+// cachingProcedureService adds CacheTTL to the mock, like service.ProcedureService with a cache.
+type cachingProcedureService struct {
+	*MockProcedureService
+	ttl time.Duration
+}
+
+func (s cachingProcedureService) CacheTTL(context.Context, request.CallProcedureRequest) (time.Duration, bool) {
+	return s.ttl, true
+}
+
+func TestProcedureHandler_CallProcedure_CacheHeaders(t *testing.T) {
+	mockService := &MockProcedureService{}
+	mockService.On("CallProcedure", mock.Anything, mock.Anything).
+		Return(response.CallProcedureResponse{"p_rate": 470.5}, nil)
+	handler := NewProcedureHandler(cachingProcedureService{MockProcedureService: mockService, ttl: 5 * time.Minute})
+
+	call := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/procedure/call", strings.NewReader(`{"name": "pkg.get_rates", "params": []}`))
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler.CallProcedure(w, req)
+		return w
+	}
+
+	first := call("")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "private, max-age=300", first.Header().Get("Cache-Control"))
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	notModified := call(`"other", ` + etag)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+	assert.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	stale := call(`"other"`)
+	assert.Equal(t, http.StatusOK, stale.Code)
+}
+
+func TestProcedureHandler_Datasource(t *testing.T) {
+	mockService := &MockProcedureService{}
+	mockService.On("CallProcedure", mock.Anything, mock.MatchedBy(func(r request.CallProcedureRequest) bool {
+		return r.Datasource == "billing"
+	})).Return(response.CallProcedureResponse{}, nil)
+	mockService.On("GetProcedureInfo", mock.MatchedBy(func(ctx context.Context) bool {
+		return datasource.NameFrom(ctx) == "billing"
+	}), "pkg.proc").Return(&response.GetProcedureInfoResponse{}, nil)
+
+	handler := NewProcedureHandler(mockService)
+	router := chi.NewRouter()
+	routes := func(r chi.Router) {
+		r.Post("/call", handler.CallProcedure)
+		r.Get("/info", handler.GetProcedureInfo)
+	}
+	router.Route("/procedures", routes)
+	router.Route("/{ds}/procedures", routes)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "call by path", method: http.MethodPost, path: "/billing/procedures/call", body: `{"name": "pkg.proc"}`, status: http.StatusOK},
+		{name: "call by body", method: http.MethodPost, path: "/procedures/call", body: `{"name": "pkg.proc", "datasource": "billing"}`, status: http.StatusOK},
+		{name: "path and body agree", method: http.MethodPost, path: "/billing/procedures/call", body: `{"name": "pkg.proc", "datasource": "billing"}`, status: http.StatusOK},
+		{name: "path and body disagree", method: http.MethodPost, path: "/billing/procedures/call", body: `{"name": "pkg.proc", "datasource": "core"}`, status: http.StatusBadRequest},
+		{name: "info by path", method: http.MethodGet, path: "/billing/procedures/info", body: `{"procedure_name": "pkg.proc"}`, status: http.StatusOK},
+		{name: "info by body", method: http.MethodGet, path: "/procedures/info", body: `{"procedure_name": "pkg.proc", "datasource": "billing"}`, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestProcedureHandler_UnknownDatasource(t *testing.T) {
+	mockService := &MockProcedureService{}
+	mockService.On("CallProcedure", mock.Anything, mock.Anything).Return(nil, &datasource.UnknownError{Name: "dwh"})
+	handler := NewProcedureHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/procedures/call", strings.NewReader(`{"name": "pkg.proc", "datasource": "dwh"}`))
+	w := httptest.NewRecorder()
+	handler.CallProcedure(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown datasource: dwh")
+}
+
+func TestProcedureHandler_ListProcedures(t *testing.T) {
+	page := response.ListProceduresResponse{
+		Items: []response.ProcedureSummary{{Owner: "APP", Package: "PKG", Name: "GET_ORDERS", FullName: "PKG.GET_ORDERS", ObjectType: "PACKAGE", Status: "VALID"}},
+		Limit: 10,
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		want   request.ListProceduresRequest
+		status int
+	}{
+		{
+			name:   "defaults",
+			path:   "/procedures",
+			want:   request.ListProceduresRequest{Limit: request.DefaultListLimit},
+			status: http.StatusOK,
+		},
+		{
+			name:   "filters",
+			path:   "/billing/procedures?owner=app&package=pkg&name=get_*&type=package&limit=10&offset=20",
+			want:   request.ListProceduresRequest{Owner: "app", Package: "pkg", Name: "get_*", Type: "package", Limit: 10, Offset: 20, Datasource: "billing"},
+			status: http.StatusOK,
+		},
+		{name: "bad limit", path: "/procedures?limit=ten", status: http.StatusBadRequest},
+		{name: "limit too large", path: "/procedures?limit=1000", status: http.StatusBadRequest},
+		{name: "bad type", path: "/procedures?type=trigger", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockProcedureService{}
+			mockService.On("ListProcedures", mock.Anything, tt.want).Return(page, nil)
+
+			router := chi.NewRouter()
+			router.Get("/procedures", NewProcedureHandler(mockService).ListProcedures)
+			router.Get("/{ds}/procedures", NewProcedureHandler(mockService).ListProcedures)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"full_name":"PKG.GET_ORDERS"`)
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ListProcedures", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/logger"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"oracle-golang/internal/tracing"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// RestHandler calls procedures through resource-style routes,
+// /p/{schema}/{package}/{proc} or /p/{schema}/{proc}. Arguments are a flat object keyed
+// by lower-case argument name, and their types and directions come from the procedure's
+// metadata. A tenant names its own schema in the path, and the procedure is called by
+// the name relative to it, as tenants call procedures everywhere else.
+type RestHandler struct {
+	service ProcedureService
+	// procedures holds, per data source, the procedures named in its policy and whether
+	// each is read-only. Keys are upper case.
+	procedures map[string]map[string]bool
+	fallback   string
+}
+
+// NewRestHandler serves the procedures of every data source. Calls go through the name a
+// data source's policy uses for the procedure, so its limits, caching and replica routing
+// apply; only read-only procedures can be called with GET. Requests that name no data
+// source use the fallback one.
+func NewRestHandler(service ProcedureService, procedures map[string]map[string]bool, fallback string) *RestHandler {
+	return &RestHandler{service: service, procedures: procedures, fallback: fallback}
+}
+
+// Call calls the procedure named by the path with the IN arguments in the JSON body or,
+// for GET, in the query string. The response data holds the OUT arguments.
+func (rh *RestHandler) Call(w http.ResponseWriter, r *http.Request) {
+	ctx, span := otel.Tracer(tracerName).Start(r.Context(), "RestHandler.Call")
+	defer span.End()
+
+	target := response.ProcedureTarget{
+		Owner:   strings.ToUpper(chi.URLParam(r, "schema")),
+		Package: strings.ToUpper(chi.URLParam(r, "package")),
+		Name:    strings.ToUpper(chi.URLParam(r, "proc")),
+	}
+	ds := chi.URLParam(r, "ds")
+	if ds != "" {
+		span.SetAttributes(tracing.AttrDatasource.String(ds))
+		ctx = datasource.WithName(logger.With(ctx, "datasource", ds), ds)
+	}
+
+	t, relative := tenant.From(ctx)
+	if relative && target.Owner != t.Schema {
+		message := fmt.Sprintf("tenant %s can only call procedures in schema %s", t.Name, t.Schema)
+		logMethod(ctx, message)
+		span.SetStatus(codes.Error, message)
+		response.WriteJSON(w, http.StatusForbidden, response.ErrorResponse(message, nil))
+		return
+	}
+
+	name, readOnly := rh.policyName(ds, target, relative)
+	info, err := rh.service.GetProcedureInfo(ctx, name)
+	if err == nil && !relative && name != target.String() && !sameTarget(info.Target, target) {
+		// The policy's name for the procedure resolves to another object in this schema.
+		name, readOnly = target.String(), false
+		info, err = rh.service.GetProcedureInfo(ctx, name)
+	}
+	span.SetAttributes(tracing.AttrProcedure.String(name))
+	ctx = logger.With(ctx, "procedure", name)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	if len(info.Overloads) == 0 {
+		message := fmt.Sprintf("procedure %s not found", target)
+		logMethod(ctx, message)
+		span.SetStatus(codes.Error, message)
+		response.WriteJSON(w, http.StatusNotFound, response.ErrorResponse(message, nil))
+		return
+	}
+	if r.Method == http.MethodGet && !readOnly {
+		message := fmt.Sprintf("%s is not read-only and must be called with POST", target)
+		logMethod(ctx, message)
+		span.SetStatus(codes.Error, message)
+		w.Header().Set("Allow", http.MethodPost)
+		response.WriteJSON(w, http.StatusMethodNotAllowed, response.ErrorResponse(message, nil))
+		return
+	}
+
+	req, err := restRequest(r, info, name, ds)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+	span.SetAttributes(tracing.AttrParamCount.Int(len(req.Params)))
+
+	result, err := rh.service.CallProcedure(ctx, req)
+	if err != nil {
+		logMethod(ctx, err.Error())
+		span.SetStatus(codes.Error, err.Error())
+		response.WriteJSON(w, serviceErrorStatus(w, err), response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	if cs, ok := rh.service.(cachingService); ok {
+		if ttl, ok := cs.CacheTTL(r.Context(), req); ok && writeCacheHeaders(w, r, ttl, result) {
+			return
+		}
+	}
+
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
+}
+
+// policyName returns the name the data source's policy uses for target, trying the
+// owner-qualified name before the one relative to the schema, and whether it is
+// read-only. Procedures outside the policy are called by their owner-qualified name.
+// With relative set, as for tenants, only the name relative to the schema is used.
+func (rh *RestHandler) policyName(ds string, target response.ProcedureTarget, relative bool) (string, bool) {
+	if ds == "" {
+		ds = rh.fallback
+	}
+	procedures := rh.procedures[ds]
+
+	local := target.Name
+	if target.Package != "" {
+		local = target.Package + "." + target.Name
+	}
+	candidates := []string{target.String(), local}
+	if relative {
+		candidates = candidates[1:]
+	}
+	for _, name := range candidates {
+		if readOnly, ok := procedures[name]; ok {
+			return name, readOnly
+		}
+	}
+	return candidates[0], false
+}
+
+func sameTarget(a, b response.ProcedureTarget) bool {
+	return strings.EqualFold(a.String(), b.String())
+}
+
+// restRequest builds the call of procedure name from the arguments of r.
+func restRequest(r *http.Request, info *response.GetProcedureInfoResponse, name, ds string) (request.CallProcedureRequest, error) {
+	args, err := restArguments(r)
+	if err != nil {
+		return request.CallProcedureRequest{Name: name, Datasource: ds}, err
+	}
+	return callRequest(info, name, ds, args)
+}
+
+// callRequest builds the call of procedure name with args, keyed by lower-case argument
+// name, binding them to the first overload that takes them.
+func callRequest(info *response.GetProcedureInfoResponse, name, ds string, args map[string]any) (request.CallProcedureRequest, error) {
+	req := request.CallProcedureRequest{Name: name, Datasource: ds}
+	overload, err := selectOverload(info, args)
+	if err != nil {
+		return req, err
+	}
+	if req.Params, err = restParams(overload, args); err != nil {
+		return req, err
+	}
+	return req, req.Validate()
+}
+
+// restArguments reads the arguments of a call, keyed by lower-case name: the JSON object
+// in the body, or the query string for GET. An empty body passes no arguments.
+func restArguments(r *http.Request) (map[string]any, error) {
+	args := make(map[string]any)
+	if r.Method == http.MethodGet {
+		for name, values := range r.URL.Query() {
+			args[strings.ToLower(name)] = values[0]
+		}
+		return args, nil
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.New("body must be a JSON object of argument values")
+	}
+	for name, value := range body {
+		args[strings.ToLower(name)] = value
+	}
+	return args, nil
+}
+
+// selectOverload returns the first overload that takes every argument in args and is
+// given all of its arguments without a default.
+func selectOverload(info *response.GetProcedureInfoResponse, args map[string]any) (response.ProcedureOverload, error) {
+	var mismatch error
+	for _, overload := range info.Overloads {
+		if overload.Return != nil {
+			mismatch = fmt.Errorf("%s is a function; only procedures can be called", info.Target)
+			continue
+		}
+		if mismatch = matchArguments(overload, args); mismatch == nil {
+			return overload, nil
+		}
+	}
+	if len(info.Overloads) > 1 {
+		return response.ProcedureOverload{}, fmt.Errorf("the arguments match none of the %d overloads of %s", len(info.Overloads), info.Target)
+	}
+	return response.ProcedureOverload{}, mismatch
+}
+
+func matchArguments(overload response.ProcedureOverload, args map[string]any) error {
+	inputs := make(map[string]bool, len(overload.Arguments))
+	for _, arg := range overload.Arguments {
+		name := strings.ToLower(arg.Name)
+		if arg.InOut == "OUT" {
+			continue
+		}
+		inputs[name] = true
+		if _, ok := args[name]; !ok && !arg.Defaulted {
+			return fmt.Errorf("argument %q is required", name)
+		}
+	}
+	for name := range args {
+		if !inputs[name] {
+			return fmt.Errorf("unknown argument %q", name)
+		}
+	}
+	return nil
+}
+
+// restParams binds the arguments of overload in declaration order. Calls bind arguments
+// by position, so an argument with a default can only be left out when every argument
+// after it is too.
+func restParams(overload response.ProcedureOverload, args map[string]any) ([]request.ProcedureParam, error) {
+	bound := len(overload.Arguments)
+	for bound > 0 {
+		arg := overload.Arguments[bound-1]
+		if _, ok := args[strings.ToLower(arg.Name)]; ok || arg.InOut != "IN" {
+			break
+		}
+		bound--
+	}
+
+	params := make([]request.ProcedureParam, 0, bound)
+	for _, arg := range overload.Arguments[:bound] {
+		name := strings.ToLower(arg.Name)
+		value, ok := args[name]
+		if !ok && arg.InOut != "OUT" {
+			return nil, fmt.Errorf("argument %q must be passed: it comes before other arguments, which are bound by position", name)
+		}
+		paramType, err := restParamType(arg)
+		if err != nil {
+			return nil, err
+		}
+		param := request.ProcedureParam{Name: name, Type: paramType, Direction: arg.InOut}
+		if arg.InOut == "IN/OUT" {
+			param.Direction = "INOUT"
+		}
+		if arg.InOut != "OUT" {
+			param.Value = value
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// restParamType maps the Oracle type of an argument to a type calls can bind.
+func restParamType(arg response.ProcedureArgument) (string, error) {
+	switch arg.DataType {
+	case "NUMBER", "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE", "BINARY_INTEGER", "PL/SQL PLS INTEGER", "PLS_INTEGER", "PL/SQL BINARY INTEGER":
+		return "NUMBER", nil
+	case "VARCHAR2", "VARCHAR", "CHAR", "NVARCHAR2", "NCHAR", "CLOB", "NCLOB", "RAW", "BLOB", "DATE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		return arg.DataType, nil
+	case "PL/SQL BOOLEAN", "BOOLEAN":
+		return "BOOLEAN", nil
+	case "REF CURSOR":
+		return "REF CURSOR", nil
+	}
+	return "", fmt.Errorf("argument %q has type %s, which cannot be bound", strings.ToLower(arg.Name), typeDescription(arg))
+}
+
+func typeDescription(arg response.ProcedureArgument) string {
+	if arg.TypeName != "" {
+		return strings.TrimPrefix(arg.TypeOwner+"."+arg.TypeName, ".")
+	}
+	return arg.DataType
+}
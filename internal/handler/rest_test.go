@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func restRouter(h *RestHandler) chi.Router {
+	r := chi.NewRouter()
+	for _, prefix := range []string{"/p", "/{ds}/p"} {
+		for _, path := range []string{"/{schema}/{proc}", "/{schema}/{package}/{proc}"} {
+			r.Get(prefix+path, h.Call)
+			r.Post(prefix+path, h.Call)
+		}
+	}
+	return r
+}
+
+func TestRestHandler_Call(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	getOrders := &response.GetProcedureInfoResponse{
+		Target: response.ProcedureTarget{Owner: "APP", Package: "ORDERS_PKG", Name: "GET_ORDERS"},
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+			{Name: "P_CUSTOMER_ID", DataType: "NUMBER", InOut: "IN", DataPrecision: intPtr(10), DataScale: intPtr(0)},
+			{Name: "P_STATUS", DataType: "VARCHAR2", InOut: "IN", Defaulted: true},
+			{Name: "P_ORDERS", DataType: "REF CURSOR", InOut: "OUT"},
+			{Name: "P_LIMIT", DataType: "PL/SQL PLS INTEGER", InOut: "IN", Defaulted: true},
+		}}},
+	}
+	pay := &response.GetProcedureInfoResponse{
+		Target: response.ProcedureTarget{Owner: "APP", Name: "PAY"},
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+			{Name: "P_AMOUNT", DataType: "NUMBER", InOut: "IN"},
+			{Name: "P_BALANCE", DataType: "NUMBER", InOut: "IN/OUT"},
+			{Name: "P_PAID", DataType: "PL/SQL BOOLEAN", InOut: "OUT"},
+		}}},
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		setupMock    func(*MockProcedureService)
+		expectedCode int
+		expectedData map[string]any
+		expectedErr  string
+	}{
+		{
+			name:   "POST binds the body by argument name",
+			method: http.MethodPost,
+			path:   "/p/app/pay",
+			body:   `{"P_Amount": 12.5, "p_balance": 100}`,
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "PAY").Return(pay, nil)
+				m.On("CallProcedure", mock.Anything, request.CallProcedureRequest{
+					Name: "PAY",
+					Params: []request.ProcedureParam{
+						{Name: "p_amount", Type: "NUMBER", Value: 12.5, Direction: "IN"},
+						{Name: "p_balance", Type: "NUMBER", Value: float64(100), Direction: "INOUT"},
+						{Name: "p_paid", Type: "BOOLEAN", Direction: "OUT"},
+					},
+				}).Return(response.CallProcedureResponse{"p_balance": 87.5, "p_paid": true}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedData: map[string]any{"p_balance": 87.5, "p_paid": true},
+		},
+		{
+			name: "GET binds the query string of a read-only procedure",
+			// p_limit has a default and comes last, so it is left out of the call.
+			method: http.MethodGet,
+			path:   "/billing/p/app/orders_pkg/get_orders?p_customer_id=7&p_status=OPEN",
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "ORDERS_PKG.GET_ORDERS").Return(getOrders, nil)
+				m.On("CallProcedure", mock.Anything, request.CallProcedureRequest{
+					Name:       "ORDERS_PKG.GET_ORDERS",
+					Datasource: "billing",
+					Params: []request.ProcedureParam{
+						{Name: "p_customer_id", Type: "NUMBER", Value: "7", Direction: "IN"},
+						{Name: "p_status", Type: "VARCHAR2", Value: "OPEN", Direction: "IN"},
+						{Name: "p_orders", Type: "REF CURSOR", Direction: "OUT"},
+					},
+				}).Return(response.CallProcedureResponse{"p_orders": []any{}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedData: map[string]any{"p_orders": []any{}},
+		},
+		{
+			name:   "GET is refused for procedures that are not read-only",
+			method: http.MethodGet,
+			path:   "/p/app/pay?p_amount=1&p_balance=2",
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "PAY").Return(pay, nil)
+			},
+			expectedCode: http.StatusMethodNotAllowed,
+			expectedErr:  "APP.PAY is not read-only and must be called with POST",
+		},
+		{
+			name:   "unknown argument",
+			method: http.MethodPost,
+			path:   "/p/app/pay",
+			body:   `{"p_amount": 1, "p_balance": 2, "p_note": "x"}`,
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "PAY").Return(pay, nil)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  `unknown argument "p_note"`,
+		},
+		{
+			name:   "missing argument",
+			method: http.MethodPost,
+			path:   "/p/app/pay",
+			body:   `{"p_amount": 1}`,
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "PAY").Return(pay, nil)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  `argument "p_balance" is required`,
+		},
+		{
+			name:   "defaulted argument before a bound one",
+			method: http.MethodPost,
+			path:   "/p/app/orders_pkg/get_orders",
+			body:   `{"p_customer_id": 7, "p_limit": 10}`,
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "APP.ORDERS_PKG.GET_ORDERS").Return(getOrders, nil)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  `argument "p_status" must be passed: it comes before other arguments, which are bound by position`,
+		},
+		{
+			name:   "body that is not an object",
+			method: http.MethodPost,
+			path:   "/p/app/pay",
+			body:   `[1, 2]`,
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "PAY").Return(pay, nil)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "body must be a JSON object of argument values",
+		},
+		{
+			name:   "unknown procedure",
+			method: http.MethodPost,
+			path:   "/p/app/missing",
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "APP.MISSING").Return(&response.GetProcedureInfoResponse{Overloads: []response.ProcedureOverload{}}, nil)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "procedure APP.MISSING not found",
+		},
+		{
+			name:   "unknown data source",
+			method: http.MethodPost,
+			path:   "/crm/p/app/pay",
+			setupMock: func(m *MockProcedureService) {
+				m.On("GetProcedureInfo", mock.Anything, "APP.PAY").Return(nil, &datasource.UnknownError{Name: "crm"})
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockProcedureService{}
+			tt.setupMock(mockService)
+			h := NewRestHandler(mockService, map[string]map[string]bool{
+				"core":    {"PAY": false},
+				"billing": {"ORDERS_PKG.GET_ORDERS": true},
+			}, "core")
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			restRouter(h).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			var resp map[string]any
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			if tt.expectedData != nil {
+				assert.Equal(t, tt.expectedData, resp["data"])
+			}
+			if tt.expectedErr != "" {
+				assert.Equal(t, tt.expectedErr, resp["message"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestHandler_PolicyNameResolvingElsewhere(t *testing.T) {
+	mockService := &MockProcedureService{}
+	// PAY in the policy is a synonym for another schema's procedure, so the path's
+	// procedure is called by its qualified name, outside the policy.
+	mockService.On("GetProcedureInfo", mock.Anything, "PAY").Return(&response.GetProcedureInfoResponse{
+		Target:    response.ProcedureTarget{Owner: "BILLING", Name: "PAY"},
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{}}},
+	}, nil)
+	mockService.On("GetProcedureInfo", mock.Anything, "APP.PAY").Return(&response.GetProcedureInfoResponse{
+		Target:    response.ProcedureTarget{Owner: "APP", Name: "PAY"},
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{}}},
+	}, nil)
+	mockService.On("CallProcedure", mock.Anything, request.CallProcedureRequest{Name: "APP.PAY", Params: []request.ProcedureParam{}}).
+		Return(response.CallProcedureResponse{}, nil)
+
+	h := NewRestHandler(mockService, map[string]map[string]bool{"core": {"PAY": true}}, "core")
+	w := httptest.NewRecorder()
+	restRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/p/app/pay", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRestHandler_Tenant(t *testing.T) {
+	mockService := &MockProcedureService{}
+	// Tenants call their procedures by the name relative to their schema.
+	mockService.On("GetProcedureInfo", mock.Anything, "PAY").Return(&response.GetProcedureInfoResponse{
+		Target:    response.ProcedureTarget{Owner: "ACME", Name: "PAY"},
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{}}},
+	}, nil)
+	mockService.On("CallProcedure", mock.Anything, request.CallProcedureRequest{Name: "PAY", Params: []request.ProcedureParam{}}).
+		Return(response.CallProcedureResponse{}, nil)
+
+	h := NewRestHandler(mockService, map[string]map[string]bool{"core": {"ACME.PAY": true}}, "core")
+	router := restRouter(h)
+	acme := tenant.With(t.Context(), tenant.Tenant{Name: "acme", Schema: "ACME"})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/p/acme/pay", nil).WithContext(acme))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/p/globex_prod/pay", nil).WithContext(acme))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "tenant acme can only call procedures in schema ACME")
+	mockService.AssertExpectations(t)
+}
+
+func TestRestParamType(t *testing.T) {
+	tests := []struct {
+		arg      response.ProcedureArgument
+		expected string
+		err      string
+	}{
+		{arg: response.ProcedureArgument{DataType: "BINARY_INTEGER"}, expected: "NUMBER"},
+		{arg: response.ProcedureArgument{DataType: "TIMESTAMP"}, expected: "TIMESTAMP"},
+		{arg: response.ProcedureArgument{DataType: "PL/SQL BOOLEAN"}, expected: "BOOLEAN"},
+		{arg: response.ProcedureArgument{DataType: "REF CURSOR"}, expected: "REF CURSOR"},
+		{
+			arg: response.ProcedureArgument{Name: "P_LINES", DataType: "TABLE", TypeOwner: "APP", TypeName: "LINE_TAB"},
+			err: `argument "p_lines" has type APP.LINE_TAB, which cannot be bound`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg.DataType, func(t *testing.T) {
+			got, err := restParamType(tt.arg)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/scheduler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ScheduleService interface {
+	List(ctx context.Context) ([]scheduler.Schedule, error)
+	Get(ctx context.Context, name string) (*scheduler.Schedule, error)
+	Trigger(ctx context.Context, name string) (*scheduler.Schedule, error)
+	Pause(ctx context.Context, name string) (*scheduler.Schedule, error)
+	Resume(ctx context.Context, name string) (*scheduler.Schedule, error)
+}
+
+type ScheduleHandler struct {
+	service ScheduleService
+}
+
+func NewScheduleHandler(service ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		service: service,
+	}
+}
+
+func (sh *ScheduleHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	result, err := sh.service.List(r.Context())
+	if err != nil {
+		logMethod(r.Context(), err.Error())
+		response.WriteJSON(w, http.StatusInternalServerError, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	response.WriteJSON(w, http.StatusOK, response.SuccessResponse("Success", result))
+}
+
+func (sh *ScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	sh.handle(w, r, http.StatusOK, sh.service.Get)
+}
+
+func (sh *ScheduleHandler) TriggerSchedule(w http.ResponseWriter, r *http.Request) {
+	sh.handle(w, r, http.StatusAccepted, sh.service.Trigger)
+}
+
+func (sh *ScheduleHandler) PauseSchedule(w http.ResponseWriter, r *http.Request) {
+	sh.handle(w, r, http.StatusOK, sh.service.Pause)
+}
+
+func (sh *ScheduleHandler) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	sh.handle(w, r, http.StatusOK, sh.service.Resume)
+}
+
+func (sh *ScheduleHandler) handle(w http.ResponseWriter, r *http.Request, status int,
+	action func(ctx context.Context, name string) (*scheduler.Schedule, error)) {
+	result, err := action(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		logMethod(r.Context(), err.Error())
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, scheduler.ErrNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, scheduler.ErrLocalPause):
+			code = http.StatusConflict
+		}
+		response.WriteJSON(w, code, response.ErrorResponse(err.Error(), nil))
+		return
+	}
+
+	response.WriteJSON(w, status, response.SuccessResponse("Success", result))
+}
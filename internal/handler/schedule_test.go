@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/scheduler"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockScheduleService is a mock implementation of the ScheduleService interface
+type MockScheduleService struct {
+	mock.Mock
+}
+
+func (m *MockScheduleService) List(ctx context.Context) ([]scheduler.Schedule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]scheduler.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) Get(ctx context.Context, name string) (*scheduler.Schedule, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*scheduler.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) Trigger(ctx context.Context, name string) (*scheduler.Schedule, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*scheduler.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) Pause(ctx context.Context, name string) (*scheduler.Schedule, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*scheduler.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) Resume(ctx context.Context, name string) (*scheduler.Schedule, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*scheduler.Schedule), args.Error(1)
+}
+
+func TestScheduleHandler(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		setupMock          func(*MockScheduleService)
+		expectedStatusCode int
+		expectedMessage    string
+	}{
+		{
+			name:   "list schedules",
+			method: http.MethodGet,
+			target: "/schedules",
+			setupMock: func(m *MockScheduleService) {
+				m.On("List", mock.Anything).Return([]scheduler.Schedule{{Name: "nightly"}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "Success",
+		},
+		{
+			name:   "trigger schedule",
+			method: http.MethodPost,
+			target: "/schedules/nightly/run",
+			setupMock: func(m *MockScheduleService) {
+				m.On("Trigger", mock.Anything, "nightly").Return(&scheduler.Schedule{Name: "nightly"}, nil)
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedMessage:    "Success",
+		},
+		{
+			name:   "pause schedule",
+			method: http.MethodPost,
+			target: "/schedules/nightly/pause",
+			setupMock: func(m *MockScheduleService) {
+				m.On("Pause", mock.Anything, "nightly").Return(&scheduler.Schedule{Name: "nightly", Paused: true}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedMessage:    "Success",
+		},
+		{
+			name:   "resume missing schedule",
+			method: http.MethodPost,
+			target: "/schedules/missing/resume",
+			setupMock: func(m *MockScheduleService) {
+				m.On("Resume", mock.Anything, "missing").Return(nil, scheduler.ErrNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedMessage:    scheduler.ErrNotFound.Error(),
+		},
+		{
+			name:   "pause without a shared state",
+			method: http.MethodPost,
+			target: "/schedules/nightly/pause",
+			setupMock: func(m *MockScheduleService) {
+				m.On("Pause", mock.Anything, "nightly").Return(nil, scheduler.ErrLocalPause)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedMessage:    scheduler.ErrLocalPause.Error(),
+		},
+		{
+			name:   "get schedule fails",
+			method: http.MethodGet,
+			target: "/schedules/nightly",
+			setupMock: func(m *MockScheduleService) {
+				m.On("Get", mock.Anything, "nightly").Return(nil, errors.New("boom"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedMessage:    "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockScheduleService{}
+			tt.setupMock(mockService)
+
+			h := NewScheduleHandler(mockService)
+			r := chi.NewRouter()
+			r.Get("/schedules", h.ListSchedules)
+			r.Get("/schedules/{name}", h.GetSchedule)
+			r.Post("/schedules/{name}/run", h.TriggerSchedule)
+			r.Post("/schedules/{name}/pause", h.PauseSchedule)
+			r.Post("/schedules/{name}/resume", h.ResumeSchedule)
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+
+			var resp map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectedMessage, resp["message"])
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
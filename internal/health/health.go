@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Checker runs readiness checks concurrently, each bounded by the same timeout.
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+}
+
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+func (c *Checker) Check(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, r := range results {
+		if r.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := Result{
+		Name:      check.Name,
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func PingCheck(db *sql.DB) Check {
+	return Check{
+		Name: "ping",
+		Run:  db.PingContext,
+	}
+}
+
+func QueryCheck(db *sql.DB) Check {
+	return Check{
+		Name: "select_dual",
+		Run: func(ctx context.Context) error {
+			var one int
+			if err := db.QueryRowContext(ctx, "SELECT 1 FROM DUAL").Scan(&one); err != nil {
+				return err
+			}
+			if one != 1 {
+				return fmt.Errorf("unexpected result: %d", one)
+			}
+			return nil
+		},
+	}
+}
+
+// ProcedureCheck calls a parameterless procedure; the check fails when the procedure raises.
+func ProcedureCheck(db *sql.DB, name string) Check {
+	return Check{
+		Name: "procedure:" + name,
+		Run: func(ctx context.Context) error {
+			_, err := db.ExecContext(ctx, fmt.Sprintf("BEGIN %s; END;", name))
+			return err
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Check(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		procedures     []string
+		expectedStatus string
+		expectedChecks map[string]string
+	}{
+		{
+			name: "database is healthy",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+				mock.ExpectQuery(`SELECT 1 FROM DUAL`).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+				mock.ExpectExec(`BEGIN app.health_pkg.check_all; END;`).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			procedures:     []string{"app.health_pkg.check_all"},
+			expectedStatus: StatusUp,
+			expectedChecks: map[string]string{
+				"ping":                               StatusUp,
+				"select_dual":                        StatusUp,
+				"procedure:app.health_pkg.check_all": StatusUp,
+			},
+		},
+		{
+			name: "database is down",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing().WillReturnError(errors.New("ORA-03113: end-of-file on communication channel"))
+				mock.ExpectQuery(`SELECT 1 FROM DUAL`).WillReturnError(errors.New("ORA-03114: not connected to ORACLE"))
+			},
+			expectedStatus: StatusDown,
+			expectedChecks: map[string]string{
+				"ping":        StatusDown,
+				"select_dual": StatusDown,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+			require.NoError(t, err)
+			defer db.Close()
+			mock.MatchExpectationsInOrder(false)
+
+			tt.setupMock(mock)
+
+			checks := []Check{PingCheck(db), QueryCheck(db)}
+			for _, name := range tt.procedures {
+				checks = append(checks, ProcedureCheck(db, name))
+			}
+
+			report := NewChecker(time.Second, checks...).Check(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, report.Status)
+			require.Len(t, report.Checks, len(tt.expectedChecks))
+			for _, r := range report.Checks {
+				assert.Equal(t, tt.expectedChecks[r.Name], r.Status, r.Name)
+				if r.Status == StatusDown {
+					assert.NotEmpty(t, r.Error)
+				}
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	slow := Check{
+		Name: "slow",
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	report := NewChecker(10*time.Millisecond, slow).Check(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
@@ -0,0 +1,149 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+var (
+	// inProgressTTL bounds how long a key stays locked if the instance running the first
+	// request dies before completing it.
+	inProgressTTL = 5 * time.Minute
+	pollInterval  = 50 * time.Millisecond
+)
+
+// Middleware makes requests carrying an Idempotency-Key header safe to retry. The first
+// request runs and its response is stored for ttl; repeats with the same body get the stored
+// response, repeats with a different body get 409, and repeats that arrive while the first
+// request is running wait for it. Keys are scoped to the principal and tenant. Responses that mean the
+// request was not executed (429, 503) are not stored, so the client can retry with the same key;
+// neither is 304, whose empty body only makes sense to the client that sent If-None-Match.
+func Middleware(store Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse(Header+" is too long", nil))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse("Failed to read request body", nil))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			storeKey := auth.PrincipalFrom(ctx) + "\x00" + tenant.Name(ctx) + "\x00" + key
+			hash := requestHash(r, body)
+
+			for {
+				record, reserved, err := store.Reserve(ctx, storeKey, hash, inProgressTTL)
+				if err != nil {
+					slog.ErrorContext(ctx, "failed to reserve idempotency key", "error", err)
+					response.WriteJSON(w, http.StatusInternalServerError, response.ErrorResponse("Idempotency store unavailable", nil))
+					return
+				}
+
+				switch {
+				case reserved:
+					execute(w, r, next, store, storeKey, ttl)
+					return
+				case record.Hash != hash:
+					response.WriteJSON(w, http.StatusConflict, response.ErrorResponse(Header+" was already used with a different request", nil))
+					return
+				case record.Response != nil:
+					replay(w, record.Response)
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					response.WriteJSON(w, http.StatusConflict, response.ErrorResponse("A request with this "+Header+" is still in progress", nil))
+					return
+				case <-time.After(pollInterval):
+				}
+			}
+		})
+	}
+}
+
+func execute(w http.ResponseWriter, r *http.Request, next http.Handler, store Store, key string, ttl time.Duration) {
+	ctx := context.WithoutCancel(r.Context())
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			slog.ErrorContext(ctx, "failed to release idempotency key", "error", err)
+		}
+	}()
+
+	var buf bytes.Buffer
+	ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	ww.Tee(&buf)
+	next.ServeHTTP(ww, r)
+
+	status := ww.Status()
+	if status == 0 {
+		status = http.StatusOK
+	}
+	switch status {
+	case http.StatusNotModified, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return
+	}
+
+	resp := Response{Status: status, Header: w.Header().Clone(), Body: buf.Bytes()}
+	if err := store.Complete(ctx, key, resp, ttl); err != nil {
+		slog.ErrorContext(ctx, "failed to store idempotent response", "error", err)
+		return
+	}
+	completed = true
+}
+
+func replay(w http.ResponseWriter, resp *Response) {
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	// REST routes take their arguments from the query string, so it is part of the
+	// request, in a canonical order.
+	h.Write([]byte(r.URL.Query().Encode()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,228 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/auth"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingHandler struct {
+	calls  atomic.Int32
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := h.calls.Add(1)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+}
+
+func send(handler http.Handler, principal, key, body string) *httptest.ResponseRecorder {
+	return sendTo(handler, "/api/v1/procedures/call", principal, key, body)
+}
+
+func sendTo(handler http.Handler, target, principal, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	req = req.WithContext(auth.WithPrincipal(req.Context(), principal))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		second           func(http.Handler) *httptest.ResponseRecorder
+		expectedStatus   int
+		expectedBody     string
+		expectedReplayed string
+		expectedCalls    int32
+	}{
+		{
+			name:   "repeat returns the stored response",
+			status: http.StatusOK,
+			second: func(h http.Handler) *httptest.ResponseRecorder {
+				return send(h, "alice", "key-1", `{"name":"pkg.pay"}`)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `{"call":1}`,
+			expectedReplayed: "true",
+			expectedCalls:    1,
+		},
+		{
+			name:   "stored errors are replayed too",
+			status: http.StatusInternalServerError,
+			second: func(h http.Handler) *httptest.ResponseRecorder {
+				return send(h, "alice", "key-1", `{"name":"pkg.pay"}`)
+			},
+			expectedStatus:   http.StatusInternalServerError,
+			expectedBody:     `{"call":1}`,
+			expectedReplayed: "true",
+			expectedCalls:    1,
+		},
+		{
+			name:   "different payload is a conflict",
+			status: http.StatusOK,
+			second: func(h http.Handler) *httptest.ResponseRecorder {
+				return send(h, "alice", "key-1", `{"name":"pkg.refund"}`)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedCalls:  1,
+		},
+		{
+			name:   "different query string is a conflict",
+			status: http.StatusOK,
+			second: func(h http.Handler) *httptest.ResponseRecorder {
+				return sendTo(h, "/api/v1/procedures/call?p_amount=1", "alice", "key-1", `{"name":"pkg.pay"}`)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedCalls:  1,
+		},
+		{
+			name:   "keys are scoped to the principal",
+			status: http.StatusOK,
+			second: func(h http.Handler) *httptest.ResponseRecorder {
+				return send(h, "bob", "key-1", `{"name":"pkg.pay"}`)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"call":2}`,
+			expectedCalls:  2,
+		},
+		{
+			name:   "rejected requests can be retried with the same key",
+			status: http.StatusTooManyRequests,
+			second: func(h http.Handler) *httptest.ResponseRecorder {
+				return send(h, "alice", "key-1", `{"name":"pkg.pay"}`)
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   `{"call":2}`,
+			expectedCalls:  2,
+		},
+		{
+			name:   "requests without a key are not deduplicated",
+			status: http.StatusOK,
+			second: func(h http.Handler) *httptest.ResponseRecorder {
+				return send(h, "alice", "", `{"name":"pkg.pay"}`)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"call":2}`,
+			expectedCalls:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &countingHandler{status: tt.status}
+			handler := Middleware(NewMemoryStore(), time.Hour)(next)
+
+			first := send(handler, "alice", "key-1", `{"name":"pkg.pay"}`)
+			require.Equal(t, tt.status, first.Code)
+
+			w := tt.second(handler)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+			assert.Equal(t, tt.expectedReplayed, w.Header().Get(ReplayedHeader))
+			assert.Equal(t, tt.expectedCalls, next.calls.Load())
+		})
+	}
+}
+
+func TestMiddleware_ConcurrentDuplicatesWait(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	handler := Middleware(NewMemoryStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		close(started)
+		<-release
+		_, _ = w.Write([]byte(`{"paid":true}`))
+	}))
+
+	results := make([]*httptest.ResponseRecorder, 3)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0] = send(handler, "alice", "key-1", `{"name":"pkg.pay"}`)
+	}()
+	<-started
+
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = send(handler, "alice", "key-1", `{"name":"pkg.pay"}`)
+		}(i)
+	}
+
+	time.Sleep(3 * pollInterval)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, w := range results {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"paid":true}`, w.Body.String())
+	}
+	assert.Equal(t, "true", results[1].Header().Get(ReplayedHeader))
+}
+
+func TestMiddleware_KeyTooLong(t *testing.T) {
+	next := &countingHandler{status: http.StatusOK}
+	handler := Middleware(NewMemoryStore(), time.Hour)(next)
+
+	w := send(handler, "alice", strings.Repeat("k", maxKeyLength+1), `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, int32(0), next.calls.Load())
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, reserved, err := store.Reserve(t.Context(), "key", "hash", time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, store.Complete(t.Context(), "key", Response{Status: http.StatusOK}, time.Hour))
+
+	now = now.Add(59 * time.Minute)
+	record, reserved, err := store.Reserve(t.Context(), "key", "other", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "hash", record.Hash)
+
+	now = now.Add(time.Minute)
+	_, reserved, err = store.Reserve(t.Context(), "key", "other", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func TestRequestHash_QueryOrder(t *testing.T) {
+	a := httptest.NewRequest(http.MethodPost, "/p/app/pay?p_amount=1&p_balance=2", nil)
+	b := httptest.NewRequest(http.MethodPost, "/p/app/pay?p_balance=2&p_amount=1", nil)
+	c := httptest.NewRequest(http.MethodPost, "/p/app/pay?p_amount=2&p_balance=2", nil)
+
+	assert.Equal(t, requestHash(a, nil), requestHash(b, nil))
+	assert.NotEqual(t, requestHash(a, nil), requestHash(c, nil))
+}
@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is the stored outcome of the first request with a key.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// Record is the state of a key. Response is nil while the first request is still running.
+type Record struct {
+	Hash      string    `json:"hash"`
+	Response  *Response `json:"response,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Store keeps idempotency records. Implementations shared by several instances must make
+// Reserve atomic, for example with an INSERT that fails on a duplicate key.
+type Store interface {
+	// Reserve creates an in-progress record for key unless an unexpired one exists, in
+	// which case the existing record is returned with reserved set to false.
+	Reserve(ctx context.Context, key, hash string, ttl time.Duration) (record Record, reserved bool, err error)
+	Complete(ctx context.Context, key string, resp Response, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+const sweepInterval = time.Minute
+
+type MemoryStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	records   map[string]Record
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{now: time.Now, records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key, hash string, ttl time.Duration) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if r, ok := s.records[key]; ok && now.Before(r.ExpiresAt) {
+		return r, false, nil
+	}
+
+	r := Record{Hash: hash, ExpiresAt: now.Add(ttl)}
+	s.records[key] = r
+	return r, true, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, resp Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.records[key]
+	r.Response = &resp
+	r.ExpiresAt = s.now().Add(ttl)
+	s.records[key] = r
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, r := range s.records {
+		if !now.Before(r.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package job
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	jobsBucket = []byte("jobs")
+	// finishedBucket indexes finished jobs by the time they finished, then ID, so that the
+	// oldest come first.
+	finishedBucket = []byte("finished")
+)
+
+// BoltStore keeps jobs in a bbolt file so that their state survives restarts.
+type BoltStore struct {
+	db   *bolt.DB
+	opts StoreOptions
+	now  func() time.Time
+}
+
+func NewBoltStore(path string, opts StoreOptions) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open job store: %w", err)
+	}
+
+	s := &BoltStore{db: db, opts: opts, now: time.Now}
+	err = db.Update(func(tx *bolt.Tx) error {
+		jobs, err := tx.CreateBucketIfNotExists(jobsBucket)
+		if err != nil {
+			return err
+		}
+		if tx.Bucket(finishedBucket) == nil {
+			if err := s.index(tx, jobs); err != nil {
+				return err
+			}
+		}
+		return s.evict(tx)
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init job store: %w", err)
+	}
+
+	return s, nil
+}
+
+// index builds the finished job index of a file written before it existed.
+func (s *BoltStore) index(tx *bolt.Tx, jobs *bolt.Bucket) error {
+	finished, err := tx.CreateBucket(finishedBucket)
+	if err != nil {
+		return err
+	}
+	return jobs.ForEach(func(_, data []byte) error {
+		j, err := decode(data)
+		if err != nil || !j.Finished() {
+			return err
+		}
+		return finished.Put(s.finishedKey(j), []byte(j.ID))
+	})
+}
+
+func (s *BoltStore) Save(_ context.Context, j *Job) error {
+	data, err := encode(j)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		jobs := tx.Bucket(jobsBucket)
+		if j.Finished() {
+			previous, err := s.finishedBefore(jobs, j.ID)
+			if err != nil {
+				return err
+			}
+			if !previous {
+				if err := tx.Bucket(finishedBucket).Put(s.finishedKey(j), []byte(j.ID)); err != nil {
+					return err
+				}
+			}
+		}
+		if err := jobs.Put([]byte(j.ID), data); err != nil {
+			return err
+		}
+		return s.evict(tx)
+	})
+}
+
+// finishedBefore reports whether the stored job id had already finished, and so is indexed.
+func (s *BoltStore) finishedBefore(jobs *bolt.Bucket, id string) (bool, error) {
+	data := jobs.Get([]byte(id))
+	if data == nil {
+		return false, nil
+	}
+	j, err := decode(data)
+	if err != nil {
+		return false, err
+	}
+	return j.Finished(), nil
+}
+
+// finishedKey orders j among the finished jobs by FinishedAt, or now when it is not set.
+func (s *BoltStore) finishedKey(j *Job) []byte {
+	finishedAt := s.now()
+	if j.FinishedAt != nil {
+		finishedAt = *j.FinishedAt
+	}
+	key := binary.BigEndian.AppendUint64(nil, uint64(finishedAt.UnixNano()))
+	return append(key, j.ID...)
+}
+
+// evict drops the oldest finished jobs past the retention or over the cap.
+func (s *BoltStore) evict(tx *bolt.Tx) error {
+	jobs, finished := tx.Bucket(jobsBucket), tx.Bucket(finishedBucket)
+	cutoff := s.now().Add(-s.opts.Retention)
+	c := finished.Cursor()
+
+	// Bucket stats leave out writes of the open transaction, so the index is counted.
+	count := 0
+	if s.opts.MaxFinished > 0 {
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			count++
+		}
+	}
+
+	// Keys are copied out first, as deleting under a cursor skips the next key.
+	var keys [][]byte
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		over := s.opts.MaxFinished > 0 && count-len(keys) > s.opts.MaxFinished
+		finishedAt := time.Unix(0, int64(binary.BigEndian.Uint64(k[:8])))
+		expired := s.opts.Retention > 0 && finishedAt.Before(cutoff)
+		if !over && !expired {
+			break
+		}
+		keys = append(keys, append([]byte(nil), k...))
+	}
+
+	for _, k := range keys {
+		if err := jobs.Delete(k[8:]); err != nil {
+			return err
+		}
+		if err := finished.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BoltStore) Get(_ context.Context, id string) (*Job, error) {
+	var j *Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		var err error
+		j, err = decode(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (s *BoltStore) List(_ context.Context) ([]*Job, error) {
+	var result []*Job
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, data []byte) error {
+			j, err := decode(data)
+			if err != nil {
+				return err
+			}
+			result = append(result, j)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
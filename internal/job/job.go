@@ -0,0 +1,70 @@
+package job
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+var (
+	ErrNotFound  = errors.New("job not found")
+	ErrQueueFull = errors.New("job queue is full")
+	ErrFinished  = errors.New("job has already finished")
+	ErrStopped   = errors.New("job manager is stopped")
+	// ErrNoCallbacks is returned for jobs with a callback when no Notifier is configured.
+	ErrNoCallbacks = errors.New("callback_url is not supported, as callbacks are not configured")
+)
+
+type Job struct {
+	ID         string                         `json:"id"`
+	Status     Status                         `json:"status"`
+	Request    request.CallProcedureRequest   `json:"request"`
+	Tenant     string                         `json:"tenant,omitempty"`
+	Principal  string                         `json:"principal,omitempty"`
+	Callback   *Callback                      `json:"callback,omitempty"`
+	Result     response.CallProcedureResponse `json:"result,omitempty"`
+	Error      string                         `json:"error,omitempty"`
+	CreatedAt  time.Time                      `json:"created_at"`
+	StartedAt  *time.Time                     `json:"started_at,omitempty"`
+	FinishedAt *time.Time                     `json:"finished_at,omitempty"`
+}
+
+// Callback is where the job result is delivered once the job finishes.
+// Header values are text/template templates rendered against the finished Job. They often
+// carry credentials, so they are left out of the job's JSON, which is returned by the API
+// and sent in the callback payload; stores keep them through encode.
+type Callback struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"-"`
+}
+
+// Finished reports whether the job has reached a terminal status.
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case StatusSucceeded, StatusFailed, StatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,324 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"sync"
+	"time"
+)
+
+type Executor interface {
+	CallProcedure(ctx context.Context, r request.CallProcedureRequest) (response.CallProcedureResponse, error)
+}
+
+// Notifier delivers finished jobs to their callback.
+type Notifier interface {
+	Notify(ctx context.Context, j *Job)
+}
+
+type Option func(*Manager)
+
+// WithNotifier sends every finished job that has a callback to n. Without it, jobs with a
+// callback are refused.
+func WithNotifier(n Notifier) Option {
+	return func(m *Manager) {
+		m.notifier = n
+	}
+}
+
+type queued struct {
+	id  string
+	ctx context.Context
+}
+
+// Manager runs submitted jobs on a bounded pool of workers.
+type Manager struct {
+	executor Executor
+	store    Store
+	notifier Notifier
+	workers  int
+	queue    chan queued
+	now      func() time.Time
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	stopped bool
+	wg      sync.WaitGroup
+
+	notifyCtx    context.Context
+	notifyCancel context.CancelFunc
+	notifyWg     sync.WaitGroup
+}
+
+func NewManager(executor Executor, store Store, workers, queueSize int, opts ...Option) *Manager {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	notifyCtx, notifyCancel := context.WithCancel(context.Background())
+	m := &Manager{
+		executor:     executor,
+		store:        store,
+		workers:      workers,
+		queue:        make(chan queued, queueSize),
+		now:          time.Now,
+		cancels:      make(map[string]context.CancelFunc),
+		notifyCtx:    notifyCtx,
+		notifyCancel: notifyCancel,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start marks jobs left unfinished by a previous run as failed and launches the workers.
+// Interrupted jobs are not re-run because the procedures they call may not be idempotent.
+func (m *Manager) Start(ctx context.Context) error {
+	jobs, err := m.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list jobs: %w", err)
+	}
+
+	for _, j := range jobs {
+		if j.Finished() {
+			continue
+		}
+		m.finish(j, nil, errors.New("interrupted by server restart"))
+		if err := m.store.Save(ctx, j); err != nil {
+			return fmt.Errorf("save job %s: %w", j.ID, err)
+		}
+	}
+
+	for i := 0; i < m.workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+	return nil
+}
+
+// Submit stores a new job and queues it. The request context only contributes its values
+// (request ID, principal and so on); its cancellation does not affect the job.
+func (m *Manager) Submit(ctx context.Context, req request.CreateJobRequest) (*Job, error) {
+	if req.CallbackURL != "" && m.notifier == nil {
+		return nil, ErrNoCallbacks
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("generate job id: %w", err)
+	}
+
+	j := &Job{
+		ID:        id,
+		Status:    StatusQueued,
+		Request:   req.CallProcedureRequest,
+		Tenant:    tenant.Name(ctx),
+		Principal: auth.PrincipalFrom(ctx),
+		CreatedAt: m.now(),
+	}
+	if req.CallbackURL != "" {
+		j.Callback = &Callback{URL: req.CallbackURL, Headers: req.CallbackHeaders}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		return nil, ErrStopped
+	}
+	if len(m.queue) == cap(m.queue) {
+		return nil, ErrQueueFull
+	}
+	if err := m.store.Save(ctx, j); err != nil {
+		return nil, fmt.Errorf("save job: %w", err)
+	}
+
+	// Only Submit sends on the queue and it holds m.mu, so the capacity check above guarantees room.
+	m.queue <- queued{id: id, ctx: context.WithoutCancel(ctx)}
+	return j, nil
+}
+
+// Get returns a job submitted by the principal and tenant of ctx. Other callers' jobs are
+// reported as not found.
+func (m *Manager) Get(ctx context.Context, id string) (*Job, error) {
+	return m.get(ctx, id)
+}
+
+// Cancel cancels a queued or running job. Running jobs are marked as canceled by their
+// worker once the procedure call returns.
+func (m *Manager) Cancel(ctx context.Context, id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, err := m.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case j.Finished():
+		return nil, ErrFinished
+	case j.Status == StatusQueued:
+		m.finish(j, nil, context.Canceled)
+		if err := m.store.Save(ctx, j); err != nil {
+			return nil, fmt.Errorf("save job: %w", err)
+		}
+		m.notify(j)
+	default:
+		if cancel, ok := m.cancels[id]; ok {
+			cancel()
+		}
+	}
+
+	return j, nil
+}
+
+func (m *Manager) get(ctx context.Context, id string) (*Job, error) {
+	j, err := m.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if j.Tenant != tenant.Name(ctx) || j.Principal != auth.PrincipalFrom(ctx) {
+		return nil, ErrNotFound
+	}
+	return j, nil
+}
+
+// Shutdown stops accepting jobs, cancels the running ones and waits for the workers to exit.
+// Callback deliveries still pending when ctx expires are abandoned.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.stopped {
+		m.stopped = true
+		close(m.queue)
+		for _, cancel := range m.cancels {
+			cancel()
+		}
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		m.notifyWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		m.notifyCancel()
+		return ctx.Err()
+	}
+}
+
+func (m *Manager) work() {
+	defer m.wg.Done()
+
+	for q := range m.queue {
+		m.run(q)
+	}
+}
+
+func (m *Manager) run(q queued) {
+	ctx, cancel := context.WithCancel(q.ctx)
+	defer cancel()
+
+	j, ok := m.start(q.id, cancel)
+	if !ok {
+		return
+	}
+
+	result, err := m.executor.CallProcedure(ctx, j.Request)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cancels, j.ID)
+
+	if err != nil && ctx.Err() != nil {
+		if m.stopped {
+			err = errors.New("interrupted by server shutdown")
+		} else {
+			err = context.Canceled
+		}
+	}
+	m.finish(j, result, err)
+	if err := m.store.Save(context.Background(), j); err != nil {
+		slog.ErrorContext(q.ctx, "failed to save job", "job_id", j.ID, "error", err)
+	}
+	m.notify(j)
+}
+
+// start marks a queued job as running. It reports false when the job was canceled while queued
+// or the manager is shutting down.
+func (m *Manager) start(id string, cancel context.CancelFunc) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, err := m.store.Get(context.Background(), id)
+	if err != nil {
+		slog.Error("failed to load job", "job_id", id, "error", err)
+		return nil, false
+	}
+	if j.Status != StatusQueued {
+		return nil, false
+	}
+
+	if m.stopped {
+		m.finish(j, nil, errors.New("interrupted by server shutdown"))
+		defer m.notify(j)
+	} else {
+		now := m.now()
+		j.Status = StatusRunning
+		j.StartedAt = &now
+		m.cancels[id] = cancel
+	}
+
+	if err := m.store.Save(context.Background(), j); err != nil {
+		slog.Error("failed to save job", "job_id", id, "error", err)
+		delete(m.cancels, id)
+		return nil, false
+	}
+	return j, j.Status == StatusRunning
+}
+
+// notify delivers j to its callback in the background. It must be called with m.mu held
+// so that Shutdown cannot miss a delivery that is being added.
+func (m *Manager) notify(j *Job) {
+	if m.notifier == nil || j.Callback == nil {
+		return
+	}
+
+	m.notifyWg.Add(1)
+	go func() {
+		defer m.notifyWg.Done()
+		m.notifier.Notify(m.notifyCtx, j)
+	}()
+}
+
+func (m *Manager) finish(j *Job, result response.CallProcedureResponse, err error) {
+	now := m.now()
+	j.FinishedAt = &now
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		j.Status = StatusCanceled
+	case err != nil:
+		j.Status = StatusFailed
+		j.Error = err.Error()
+	default:
+		j.Status = StatusSucceeded
+		j.Result = result
+	}
+}
@@ -0,0 +1,309 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingExecutor blocks every call until release is closed or the context is canceled
+type blockingExecutor struct {
+	started chan string
+	release chan struct{}
+	result  response.CallProcedureResponse
+	err     error
+}
+
+func newBlockingExecutor() *blockingExecutor {
+	return &blockingExecutor{
+		started: make(chan string, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (e *blockingExecutor) CallProcedure(ctx context.Context, r request.CallProcedureRequest) (response.CallProcedureResponse, error) {
+	e.started <- r.Name
+	select {
+	case <-e.release:
+		return e.result, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newRequest(name string) request.CreateJobRequest {
+	return request.CreateJobRequest{CallProcedureRequest: request.CallProcedureRequest{Name: name}}
+}
+
+func waitForStatus(t *testing.T, m *Manager, id string, status Status) *Job {
+	t.Helper()
+
+	var j *Job
+	require.Eventually(t, func() bool {
+		var err error
+		j, err = m.Get(context.Background(), id)
+		return err == nil && j.Status == status
+	}, time.Second, 5*time.Millisecond)
+	return j
+}
+
+func TestManager_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name           string
+		result         response.CallProcedureResponse
+		err            error
+		expectedStatus Status
+		expectedError  string
+	}{
+		{
+			name:           "successful job",
+			result:         response.CallProcedureResponse{"p_out": "ok"},
+			expectedStatus: StatusSucceeded,
+		},
+		{
+			name:           "failed job",
+			err:            errors.New("ORA-01403: no data found"),
+			expectedStatus: StatusFailed,
+			expectedError:  "ORA-01403: no data found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := newBlockingExecutor()
+			executor.result = tt.result
+			executor.err = tt.err
+
+			m := NewManager(executor, NewMemoryStore(StoreOptions{}), 1, 1)
+			require.NoError(t, m.Start(context.Background()))
+			defer m.Shutdown(context.Background())
+
+			j, err := m.Submit(context.Background(), newRequest("month_end"))
+			require.NoError(t, err)
+			assert.Equal(t, StatusQueued, j.Status)
+
+			<-executor.started
+			waitForStatus(t, m, j.ID, StatusRunning)
+			close(executor.release)
+
+			j = waitForStatus(t, m, j.ID, tt.expectedStatus)
+			assert.Equal(t, tt.result, j.Result)
+			assert.Equal(t, tt.expectedError, j.Error)
+			assert.NotNil(t, j.StartedAt)
+			assert.NotNil(t, j.FinishedAt)
+		})
+	}
+}
+
+func TestManager_QueueFull(t *testing.T) {
+	executor := newBlockingExecutor()
+	defer close(executor.release)
+
+	m := NewManager(executor, NewMemoryStore(StoreOptions{}), 1, 1)
+	require.NoError(t, m.Start(context.Background()))
+	defer m.Shutdown(context.Background())
+
+	_, err := m.Submit(context.Background(), newRequest("first"))
+	require.NoError(t, err)
+	<-executor.started
+
+	_, err = m.Submit(context.Background(), newRequest("second"))
+	require.NoError(t, err)
+
+	_, err = m.Submit(context.Background(), newRequest("third"))
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestManager_Cancel(t *testing.T) {
+	executor := newBlockingExecutor()
+	defer close(executor.release)
+
+	m := NewManager(executor, NewMemoryStore(StoreOptions{}), 1, 1)
+	require.NoError(t, m.Start(context.Background()))
+	defer m.Shutdown(context.Background())
+
+	running, err := m.Submit(context.Background(), newRequest("running"))
+	require.NoError(t, err)
+	<-executor.started
+	waitForStatus(t, m, running.ID, StatusRunning)
+
+	queuedJob, err := m.Submit(context.Background(), newRequest("queued"))
+	require.NoError(t, err)
+
+	// A queued job is canceled immediately and never reaches the executor
+	j, err := m.Cancel(context.Background(), queuedJob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCanceled, j.Status)
+
+	// A running job is canceled through its context
+	_, err = m.Cancel(context.Background(), running.ID)
+	require.NoError(t, err)
+	waitForStatus(t, m, running.ID, StatusCanceled)
+
+	_, err = m.Cancel(context.Background(), running.ID)
+	assert.ErrorIs(t, err, ErrFinished)
+
+	_, err = m.Cancel(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	select {
+	case name := <-executor.started:
+		t.Fatalf("canceled job %q was executed", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestManager_StartFailsInterruptedJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+
+	store, err := NewBoltStore(path, StoreOptions{})
+	require.NoError(t, err)
+	require.NoError(t, store.Save(context.Background(), &Job{ID: "done", Status: StatusSucceeded}))
+	require.NoError(t, store.Save(context.Background(), &Job{ID: "running", Status: StatusRunning}))
+	require.NoError(t, store.Close())
+
+	// Reopen the store to simulate a restart
+	store, err = NewBoltStore(path, StoreOptions{})
+	require.NoError(t, err)
+	defer store.Close()
+
+	m := NewManager(newBlockingExecutor(), store, 1, 1)
+	require.NoError(t, m.Start(context.Background()))
+	defer m.Shutdown(context.Background())
+
+	j, err := m.Get(context.Background(), "running")
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, j.Status)
+	assert.Equal(t, "interrupted by server restart", j.Error)
+
+	j, err = m.Get(context.Background(), "done")
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, j.Status)
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	executor := newBlockingExecutor()
+
+	m := NewManager(executor, NewMemoryStore(StoreOptions{}), 1, 1)
+	require.NoError(t, m.Start(context.Background()))
+
+	j, err := m.Submit(context.Background(), newRequest("long"))
+	require.NoError(t, err)
+	<-executor.started
+
+	require.NoError(t, m.Shutdown(context.Background()))
+
+	j, err = m.Get(context.Background(), j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, j.Status)
+	assert.Equal(t, "interrupted by server shutdown", j.Error)
+
+	_, err = m.Submit(context.Background(), newRequest("late"))
+	assert.ErrorIs(t, err, ErrStopped)
+}
+
+type recordingNotifier struct {
+	jobs chan *Job
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, j *Job) {
+	n.jobs <- j
+}
+
+func TestManager_NotifiesCallback(t *testing.T) {
+	executor := newBlockingExecutor()
+	executor.result = response.CallProcedureResponse{"p_out": "ok"}
+	close(executor.release)
+
+	notifier := &recordingNotifier{jobs: make(chan *Job, 2)}
+	m := NewManager(executor, NewMemoryStore(StoreOptions{}), 1, 2, WithNotifier(notifier))
+	require.NoError(t, m.Start(context.Background()))
+	defer m.Shutdown(context.Background())
+
+	_, err := m.Submit(context.Background(), newRequest("without_callback"))
+	require.NoError(t, err)
+
+	req := newRequest("with_callback")
+	req.CallbackURL = "http://localhost/hook"
+	withCallback, err := m.Submit(context.Background(), req)
+	require.NoError(t, err)
+
+	select {
+	case j := <-notifier.jobs:
+		assert.Equal(t, withCallback.ID, j.ID)
+		assert.Equal(t, StatusSucceeded, j.Status)
+		assert.Equal(t, "http://localhost/hook", j.Callback.URL)
+	case <-time.After(time.Second):
+		t.Fatal("callback was not notified")
+	}
+
+	require.NoError(t, m.Shutdown(context.Background()))
+	assert.Empty(t, notifier.jobs)
+}
+
+func TestManager_RefusesCallbackWithoutNotifier(t *testing.T) {
+	m := NewManager(newBlockingExecutor(), NewMemoryStore(StoreOptions{}), 1, 2)
+
+	req := newRequest("with_callback")
+	req.CallbackURL = "http://localhost/hook"
+	_, err := m.Submit(context.Background(), req)
+	assert.ErrorIs(t, err, ErrNoCallbacks)
+}
+
+func TestManager_TenantIsolation(t *testing.T) {
+	executor := newBlockingExecutor()
+	defer close(executor.release)
+
+	m := NewManager(executor, NewMemoryStore(StoreOptions{}), 1, 1)
+	require.NoError(t, m.Start(context.Background()))
+	defer m.Shutdown(context.Background())
+
+	acme := tenant.With(context.Background(), tenant.Tenant{Name: "acme", Schema: "ACME"})
+	globex := tenant.With(context.Background(), tenant.Tenant{Name: "globex", Schema: "GLOBEX"})
+
+	j, err := m.Submit(acme, newRequest("pkg.export"))
+	require.NoError(t, err)
+	assert.Equal(t, "acme", j.Tenant)
+
+	got, err := m.Get(acme, j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, j.ID, got.ID)
+
+	_, err = m.Get(globex, j.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = m.Cancel(globex, j.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = m.Get(context.Background(), j.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_PrincipalIsolation(t *testing.T) {
+	executor := newBlockingExecutor()
+	defer close(executor.release)
+
+	m := NewManager(executor, NewMemoryStore(StoreOptions{}), 1, 1)
+	require.NoError(t, m.Start(context.Background()))
+	defer m.Shutdown(context.Background())
+
+	alice := auth.WithAuthenticatedPrincipal(context.Background(), "alice")
+	bob := auth.WithAuthenticatedPrincipal(context.Background(), "bob")
+
+	j, err := m.Submit(alice, newRequest("pkg.export"))
+	require.NoError(t, err)
+
+	_, err = m.Get(alice, j.ID)
+	require.NoError(t, err)
+	_, err = m.Get(bob, j.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = m.Cancel(bob, j.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
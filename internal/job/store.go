@@ -0,0 +1,145 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Store persists job state. Implementations must be safe for concurrent use.
+type Store interface {
+	Save(ctx context.Context, j *Job) error
+	Get(ctx context.Context, id string) (*Job, error)
+	List(ctx context.Context) ([]*Job, error)
+	Close() error
+}
+
+// record is the stored form of a job, with the callback headers its JSON leaves out.
+type record struct {
+	Job
+	CallbackHeaders map[string]string `json:"callback_headers,omitempty"`
+}
+
+func encode(j *Job) ([]byte, error) {
+	r := record{Job: *j}
+	if j.Callback != nil {
+		r.CallbackHeaders = j.Callback.Headers
+	}
+	return json.Marshal(r)
+}
+
+func decode(data []byte) (*Job, error) {
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	if r.Callback != nil {
+		r.Callback.Headers = r.CallbackHeaders
+	}
+	return &r.Job, nil
+}
+
+// StoreOptions bounds how many finished jobs a store keeps. Queued and running jobs are
+// never evicted.
+type StoreOptions struct {
+	// Retention is how long a finished job stays readable; zero keeps it until MaxFinished
+	// pushes it out.
+	Retention time.Duration
+	// MaxFinished caps the number of finished jobs kept, evicting the oldest first; zero
+	// means no cap.
+	MaxFinished int
+}
+
+type memoryEntry struct {
+	data       []byte
+	finishedAt *time.Time
+}
+
+type MemoryStore struct {
+	opts StoreOptions
+	now  func() time.Time
+
+	mu   sync.RWMutex
+	jobs map[string]memoryEntry
+	// finished holds the IDs of finished jobs in the order they finished.
+	finished []string
+}
+
+func NewMemoryStore(opts StoreOptions) *MemoryStore {
+	return &MemoryStore{opts: opts, now: time.Now, jobs: make(map[string]memoryEntry)}
+}
+
+// Jobs are kept serialized so that callers never share state with the store.
+func (s *MemoryStore) Save(_ context.Context, j *Job) error {
+	data, err := encode(j)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.jobs[j.ID]
+	entry := memoryEntry{data: data}
+	if j.Finished() {
+		entry.finishedAt = j.FinishedAt
+		if entry.finishedAt == nil {
+			now := s.now()
+			entry.finishedAt = &now
+		}
+		if !ok || previous.finishedAt == nil {
+			s.finished = append(s.finished, j.ID)
+		}
+	}
+	s.jobs[j.ID] = entry
+	s.evict()
+	return nil
+}
+
+// evict drops the oldest finished jobs past the retention or over the cap.
+func (s *MemoryStore) evict() {
+	cutoff := s.now().Add(-s.opts.Retention)
+	n := 0
+	for n < len(s.finished) {
+		entry, ok := s.jobs[s.finished[n]]
+		over := s.opts.MaxFinished > 0 && len(s.finished)-n > s.opts.MaxFinished
+		expired := ok && s.opts.Retention > 0 && entry.finishedAt.Before(cutoff)
+		if ok && !over && !expired {
+			break
+		}
+		delete(s.jobs, s.finished[n])
+		n++
+	}
+	s.finished = s.finished[n:]
+}
+
+func (s *MemoryStore) Get(_ context.Context, id string) (*Job, error) {
+	s.mu.RLock()
+	entry, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return decode(entry.data)
+}
+
+func (s *MemoryStore) List(_ context.Context) ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*Job, 0, len(s.jobs))
+	for _, entry := range s.jobs {
+		j, err := decode(entry.data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, j)
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
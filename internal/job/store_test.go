@@ -0,0 +1,127 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores_KeepCallbackHeaders(t *testing.T) {
+	bolt, err := NewBoltStore(filepath.Join(t.TempDir(), "jobs.db"), StoreOptions{})
+	require.NoError(t, err)
+	defer bolt.Close()
+
+	stores := map[string]Store{"memory": NewMemoryStore(StoreOptions{}), "bolt": bolt}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			j := &Job{ID: "job-1", Status: StatusQueued, Callback: &Callback{
+				URL:     "https://example.com/hook",
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			}}
+			require.NoError(t, store.Save(context.Background(), j))
+
+			got, err := store.Get(context.Background(), "job-1")
+			require.NoError(t, err)
+			assert.Equal(t, j.Callback, got.Callback)
+
+			listed, err := store.List(context.Background())
+			require.NoError(t, err)
+			require.Len(t, listed, 1)
+			assert.Equal(t, j.Callback, listed[0].Callback)
+		})
+	}
+}
+
+func TestJob_JSONOmitsCallbackHeaders(t *testing.T) {
+	data, err := json.Marshal(&Job{ID: "job-1", Callback: &Callback{
+		URL:     "https://example.com/hook",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Bearer token")
+	assert.Contains(t, string(data), "https://example.com/hook")
+}
+
+func TestMemoryStore_Eviction(t *testing.T) {
+	at := func(minutes int) *time.Time {
+		t := time.Date(2026, 1, 1, 12, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	store := NewMemoryStore(StoreOptions{Retention: time.Hour, MaxFinished: 2})
+	store.now = func() time.Time { return *at(30) }
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, &Job{ID: "queued", Status: StatusQueued}))
+	for i, id := range []string{"first", "second", "third"} {
+		require.NoError(t, store.Save(ctx, &Job{ID: id, Status: StatusSucceeded, FinishedAt: at(i)}))
+	}
+	// Saving a finished job again does not count it twice.
+	require.NoError(t, store.Save(ctx, &Job{ID: "third", Status: StatusSucceeded, FinishedAt: at(2)}))
+
+	_, err := store.Get(ctx, "first")
+	assert.ErrorIs(t, err, ErrNotFound)
+	for _, id := range []string{"queued", "second", "third"} {
+		_, err := store.Get(ctx, id)
+		assert.NoError(t, err, id)
+	}
+
+	// Past the retention, finished jobs go while unfinished ones stay.
+	store.now = func() time.Time { return *at(90) }
+	require.NoError(t, store.Save(ctx, &Job{ID: "fourth", Status: StatusSucceeded, FinishedAt: at(90)}))
+	jobs, err := store.List(ctx)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	assert.ElementsMatch(t, []string{"queued", "fourth"}, ids)
+}
+
+func TestBoltStore_Eviction(t *testing.T) {
+	at := func(minutes int) *time.Time {
+		t := time.Date(2026, 1, 1, 12, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	store, err := NewBoltStore(path, StoreOptions{Retention: time.Hour, MaxFinished: 2})
+	require.NoError(t, err)
+	store.now = func() time.Time { return *at(30) }
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, &Job{ID: "queued", Status: StatusQueued}))
+	// A job saved while running and again once finished is indexed once.
+	require.NoError(t, store.Save(ctx, &Job{ID: "first", Status: StatusRunning}))
+	for i, id := range []string{"first", "second", "third"} {
+		require.NoError(t, store.Save(ctx, &Job{ID: id, Status: StatusSucceeded, FinishedAt: at(i)}))
+	}
+	// Saving a finished job again does not count it twice.
+	require.NoError(t, store.Save(ctx, &Job{ID: "third", Status: StatusSucceeded, FinishedAt: at(2)}))
+
+	_, err = store.Get(ctx, "first")
+	assert.ErrorIs(t, err, ErrNotFound)
+	for _, id := range []string{"queued", "second", "third"} {
+		_, err := store.Get(ctx, id)
+		assert.NoError(t, err, id)
+	}
+
+	// Past the retention, finished jobs go while unfinished ones stay, also when the
+	// store is reopened.
+	require.NoError(t, store.Close())
+	store, err = NewBoltStore(path, StoreOptions{Retention: time.Hour, MaxFinished: 2})
+	require.NoError(t, err)
+	defer store.Close()
+	store.now = func() time.Time { return *at(90) }
+	require.NoError(t, store.Save(ctx, &Job{ID: "fourth", Status: StatusSucceeded, FinishedAt: at(90)}))
+	jobs, err := store.List(ctx)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	assert.ElementsMatch(t, []string{"queued", "fourth"}, ids)
+}
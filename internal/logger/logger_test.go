@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_ContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(&buf, "debug")
+	require.NoError(t, err)
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := With(r.Context(), "procedure", "pkg.proc")
+		log.DebugContext(ctx, "calling procedure")
+	})
+	handler = middleware.RequestID(handler)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "DEBUG", record["level"])
+	assert.Equal(t, "calling procedure", record["msg"])
+	assert.Equal(t, "pkg.proc", record["procedure"])
+	assert.NotEmpty(t, record["request_id"])
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(&buf, "warn")
+	require.NoError(t, err)
+
+	log.Info("dropped")
+	assert.Empty(t, buf.String())
+
+	_, err = New(&buf, "verbose")
+	assert.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(&buf, "info")
+	require.NoError(t, err)
+
+	previous := slog.Default()
+	slog.SetDefault(log)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/jobs/42", nil))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "/api/v1/jobs/42", record["path"])
+	assert.Equal(t, float64(http.StatusBadGateway), record["status"])
+}
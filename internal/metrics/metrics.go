@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+
+	// otherProcedure replaces procedure names once maxProcedures distinct names have been seen,
+	// so that clients calling arbitrary names cannot blow up the series count.
+	otherProcedure = "other"
+	maxProcedures  = 500
+)
+
+type Metrics struct {
+	callDuration  *prometheus.HistogramVec
+	infoDuration  *prometheus.HistogramVec
+	oraErrors     *prometheus.CounterVec
+	cursorRows    *prometheus.HistogramVec
+	requestBytes  *prometheus.HistogramVec
+	responseBytes *prometheus.HistogramVec
+
+	mu         sync.Mutex
+	procedures map[string]struct{}
+}
+
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "oracle",
+			Name:      "procedure_call_duration_seconds",
+			Help:      "Duration of stored procedure calls.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300, 1200},
+		}, []string{"procedure", "outcome"}),
+		infoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "oracle",
+			Name:      "procedure_info_duration_seconds",
+			Help:      "Duration of procedure metadata lookups.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+		oraErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "oracle",
+			Name:      "procedure_errors_total",
+			Help:      "Failed stored procedure calls by Oracle error code.",
+		}, []string{"procedure", "code"}),
+		cursorRows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "oracle",
+			Name:      "procedure_cursor_rows",
+			Help:      "Rows returned through REF CURSOR parameters per call.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{"procedure"}),
+		requestBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "oracle",
+			Name:      "procedure_request_bytes",
+			Help:      "JSON size of procedure call parameters.",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+		}, []string{"procedure"}),
+		responseBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "oracle",
+			Name:      "procedure_response_bytes",
+			Help:      "JSON size of procedure call results.",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+		}, []string{"procedure"}),
+		procedures: make(map[string]struct{}),
+	}
+
+	reg.MustRegister(m.callDuration, m.infoDuration, m.oraErrors, m.cursorRows, m.requestBytes, m.responseBytes)
+	return m
+}
+
+func (m *Metrics) procedureLabel(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.procedures[name]; ok {
+		return name
+	}
+	if len(m.procedures) >= maxProcedures {
+		return otherProcedure
+	}
+	m.procedures[name] = struct{}{}
+	return name
+}
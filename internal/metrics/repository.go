@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/service"
+	"oracle-golang/pkg/util"
+	"time"
+)
+
+// Repository instruments a service.Repository without touching its implementation.
+type Repository struct {
+	next    service.Repository
+	metrics *Metrics
+}
+
+func NewRepository(next service.Repository, metrics *Metrics) *Repository {
+	return &Repository{next: next, metrics: metrics}
+}
+
+func (r *Repository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	procedure := r.metrics.procedureLabel(name)
+	r.metrics.requestBytes.WithLabelValues(procedure).Observe(jsonSize(params))
+
+	start := time.Now()
+	result, err := r.next.CallProcedure(ctx, name, params)
+	elapsed := time.Since(start).Seconds()
+
+	if err != nil {
+		code := util.OraErrorCode(err)
+		if code == "" {
+			code = "unknown"
+		}
+		r.metrics.callDuration.WithLabelValues(procedure, OutcomeError).Observe(elapsed)
+		r.metrics.oraErrors.WithLabelValues(procedure, code).Inc()
+		return result, err
+	}
+
+	r.metrics.callDuration.WithLabelValues(procedure, OutcomeSuccess).Observe(elapsed)
+	r.metrics.responseBytes.WithLabelValues(procedure).Observe(jsonSize(result))
+	if rows, ok := cursorRows(result); ok {
+		r.metrics.cursorRows.WithLabelValues(procedure).Observe(float64(rows))
+	}
+	return result, nil
+}
+
+func (r *Repository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	start := time.Now()
+	result, err := r.next.GetProcedureInfo(ctx, procedureName)
+
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	r.metrics.infoDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	return result, err
+}
+
+// ListProcedures is timed with the other metadata lookups.
+func (r *Repository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	start := time.Now()
+	result, err := r.next.ListProcedures(ctx, filter)
+
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	r.metrics.infoDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	return result, err
+}
+
+// cursorRows sums the rows of every cursor in a call result and reports whether there was any cursor.
+func cursorRows(result map[string]any) (int, bool) {
+	var total int
+	var found bool
+	for _, v := range result {
+		if rows, ok := v.([]map[string]any); ok {
+			total += len(rows)
+			found = true
+		}
+	}
+	return total, found
+}
+
+func jsonSize(v any) float64 {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return float64(len(data))
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository returns canned results for every call
+type fakeRepository struct {
+	result map[string]any
+	info   *response.GetProcedureInfoResponse
+	err    error
+}
+
+func (f *fakeRepository) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	return f.result, f.err
+}
+
+func (f *fakeRepository) GetProcedureInfo(context.Context, string) (*response.GetProcedureInfoResponse, error) {
+	return f.info, f.err
+}
+
+func (f *fakeRepository) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{}, f.err
+}
+
+func TestRepository_CallProcedure(t *testing.T) {
+	tests := []struct {
+		name               string
+		repo               *fakeRepository
+		expectedOutcome    string
+		expectedErrorCode  string
+		expectedCursorRows int
+	}{
+		{
+			name: "successful call with cursor",
+			repo: &fakeRepository{result: map[string]any{
+				"p_total": 2.0,
+				"p_rows":  []map[string]any{{"ID": 1}, {"ID": 2}, {"ID": 3}},
+			}},
+			expectedOutcome:    OutcomeSuccess,
+			expectedCursorRows: 3,
+		},
+		{
+			name:              "oracle error",
+			repo:              &fakeRepository{err: errors.New("execution failed for procedure 'pkg.proc': ORA-01403: no data found")},
+			expectedOutcome:   OutcomeError,
+			expectedErrorCode: "ORA-01403",
+		},
+		{
+			name:              "error without oracle code",
+			repo:              &fakeRepository{err: context.DeadlineExceeded},
+			expectedOutcome:   OutcomeError,
+			expectedErrorCode: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			m := New(reg)
+			repo := NewRepository(tt.repo, m)
+
+			params := []request.ProcedureParam{{Name: "p_id", Type: "NUMBER", Value: 1, Direction: "IN"}}
+			_, err := repo.CallProcedure(context.Background(), "pkg.proc", params)
+			assert.Equal(t, tt.repo.err, err)
+
+			assert.Equal(t, 1, testutil.CollectAndCount(m.callDuration))
+			assert.Equal(t, uint64(1), histogramCount(t, m.callDuration.WithLabelValues("PKG.PROC", tt.expectedOutcome)))
+			assert.Equal(t, uint64(1), histogramCount(t, m.requestBytes.WithLabelValues("PKG.PROC")))
+
+			if tt.expectedErrorCode != "" {
+				assert.Equal(t, float64(1), testutil.ToFloat64(m.oraErrors.WithLabelValues("PKG.PROC", tt.expectedErrorCode)))
+				assert.Equal(t, 0, testutil.CollectAndCount(m.responseBytes))
+			} else {
+				assert.Equal(t, 0, testutil.CollectAndCount(m.oraErrors))
+				assert.Equal(t, float64(tt.expectedCursorRows), histogramSum(t, m.cursorRows.WithLabelValues("PKG.PROC")))
+			}
+		})
+	}
+}
+
+func TestRepository_GetProcedureInfo(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	repo := NewRepository(&fakeRepository{info: &response.GetProcedureInfoResponse{Name: "pkg.proc"}}, m)
+
+	result, err := repo.GetProcedureInfo(context.Background(), "pkg.proc")
+
+	require.NoError(t, err)
+	assert.Equal(t, "pkg.proc", result.Name)
+	assert.Equal(t, uint64(1), histogramCount(t, m.infoDuration.WithLabelValues(OutcomeSuccess)))
+}
+
+func TestMetrics_ProcedureLabelLimit(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	for i := 0; i < maxProcedures; i++ {
+		m.procedureLabel(fmt.Sprintf("proc_%d", i))
+	}
+
+	assert.Equal(t, otherProcedure, m.procedureLabel("one_too_many"))
+}
+
+func histogramCount(t *testing.T, o prometheus.Observer) uint64 {
+	t.Helper()
+	return writeHistogram(t, o).GetSampleCount()
+}
+
+func histogramSum(t *testing.T, o prometheus.Observer) float64 {
+	t.Helper()
+	return writeHistogram(t, o).GetSampleSum()
+}
+
+func writeHistogram(t *testing.T, o prometheus.Observer) *dto.Histogram {
+	t.Helper()
+
+	var metric dto.Metric
+	require.NoError(t, o.(prometheus.Metric).Write(&metric))
+	return metric.GetHistogram()
+}
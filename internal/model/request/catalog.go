@@ -0,0 +1,64 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+// ListProceduresRequest filters the procedure catalog. Name is a pattern in which * matches
+// any run of characters and ? a single one; everything else, including _ and %, matches
+// literally. Names are compared in upper case, as Oracle stores them.
+type ListProceduresRequest struct {
+	Owner   string `json:"owner,omitempty"`
+	Package string `json:"package,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Type is the type of the object holding the subprogram: PROCEDURE, FUNCTION or PACKAGE.
+	Type   string `json:"type,omitempty"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	// Datasource names the database to list. Empty means the default data source.
+	Datasource string `json:"datasource,omitempty"`
+	// Exposed, when not nil, limits the catalog to these subprograms, given as
+	// owner.package.name or owner.name, and the packages holding them. It comes from the
+	// data source's policy, never from the client.
+	Exposed []string `json:"-"`
+}
+
+func (r *ListProceduresRequest) Validate() error {
+	switch strings.ToUpper(r.Type) {
+	case "", "PROCEDURE", "FUNCTION", "PACKAGE":
+	default:
+		return fmt.Errorf("type must be PROCEDURE, FUNCTION or PACKAGE, got %q", r.Type)
+	}
+	if r.Limit < 1 || r.Limit > MaxListLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxListLimit)
+	}
+	if r.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	return nil
+}
+
+// NamePattern returns Name as a LIKE pattern with \ as the escape character.
+func (r *ListProceduresRequest) NamePattern() string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(r.Name) {
+		switch c {
+		case '*':
+			b.WriteRune('%')
+		case '?':
+			b.WriteRune('_')
+		case '%', '_', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
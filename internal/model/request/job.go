@@ -0,0 +1,43 @@
+package request
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+// CreateJobRequest is a CallProcedureRequest with optional completion callback settings.
+type CreateJobRequest struct {
+	CallProcedureRequest
+	CallbackURL     string            `json:"callback_url,omitempty"`
+	CallbackHeaders map[string]string `json:"callback_headers,omitempty"`
+}
+
+func (r *CreateJobRequest) Validate() error {
+	if err := r.CallProcedureRequest.Validate(); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(r.CallbackURL) == "" {
+		if len(r.CallbackHeaders) > 0 {
+			return fmt.Errorf("callback_headers require callback_url")
+		}
+		return nil
+	}
+
+	u, err := url.Parse(r.CallbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("callback_url must be an absolute http or https URL")
+	}
+
+	for name, value := range r.CallbackHeaders {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("callback header name is required")
+		}
+		if _, err := template.New(name).Parse(value); err != nil {
+			return fmt.Errorf("callback header %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -3,12 +3,42 @@ package request
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+// identifier matches unquoted Oracle identifiers. Procedure and parameter names are
+// written into the PL/SQL block of a call, so they must be nothing else.
+var identifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*$`)
+
+// ValidateName rejects procedure names that are not one to three identifiers separated by
+// dots: procedure, package.procedure or schema.package.procedure.
+func ValidateName(name string) error {
+	parts := strings.Split(name, ".")
+	if len(parts) > 3 {
+		return fmt.Errorf("procedure name %q has more than three parts", name)
+	}
+	for _, part := range parts {
+		if !identifier.MatchString(part) {
+			return fmt.Errorf("procedure name %q is not a valid identifier", name)
+		}
+	}
+	return nil
+}
+
+// ValidateParamName rejects parameter names that are not a single identifier.
+func ValidateParamName(name string) error {
+	if !identifier.MatchString(name) {
+		return fmt.Errorf("param name %q is not a valid identifier", name)
+	}
+	return nil
+}
+
 type CallProcedureRequest struct {
 	Name   string           `json:"name"`
 	Params []ProcedureParam `json:"params"`
+	// Datasource names the database to call. Empty means the default data source.
+	Datasource string `json:"datasource,omitempty"`
 }
 
 type ProcedureParam struct {
@@ -22,6 +52,9 @@ func (r *CallProcedureRequest) Validate() error {
 	if strings.TrimSpace(r.Name) == "" {
 		return errors.New("procedure name is required")
 	}
+	if err := ValidateName(r.Name); err != nil {
+		return err
+	}
 
 	for i, p := range r.Params {
 		if strings.TrimSpace(p.Name) != "" ||
@@ -31,6 +64,9 @@ func (r *CallProcedureRequest) Validate() error {
 			if strings.TrimSpace(p.Name) == "" {
 				return fmt.Errorf("param[%d] name is required", i)
 			}
+			if err := ValidateParamName(p.Name); err != nil {
+				return fmt.Errorf("param[%d]: %w", i, err)
+			}
 			if strings.TrimSpace(p.Type) == "" {
 				return fmt.Errorf("param[%d] type is required", i)
 			}
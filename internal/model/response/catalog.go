@@ -0,0 +1,28 @@
+package response
+
+import "time"
+
+// ProcedureSummary is one entry of the procedure catalog: a standalone procedure or
+// function, a package, or a subprogram of a package.
+type ProcedureSummary struct {
+	Owner string `json:"owner"`
+	// Package is set for subprograms of a package.
+	Package string `json:"package,omitempty"`
+	Name    string `json:"name"`
+	// FullName is the name to pass to /call and /info.
+	FullName string `json:"full_name"`
+	// ObjectType is the type of the object holding the entry: PROCEDURE, FUNCTION or PACKAGE.
+	ObjectType  string    `json:"object_type"`
+	Status      string    `json:"status"`
+	LastDDLTime time.Time `json:"last_ddl_time"`
+	// Overloads counts the overloads of a package subprogram.
+	Overloads int `json:"overloads,omitempty"`
+}
+
+type ListProceduresResponse struct {
+	Items  []ProcedureSummary `json:"items"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+	// NextOffset is the offset of the next page, absent on the last one.
+	NextOffset *int `json:"next_offset,omitempty"`
+}
@@ -2,7 +2,7 @@ package response
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
@@ -39,7 +39,7 @@ func WriteJSON(w http.ResponseWriter, statusCode int, resp HttpResponse) {
 	w.WriteHeader(statusCode)
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		log.Println("JSON encoding error", err)
+		slog.Error("JSON encoding error", "error", err)
 
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
@@ -1,4 +1,59 @@
 package response
 
 type CallProcedureResponse map[string]any
-type GetProcedureInfoResponse []map[string]any
+
+// GetProcedureInfoResponse describes a procedure or function from ALL_ARGUMENTS. Package
+// subprograms can be overloaded; standalone ones have a single entry in Overloads.
+type GetProcedureInfoResponse struct {
+	Name string `json:"name"`
+	// Target is the object Name resolves to after synonyms.
+	Target ProcedureTarget `json:"target"`
+	// Description is the comment next to the declaration in ALL_SOURCE, if any.
+	Description string              `json:"description,omitempty"`
+	Overloads   []ProcedureOverload `json:"overloads"`
+}
+
+// ProcedureTarget is a resolved procedure or function, in a package when Package is set.
+type ProcedureTarget struct {
+	Owner   string `json:"owner"`
+	Package string `json:"package,omitempty"`
+	Name    string `json:"name"`
+}
+
+// String returns the owner-qualified name of the target.
+func (t ProcedureTarget) String() string {
+	if t.Package == "" {
+		return t.Owner + "." + t.Name
+	}
+	return t.Owner + "." + t.Package + "." + t.Name
+}
+
+type ProcedureOverload struct {
+	// Overload numbers the overloads of a package subprogram, starting at 1. It is empty
+	// for subprograms that are not overloaded.
+	Overload string `json:"overload,omitempty"`
+	// Return is the return value of a function, nil for procedures.
+	Return    *ProcedureArgument  `json:"return,omitempty"`
+	Arguments []ProcedureArgument `json:"arguments"`
+}
+
+// ProcedureArgument is an argument, a return value, or a component of a composite one.
+// Length, precision and scale are nil where Oracle leaves them empty.
+type ProcedureArgument struct {
+	Name          string `json:"name,omitempty"`
+	Position      int    `json:"position"`
+	DataType      string `json:"data_type"`
+	InOut         string `json:"in_out,omitempty"`
+	DataLength    *int   `json:"data_length,omitempty"`
+	DataPrecision *int   `json:"data_precision,omitempty"`
+	DataScale     *int   `json:"data_scale,omitempty"`
+	TypeOwner     string `json:"type_owner,omitempty"`
+	TypeName      string `json:"type_name,omitempty"`
+	TypeSubname   string `json:"type_subname,omitempty"`
+	PLSType       string `json:"pls_type,omitempty"`
+	Defaulted     bool   `json:"defaulted"`
+	DefaultValue  string `json:"default_value,omitempty"`
+	// Fields holds the next DATA_LEVEL: the fields of a record, or the element of a
+	// collection.
+	Fields []ProcedureArgument `json:"fields,omitempty"`
+}
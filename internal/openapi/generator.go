@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"context"
+	"log/slog"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/model/response"
+	"strings"
+)
+
+const (
+	jsonContent  = "application/json"
+	errorRef     = "#/components/responses/Error"
+	envelopeName = "Envelope"
+)
+
+// Service looks up procedure metadata in the data source selected by datasource.WithName.
+type Service interface {
+	GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error)
+}
+
+// Procedure is a procedure to document, named as in the policy of its data source.
+type Procedure struct {
+	Name string
+	// ReadOnly procedures are also documented as GET operations taking their IN arguments
+	// from the query string.
+	ReadOnly bool
+}
+
+// Generator builds an OpenAPI document with one operation per procedure of a data source.
+type Generator struct {
+	service    Service
+	procedures map[string][]Procedure
+	fallback   string
+}
+
+// NewGenerator documents the procedures listed for each data source. Requests that name
+// no data source get the fallback one.
+func NewGenerator(service Service, procedures map[string][]Procedure, fallback string) *Generator {
+	return &Generator{service: service, procedures: procedures, fallback: fallback}
+}
+
+// Document builds the document of the data source selected in ctx for an API served under
+// basePath. Metadata is read on every call, so the document follows the database.
+// Procedures whose metadata cannot be read are left out and logged.
+func (g *Generator) Document(ctx context.Context, basePath string) (*Document, error) {
+	name := datasource.NameFrom(ctx)
+	if name == "" {
+		name = g.fallback
+	}
+	procedures, ok := g.procedures[name]
+	if !ok {
+		return nil, &datasource.UnknownError{Name: name}
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Stored procedures",
+			Description: "Procedures of the " + name + " data source.",
+			Version:     "v1",
+		},
+		Servers: []Server{{URL: basePath}},
+		Paths:   make(map[string]*PathItem, len(procedures)),
+		Components: Components{
+			Schemas: map[string]*Schema{
+				envelopeName: {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"message": {Type: "string"},
+						"status":  {Type: "boolean"},
+						"data":    {Type: "object"},
+					},
+					Required: []string{"message", "status", "data"},
+				},
+			},
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "The call failed.",
+					Content:     map[string]MediaType{jsonContent: {Schema: &Schema{Ref: "#/components/schemas/" + envelopeName}}},
+				},
+			},
+		},
+	}
+
+	for _, p := range procedures {
+		info, err := g.service.GetProcedureInfo(ctx, p.Name)
+		if err != nil {
+			slog.WarnContext(ctx, "leaving procedure out of the OpenAPI document", "procedure", p.Name, "error", err)
+			continue
+		}
+		if len(info.Overloads) == 0 {
+			slog.WarnContext(ctx, "leaving unknown procedure out of the OpenAPI document", "procedure", p.Name)
+			continue
+		}
+		if info.Overloads[0].Return != nil {
+			slog.WarnContext(ctx, "leaving function out of the OpenAPI document; only procedures can be called", "procedure", p.Name)
+			continue
+		}
+
+		path := Path(info.Target)
+		if _, ok := doc.Paths[path]; ok {
+			continue
+		}
+		doc.Paths[path] = pathItem(p, info)
+	}
+	return doc, nil
+}
+
+// Path returns the resource-style path of a procedure: /p/{schema}/{package}/{procedure},
+// without the package segment for standalone procedures.
+func Path(target response.ProcedureTarget) string {
+	parts := []string{"", "p", target.Owner, target.Package, target.Name}
+	if target.Package == "" {
+		parts = []string{"", "p", target.Owner, target.Name}
+	}
+	return strings.ToLower(strings.Join(parts, "/"))
+}
+
+// pathItem documents the first overload of a procedure. Overloads share a path, and
+// callers choose between them by the arguments they pass.
+func pathItem(p Procedure, info *response.GetProcedureInfoResponse) *PathItem {
+	overload := info.Overloads[0]
+	description := info.Description
+	if len(info.Overloads) > 1 {
+		description = strings.TrimSpace(description + "\n\nOnly the first of the overloads is documented.")
+	}
+	tag := info.Target.Package
+	if tag == "" {
+		tag = info.Target.Owner
+	}
+
+	input := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	output := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	var query []Parameter
+	for _, arg := range overload.Arguments {
+		name := strings.ToLower(arg.Name)
+		schema := argumentSchema(arg)
+		if arg.InOut != "OUT" {
+			input.Properties[name] = schema
+			if !arg.Defaulted {
+				input.Required = append(input.Required, name)
+			}
+			if schema.Type != "object" && schema.Type != "array" {
+				query = append(query, Parameter{Name: name, In: "query", Required: !arg.Defaulted, Schema: schema})
+			}
+		}
+		if arg.InOut != "IN" {
+			out := *schema
+			out.Nullable = true
+			output.Properties[name] = &out
+		}
+	}
+
+	op := func(method string) *Operation {
+		return &Operation{
+			OperationID: method + "_" + strings.ToLower(strings.ReplaceAll(info.Target.String(), ".", "_")),
+			Summary:     p.Name,
+			Description: description,
+			Tags:        []string{strings.ToLower(tag)},
+			Responses: map[string]*Response{
+				"200": {
+					Description: "Values of the OUT arguments.",
+					Content: map[string]MediaType{jsonContent: {Schema: &Schema{
+						Type: "object",
+						Properties: map[string]*Schema{
+							"message": {Type: "string"},
+							"status":  {Type: "boolean"},
+							"data":    output,
+						},
+					}}},
+				},
+				"default": {Ref: errorRef},
+			},
+		}
+	}
+
+	item := &PathItem{Post: op("post")}
+	if len(input.Properties) > 0 {
+		item.Post.RequestBody = &RequestBody{
+			Required: len(input.Required) > 0,
+			Content:  map[string]MediaType{jsonContent: {Schema: input}},
+		}
+	}
+	// Composite arguments cannot be passed in the query string.
+	if p.ReadOnly && len(query) == len(input.Properties) {
+		item.Get = op("get")
+		item.Get.Parameters = query
+	}
+	return item
+}
+
+// argumentSchema maps the Oracle type of an argument to JSON Schema, following how calls
+// encode values: numbers as JSON numbers, dates as RFC 3339 strings and binary values as
+// base64.
+func argumentSchema(arg response.ProcedureArgument) *Schema {
+	s := &Schema{Description: typeName(arg)}
+	switch arg.DataType {
+	case "NUMBER", "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE":
+		s.Type = "number"
+		if isInteger(arg) {
+			s.Type = "integer"
+		}
+	case "BINARY_INTEGER", "PL/SQL PLS INTEGER", "PLS_INTEGER", "PL/SQL BINARY INTEGER":
+		s.Type = "integer"
+	case "VARCHAR2", "VARCHAR", "CHAR", "NVARCHAR2", "NCHAR":
+		s.Type = "string"
+		s.MaxLength = arg.DataLength
+	case "CLOB", "NCLOB", "LONG", "ROWID", "UROWID":
+		s.Type = "string"
+	case "DATE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		s.Type = "string"
+		s.Format = "date-time"
+	case "RAW", "LONG RAW", "BLOB":
+		s.Type = "string"
+		s.Format = "byte"
+	case "PL/SQL BOOLEAN", "BOOLEAN":
+		s.Type = "boolean"
+	case "REF CURSOR":
+		s.Type = "array"
+		s.Items = &Schema{Type: "object", Description: "A row, keyed by column name."}
+	case "PL/SQL RECORD", "OBJECT":
+		s.Type = "object"
+		s.Properties = make(map[string]*Schema, len(arg.Fields))
+		for _, field := range arg.Fields {
+			s.Properties[strings.ToLower(field.Name)] = argumentSchema(field)
+		}
+	case "TABLE", "VARRAY", "PL/SQL TABLE", "PL/SQL INDEX TABLE":
+		s.Type = "array"
+		s.Items = &Schema{}
+		if len(arg.Fields) > 0 {
+			s.Items = argumentSchema(arg.Fields[0])
+		}
+	}
+	return s
+}
+
+func isInteger(arg response.ProcedureArgument) bool {
+	switch arg.PLSType {
+	case "INTEGER", "INT", "SMALLINT", "PLS_INTEGER", "BINARY_INTEGER", "NATURAL", "POSITIVE", "SIMPLE_INTEGER":
+		return true
+	}
+	return arg.DataType == "NUMBER" && arg.DataScale != nil && *arg.DataScale == 0 && arg.DataPrecision != nil
+}
+
+// typeName describes the Oracle type of an argument: the declared type for user-defined
+// ones, the PL/SQL type otherwise.
+func typeName(arg response.ProcedureArgument) string {
+	if arg.TypeName != "" {
+		name := arg.TypeOwner + "." + arg.TypeName
+		if arg.TypeSubname != "" {
+			name += "." + arg.TypeSubname
+		}
+		return strings.TrimPrefix(name, ".")
+	}
+	if arg.PLSType != "" {
+		return arg.PLSType
+	}
+	return arg.DataType
+}
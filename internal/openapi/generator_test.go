@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"oracle-golang/internal/datasource"
+	"oracle-golang/internal/model/response"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeService map[string]*response.GetProcedureInfoResponse
+
+func (f fakeService) GetProcedureInfo(ctx context.Context, name string) (*response.GetProcedureInfoResponse, error) {
+	info, ok := f[datasource.NameFrom(ctx)+":"+name]
+	if !ok {
+		return nil, errors.New("ORA-01031: insufficient privileges")
+	}
+	return info, nil
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestGenerator_Document(t *testing.T) {
+	service := fakeService{
+		":orders_api.get_orders": {
+			Name:        "orders_api.get_orders",
+			Target:      response.ProcedureTarget{Owner: "APP", Package: "ORDERS_PKG", Name: "GET_ORDERS"},
+			Description: "Returns the open orders of a customer.",
+			Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+				{Name: "P_CUSTOMER_ID", DataType: "NUMBER", InOut: "IN", DataPrecision: intPtr(10), DataScale: intPtr(0)},
+				{Name: "P_STATUS", DataType: "VARCHAR2", InOut: "IN", DataLength: intPtr(20), Defaulted: true},
+				{Name: "P_ORDERS", DataType: "REF CURSOR", InOut: "OUT"},
+			}}},
+		},
+		":pay": {
+			Name:   "pay",
+			Target: response.ProcedureTarget{Owner: "APP", Name: "PAY"},
+			Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+				{Name: "P_LINES", DataType: "TABLE", InOut: "IN", TypeOwner: "APP", TypeName: "LINE_TAB", Fields: []response.ProcedureArgument{
+					{DataType: "OBJECT", TypeOwner: "APP", TypeName: "LINE_T", Fields: []response.ProcedureArgument{
+						{Name: "AMOUNT", DataType: "NUMBER"},
+						{Name: "BOOKED_AT", DataType: "DATE"},
+					}},
+				}},
+				{Name: "P_BALANCE", DataType: "NUMBER", InOut: "IN/OUT"},
+			}}},
+		},
+		":next_id": {
+			Name:      "next_id",
+			Target:    response.ProcedureTarget{Owner: "APP", Name: "NEXT_ID"},
+			Overloads: []response.ProcedureOverload{{Return: &response.ProcedureArgument{DataType: "NUMBER"}}},
+		},
+		":dropped": {Name: "dropped", Target: response.ProcedureTarget{Name: "DROPPED"}, Overloads: []response.ProcedureOverload{}},
+	}
+	g := NewGenerator(service, map[string][]Procedure{
+		"core": {
+			{Name: "dropped"},
+			{Name: "forbidden"},
+			{Name: "next_id"},
+			{Name: "orders_api.get_orders", ReadOnly: true},
+			{Name: "pay", ReadOnly: true},
+		},
+	}, "core")
+
+	doc, err := g.Document(context.Background(), "/api/v1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Equal(t, []Server{{URL: "/api/v1"}}, doc.Servers)
+	// Unknown procedures, functions and procedures without metadata are left out.
+	require.Len(t, doc.Paths, 2)
+
+	orders := doc.Paths["/p/app/orders_pkg/get_orders"]
+	require.NotNil(t, orders)
+	assert.Equal(t, "orders_api.get_orders", orders.Post.Summary)
+	assert.Equal(t, "Returns the open orders of a customer.", orders.Post.Description)
+	assert.Equal(t, []string{"orders_pkg"}, orders.Post.Tags)
+
+	input := orders.Post.RequestBody.Content[jsonContent].Schema
+	assert.Equal(t, "integer", input.Properties["p_customer_id"].Type)
+	assert.Equal(t, "string", input.Properties["p_status"].Type)
+	assert.Equal(t, intPtr(20), input.Properties["p_status"].MaxLength)
+	assert.Equal(t, []string{"p_customer_id"}, input.Required)
+	assert.NotContains(t, input.Properties, "p_orders")
+
+	output := orders.Post.Responses["200"].Content[jsonContent].Schema.Properties["data"]
+	assert.Equal(t, "array", output.Properties["p_orders"].Type)
+	assert.Equal(t, "object", output.Properties["p_orders"].Items.Type)
+	assert.NotContains(t, output.Properties, "p_customer_id")
+	assert.Equal(t, errorRef, orders.Post.Responses["default"].Ref)
+
+	require.NotNil(t, orders.Get)
+	assert.Equal(t, []Parameter{
+		{Name: "p_customer_id", In: "query", Required: true, Schema: input.Properties["p_customer_id"]},
+		{Name: "p_status", In: "query", Schema: input.Properties["p_status"]},
+	}, orders.Get.Parameters)
+
+	pay := doc.Paths["/p/app/pay"]
+	require.NotNil(t, pay)
+	lines := pay.Post.RequestBody.Content[jsonContent].Schema.Properties["p_lines"]
+	assert.Equal(t, "array", lines.Type)
+	assert.Equal(t, "APP.LINE_TAB", lines.Description)
+	assert.Equal(t, "number", lines.Items.Properties["amount"].Type)
+	assert.Equal(t, "date-time", lines.Items.Properties["booked_at"].Format)
+	balance := pay.Post.Responses["200"].Content[jsonContent].Schema.Properties["data"].Properties["p_balance"]
+	assert.Equal(t, "number", balance.Type)
+	assert.True(t, balance.Nullable)
+	// Collections cannot be passed in the query string.
+	assert.Nil(t, pay.Get)
+}
+
+func TestGenerator_Datasources(t *testing.T) {
+	service := fakeService{
+		"billing:close_day": {
+			Name:      "close_day",
+			Target:    response.ProcedureTarget{Owner: "BILLING", Name: "CLOSE_DAY"},
+			Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{}}},
+		},
+	}
+	g := NewGenerator(service, map[string][]Procedure{
+		"core":    {},
+		"billing": {{Name: "close_day"}},
+	}, "core")
+
+	doc, err := g.Document(datasource.WithName(context.Background(), "billing"), "/api/v1/billing")
+	require.NoError(t, err)
+	assert.Contains(t, doc.Paths, "/p/billing/close_day")
+	assert.Nil(t, doc.Paths["/p/billing/close_day"].Post.RequestBody)
+
+	doc, err = g.Document(context.Background(), "/api/v1")
+	require.NoError(t, err)
+	assert.Empty(t, doc.Paths)
+
+	_, err = g.Document(datasource.WithName(context.Background(), "crm"), "/api/v1/crm")
+	var unknown *datasource.UnknownError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, http.StatusNotFound, unknown.StatusCode())
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Rule allows Rate requests per second on average with bursts of up to Burst requests.
+// A zero Rate means no limit.
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+func (r Rule) enabled() bool {
+	return r.Rate > 0
+}
+
+type bucket struct {
+	rule   Rule
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rule Rule, now time.Time) *bucket {
+	if rule.Burst < 1 {
+		rule.Burst = 1
+	}
+	return &bucket{rule: rule, tokens: float64(rule.Burst), last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.rule.Burst), b.tokens+elapsed*b.rule.Rate)
+		b.last = now
+	}
+}
+
+// wait returns how long until a token is available, or zero if one is available now.
+func (b *bucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rule.Rate * float64(time.Second))
+}
+
+func (b *bucket) take() {
+	b.tokens--
+}
+
+func (b *bucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= float64(b.rule.Burst)
+}
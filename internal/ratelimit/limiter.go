@@ -0,0 +1,205 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// concurrencyRetryAfter is suggested to clients rejected by a concurrency cap, since there is
+// no way to know when a running call will finish.
+const concurrencyRetryAfter = time.Second
+
+const sweepInterval = time.Minute
+
+type ProcedureRule struct {
+	Rule          Rule
+	MaxConcurrent int
+}
+
+type Config struct {
+	// PerPrincipal applies to every principal without an entry in Principals.
+	PerPrincipal Rule
+	Principals   map[string]Rule
+	Procedures   map[string]ProcedureRule
+}
+
+// LimitError is returned when a call is rejected. The handler maps it to 429 with a
+// Retry-After header.
+type LimitError struct {
+	Reason string
+	Wait   time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded: %s", e.Reason)
+}
+
+func (e *LimitError) StatusCode() int {
+	return http.StatusTooManyRequests
+}
+
+func (e *LimitError) RetryAfter() time.Duration {
+	return e.Wait
+}
+
+type procedureState struct {
+	bucket  *bucket
+	max     int
+	running int
+}
+
+// principals holds the principal buckets, which every Limiter scoped from the same one
+// shares.
+type principals struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// Limiter enforces token bucket limits per principal and per procedure, and caps the number
+// of concurrent calls per procedure. State is kept in memory, so each instance limits alone.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	shared     *principals
+	procedures map[string]*procedureState
+}
+
+func New(cfg Config) *Limiter {
+	cfg.Procedures = normalizeRules(cfg.Procedures)
+	return &Limiter{
+		cfg:        cfg,
+		now:        time.Now,
+		shared:     &principals{buckets: make(map[string]*bucket)},
+		procedures: make(map[string]*procedureState),
+	}
+}
+
+// Scoped returns a Limiter with its own procedure rules that shares the principal limits
+// of l, for data sources whose procedures are limited apart while a client's requests
+// are counted once across all of them.
+func (l *Limiter) Scoped(procedures map[string]ProcedureRule) *Limiter {
+	cfg := l.cfg
+	cfg.Procedures = normalizeRules(procedures)
+	return &Limiter{
+		cfg:        cfg,
+		now:        l.now,
+		shared:     l.shared,
+		procedures: make(map[string]*procedureState),
+	}
+}
+
+func normalizeRules(rules map[string]ProcedureRule) map[string]ProcedureRule {
+	result := make(map[string]ProcedureRule, len(rules))
+	for name, rule := range rules {
+		result[normalize(name)] = rule
+	}
+	return result
+}
+
+// Acquire admits a call or returns a *LimitError. The caller must call release when the
+// call finishes. An empty principal, as used by the scheduler, skips the principal limit.
+func (l *Limiter) Acquire(principal, procedure string) (release func(), err error) {
+	l.shared.mu.Lock()
+	defer l.shared.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	principalBucket := l.principalBucket(principal, now)
+	state := l.procedureState(normalize(procedure), now)
+
+	if principalBucket != nil {
+		if wait := principalBucket.wait(now); wait > 0 {
+			return nil, &LimitError{Reason: "too many requests from " + principal, Wait: wait}
+		}
+	}
+	if state != nil && state.bucket != nil {
+		if wait := state.bucket.wait(now); wait > 0 {
+			return nil, &LimitError{Reason: "too many calls to " + procedure, Wait: wait}
+		}
+	}
+	if state != nil && state.max > 0 && state.running >= state.max {
+		return nil, &LimitError{Reason: "too many concurrent calls to " + procedure, Wait: concurrencyRetryAfter}
+	}
+
+	if principalBucket != nil {
+		principalBucket.take()
+	}
+	if state == nil {
+		return func() {}, nil
+	}
+	if state.bucket != nil {
+		state.bucket.take()
+	}
+	state.running++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.shared.mu.Lock()
+			defer l.shared.mu.Unlock()
+			state.running--
+		})
+	}, nil
+}
+
+func (l *Limiter) principalBucket(principal string, now time.Time) *bucket {
+	if principal == "" {
+		return nil
+	}
+	if b, ok := l.shared.buckets[principal]; ok {
+		return b
+	}
+
+	rule, ok := l.cfg.Principals[principal]
+	if !ok {
+		rule = l.cfg.PerPrincipal
+	}
+	if !rule.enabled() {
+		return nil
+	}
+	b := newBucket(rule, now)
+	l.shared.buckets[principal] = b
+	return b
+}
+
+func (l *Limiter) procedureState(procedure string, now time.Time) *procedureState {
+	if state, ok := l.procedures[procedure]; ok {
+		return state
+	}
+
+	rule, ok := l.cfg.Procedures[procedure]
+	if !ok {
+		return nil
+	}
+	state := &procedureState{max: rule.MaxConcurrent}
+	if rule.Rule.enabled() {
+		state.bucket = newBucket(rule.Rule, now)
+	}
+	l.procedures[procedure] = state
+	return state
+}
+
+// sweep forgets principals whose bucket has refilled, so that the map does not grow with
+// every client address ever seen.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.shared.lastSweep) < sweepInterval {
+		return
+	}
+	l.shared.lastSweep = now
+
+	for principal, b := range l.shared.buckets {
+		if b.full(now) {
+			delete(l.shared.buckets, principal)
+		}
+	}
+}
+
+func normalize(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
@@ -0,0 +1,251 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/cache"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(cfg Config) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	l := New(cfg)
+	l.now = clock.Now
+	return l, clock
+}
+
+func TestLimiter_PerPrincipal(t *testing.T) {
+	l, clock := newTestLimiter(Config{
+		PerPrincipal: Rule{Rate: 2, Burst: 2},
+		Principals:   map[string]Rule{"batch": {Rate: 100, Burst: 100}},
+	})
+
+	for i := 0; i < 2; i++ {
+		release, err := l.Acquire("alice", "pkg.proc")
+		require.NoError(t, err)
+		release()
+	}
+
+	_, err := l.Acquire("alice", "pkg.proc")
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 500*time.Millisecond, limitErr.RetryAfter())
+
+	// Other principals have their own bucket, and overrides replace the default rule.
+	_, err = l.Acquire("bob", "pkg.proc")
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		_, err = l.Acquire("batch", "pkg.proc")
+		assert.NoError(t, err)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	_, err = l.Acquire("alice", "pkg.proc")
+	assert.NoError(t, err)
+}
+
+func TestLimiter_PerProcedure(t *testing.T) {
+	l, clock := newTestLimiter(Config{
+		Procedures: map[string]ProcedureRule{"PKG.REPORT": {Rule: Rule{Rate: 0.1, Burst: 1}}},
+	})
+
+	_, err := l.Acquire("alice", "pkg.report")
+	require.NoError(t, err)
+
+	_, err = l.Acquire("bob", "pkg.report")
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 10*time.Second, limitErr.RetryAfter())
+
+	_, err = l.Acquire("bob", "pkg.other")
+	assert.NoError(t, err, "procedures without a rule are not limited")
+
+	clock.Advance(10 * time.Second)
+	_, err = l.Acquire("bob", "pkg.report")
+	assert.NoError(t, err)
+}
+
+func TestLimiter_MaxConcurrent(t *testing.T) {
+	l, _ := newTestLimiter(Config{
+		Procedures: map[string]ProcedureRule{"pkg.slow": {MaxConcurrent: 2}},
+	})
+
+	first, err := l.Acquire("alice", "pkg.slow")
+	require.NoError(t, err)
+	_, err = l.Acquire("bob", "pkg.slow")
+	require.NoError(t, err)
+
+	_, err = l.Acquire("carol", "pkg.slow")
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, concurrencyRetryAfter, limitErr.RetryAfter())
+
+	first()
+	first() // releasing twice must not free a second slot
+	_, err = l.Acquire("carol", "pkg.slow")
+	assert.NoError(t, err)
+	_, err = l.Acquire("dave", "pkg.slow")
+	assert.Error(t, err)
+}
+
+func TestLimiter_RejectionDoesNotConsumeTokens(t *testing.T) {
+	l, _ := newTestLimiter(Config{
+		PerPrincipal: Rule{Rate: 1, Burst: 1},
+		Procedures:   map[string]ProcedureRule{"pkg.slow": {MaxConcurrent: 1}},
+	})
+
+	_, err := l.Acquire("bob", "pkg.slow")
+	require.NoError(t, err)
+
+	// alice is rejected by the concurrency cap, so her token is still there.
+	_, err = l.Acquire("alice", "pkg.slow")
+	require.Error(t, err)
+	_, err = l.Acquire("alice", "pkg.other")
+	assert.NoError(t, err)
+}
+
+func TestLimiter_EmptyPrincipal(t *testing.T) {
+	l, _ := newTestLimiter(Config{PerPrincipal: Rule{Rate: 1, Burst: 1}})
+
+	for i := 0; i < 5; i++ {
+		_, err := l.Acquire("", "pkg.proc")
+		assert.NoError(t, err)
+	}
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	l, clock := newTestLimiter(Config{PerPrincipal: Rule{Rate: 1, Burst: 1}})
+
+	_, err := l.Acquire("10.0.0.1", "pkg.proc")
+	require.NoError(t, err)
+	require.Len(t, l.shared.buckets, 1)
+
+	clock.Advance(sweepInterval)
+	_, err = l.Acquire("10.0.0.2", "pkg.proc")
+	require.NoError(t, err)
+
+	assert.NotContains(t, l.shared.buckets, "10.0.0.1")
+	assert.Contains(t, l.shared.buckets, "10.0.0.2")
+}
+
+func TestLimiter_Scoped(t *testing.T) {
+	core, _ := newTestLimiter(Config{
+		PerPrincipal: Rule{Rate: 0.1, Burst: 2},
+		Procedures:   map[string]ProcedureRule{"pkg.slow": {MaxConcurrent: 1}},
+	})
+	billing := core.Scoped(map[string]ProcedureRule{"pkg.report": {MaxConcurrent: 1}})
+
+	// Each data source caps its own procedures.
+	_, err := core.Acquire("", "pkg.slow")
+	require.NoError(t, err)
+	_, err = billing.Acquire("", "pkg.slow")
+	require.NoError(t, err)
+	_, err = billing.Acquire("", "pkg.report")
+	require.NoError(t, err)
+	_, err = billing.Acquire("", "pkg.report")
+	assert.Error(t, err)
+
+	// A principal's requests count against one budget across data sources.
+	_, err = core.Acquire("alice", "pkg.other")
+	require.NoError(t, err)
+	_, err = billing.Acquire("alice", "pkg.other")
+	require.NoError(t, err)
+	_, err = core.Acquire("alice", "pkg.other")
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, "too many requests from alice", limitErr.Reason)
+}
+
+type fakeRepository struct {
+	calls int
+}
+
+func (f *fakeRepository) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	f.calls++
+	return map[string]any{}, nil
+}
+
+func (f *fakeRepository) GetProcedureInfo(context.Context, string) (*response.GetProcedureInfoResponse, error) {
+	return nil, nil
+}
+
+func (f *fakeRepository) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{}, nil
+}
+
+func TestRepository_CallProcedure(t *testing.T) {
+	next := &fakeRepository{}
+	l, _ := newTestLimiter(Config{PerPrincipal: Rule{Rate: 1, Burst: 1}})
+	repo := NewRepository(next, l, newNames())
+	ctx := auth.WithPrincipal(context.Background(), "alice")
+
+	_, err := repo.CallProcedure(ctx, "pkg.proc", nil)
+	require.NoError(t, err)
+
+	_, err = repo.CallProcedure(ctx, "pkg.proc", nil)
+	var limitErr *LimitError
+	assert.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestRepository_CallProcedureThroughSynonym(t *testing.T) {
+	next := &fakeRepository{}
+	l, _ := newTestLimiter(Config{Procedures: map[string]ProcedureRule{"pkg.proc": {Rule: Rule{Rate: 1, Burst: 1}}}})
+	repo := NewRepository(next, l, newNames("pkg.proc"))
+
+	_, err := repo.CallProcedure(context.Background(), "pkg.proc", nil)
+	require.NoError(t, err)
+
+	// A synonym of the procedure counts against the same limit.
+	for _, name := range []string{"proc", "app.pkg.proc"} {
+		_, err = repo.CallProcedure(context.Background(), name, nil)
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr), name)
+	}
+	assert.Equal(t, 1, next.calls)
+}
+
+// synonyms resolves PROC and the procedure it stands for to the same target, as the data
+// dictionary would.
+type synonyms struct{}
+
+func (synonyms) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	return nil, nil
+}
+
+func (synonyms) GetProcedureInfo(_ context.Context, name string) (*response.GetProcedureInfoResponse, error) {
+	target := response.ProcedureTarget{Owner: "APP", Package: "PKG", Name: strings.ToUpper(name[strings.LastIndex(name, ".")+1:])}
+	if strings.EqualFold(name, "proc") {
+		target.Name = "PROC"
+	}
+	return &response.GetProcedureInfoResponse{Name: name, Target: target, Overloads: []response.ProcedureOverload{{}}}, nil
+}
+
+func (synonyms) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{}, nil
+}
+
+func newNames(names ...string) *service.Names {
+	return service.NewNames(service.NewNameResolver(synonyms{}, cache.NewLRU[string](100, 0), time.Minute), names)
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/service"
+)
+
+// Repository applies a Limiter to every procedure call made through a service.Repository.
+// Calls are limited under the procedure of the rules they resolve to, so that a synonym or
+// another spelling of a limited procedure shares its limits.
+type Repository struct {
+	next    service.Repository
+	limiter *Limiter
+	limited *service.Names
+}
+
+func NewRepository(next service.Repository, limiter *Limiter, limited *service.Names) *Repository {
+	return &Repository{next: next, limiter: limiter, limited: limited}
+}
+
+func (r *Repository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	procedure := name
+	if listed, ok := r.limited.Match(ctx, name); ok {
+		procedure = listed
+	}
+	release, err := r.limiter.Acquire(auth.PrincipalFrom(ctx), procedure)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return r.next.CallProcedure(ctx, name, params)
+}
+
+func (r *Repository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	return r.next.GetProcedureInfo(ctx, procedureName)
+}
+
+func (r *Repository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return r.next.ListProcedures(ctx, filter)
+}
@@ -0,0 +1,144 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"oracle-golang/internal/model/request"
+	"regexp"
+	"strings"
+)
+
+type Mode string
+
+const (
+	ModeMask Mode = "mask"
+	ModeHash Mode = "hash"
+
+	Mask = "***"
+)
+
+// DefaultPatterns match parameter names that commonly hold secrets or personal data.
+var DefaultPatterns = []string{
+	`(?i)pass(word|wd)?`,
+	`(?i)secret`,
+	`(?i)token`,
+	`(?i)(^|_)pin($|_)`,
+	`(?i)national_?id|(^|_)iin($|_)|ssn|passport`,
+	`(?i)card_?(no|num|number)|(^|_)pan($|_)|cvv`,
+}
+
+type Config struct {
+	Mode     Mode
+	Patterns []string
+	// HashKey keys the HMAC used in hash mode, so that short values such as national IDs
+	// cannot be recovered by hashing every candidate.
+	HashKey string
+	// Procedures lists additional sensitive parameter names per procedure.
+	Procedures map[string][]string
+}
+
+// Policy decides which parameter values are sensitive and how they are written to logs,
+// traces and audit records.
+type Policy struct {
+	mode       Mode
+	patterns   []*regexp.Regexp
+	hashKey    []byte
+	procedures map[string]map[string]bool
+}
+
+func New(cfg Config) (*Policy, error) {
+	p := &Policy{
+		mode:       cfg.Mode,
+		hashKey:    []byte(cfg.HashKey),
+		procedures: make(map[string]map[string]bool, len(cfg.Procedures)),
+	}
+
+	switch p.mode {
+	case "":
+		p.mode = ModeMask
+	case ModeMask, ModeHash:
+	default:
+		return nil, fmt.Errorf("unknown redaction mode: %s", cfg.Mode)
+	}
+
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		p.patterns = append(p.patterns, re)
+	}
+
+	for procedure, params := range cfg.Procedures {
+		names := make(map[string]bool, len(params))
+		for _, param := range params {
+			names[normalize(param)] = true
+		}
+		p.procedures[normalize(procedure)] = names
+	}
+
+	return p, nil
+}
+
+// Default masks parameters matching DefaultPatterns.
+func Default() *Policy {
+	p, err := New(Config{Mode: ModeMask, Patterns: DefaultPatterns})
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Policy) IsSensitive(procedure, param string) bool {
+	if p.procedures[normalize(procedure)][normalize(param)] {
+		return true
+	}
+	for _, re := range p.patterns {
+		if re.MatchString(param) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns v unchanged, masked or hashed depending on the policy.
+func (p *Policy) Value(procedure, param string, v any) any {
+	if v == nil || !p.IsSensitive(procedure, param) {
+		return v
+	}
+	if p.mode == ModeHash {
+		mac := hmac.New(sha256.New, p.hashKey)
+		_, _ = fmt.Fprint(mac, v)
+		return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+	}
+	return Mask
+}
+
+// Params returns a copy of params with sensitive values redacted.
+func (p *Policy) Params(procedure string, params []request.ProcedureParam) []request.ProcedureParam {
+	result := make([]request.ProcedureParam, len(params))
+	for i, param := range params {
+		param.Value = p.Value(procedure, param.Name, param.Value)
+		result[i] = param
+	}
+	return result
+}
+
+// Values redacts a map of parameter values such as procedure output.
+func (p *Policy) Values(procedure string, values map[string]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+
+	result := make(map[string]any, len(values))
+	for name, v := range values {
+		result[name] = p.Value(procedure, name, v)
+	}
+	return result
+}
+
+func normalize(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
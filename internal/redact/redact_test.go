@@ -0,0 +1,116 @@
+package redact
+
+import (
+	"oracle-golang/internal/model/request"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Value(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		procedure string
+		param     string
+		value     any
+		expected  any
+	}{
+		{
+			name:      "default pattern is masked",
+			cfg:       Config{Patterns: DefaultPatterns},
+			procedure: "pkg.login",
+			param:     "p_password",
+			value:     "hunter2",
+			expected:  Mask,
+		},
+		{
+			name:      "ordinary parameter is kept",
+			cfg:       Config{Patterns: DefaultPatterns},
+			procedure: "pkg.login",
+			param:     "p_user",
+			value:     "alice",
+			expected:  "alice",
+		},
+		{
+			name:      "pin pattern does not match spinner",
+			cfg:       Config{Patterns: DefaultPatterns},
+			procedure: "pkg.proc",
+			param:     "p_spinner",
+			value:     1,
+			expected:  1,
+		},
+		{
+			name:      "per-procedure parameter is matched case-insensitively",
+			cfg:       Config{Procedures: map[string][]string{"PKG.CUSTOMER": {"p_birth_date"}}},
+			procedure: "pkg.customer",
+			param:     "P_BIRTH_DATE",
+			value:     "1990-01-01",
+			expected:  Mask,
+		},
+		{
+			name:      "per-procedure parameter does not leak to other procedures",
+			cfg:       Config{Procedures: map[string][]string{"pkg.customer": {"p_birth_date"}}},
+			procedure: "pkg.other",
+			param:     "p_birth_date",
+			value:     "1990-01-01",
+			expected:  "1990-01-01",
+		},
+		{
+			name:      "nil value is kept",
+			cfg:       Config{Patterns: DefaultPatterns},
+			procedure: "pkg.login",
+			param:     "p_token",
+			value:     nil,
+			expected:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.cfg)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, p.Value(tt.procedure, tt.param, tt.value))
+		})
+	}
+}
+
+func TestPolicy_HashMode(t *testing.T) {
+	p, err := New(Config{Mode: ModeHash, Patterns: DefaultPatterns, HashKey: "key"})
+	require.NoError(t, err)
+	other, err := New(Config{Mode: ModeHash, Patterns: DefaultPatterns, HashKey: "other"})
+	require.NoError(t, err)
+
+	hashed := p.Value("pkg.proc", "p_iin", "900101300123")
+
+	require.IsType(t, "", hashed)
+	assert.True(t, strings.HasPrefix(hashed.(string), "sha256:"))
+	assert.NotContains(t, hashed, "900101300123")
+	assert.Equal(t, hashed, p.Value("pkg.proc", "p_iin", "900101300123"), "hash must be stable")
+	assert.NotEqual(t, hashed, other.Value("pkg.proc", "p_iin", "900101300123"), "hash must depend on the key")
+}
+
+func TestPolicy_Params(t *testing.T) {
+	p := Default()
+	params := []request.ProcedureParam{
+		{Name: "p_user", Type: "VARCHAR2", Value: "alice", Direction: "IN"},
+		{Name: "p_secret", Type: "VARCHAR2", Value: "s3cr3t", Direction: "IN"},
+	}
+
+	result := p.Params("pkg.login", params)
+
+	assert.Equal(t, "alice", result[0].Value)
+	assert.Equal(t, Mask, result[1].Value)
+	assert.Equal(t, "s3cr3t", params[1].Value, "input must not be modified")
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(Config{Mode: "encrypt"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Patterns: []string{"("}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package replica
+
+import (
+	"context"
+	"net/http"
+)
+
+// Header tells clients which node served a procedure call. It is absent when no database
+// was called, for example on a cache hit.
+const Header = "X-Served-By"
+
+type contextKey struct{}
+
+type servedBy struct {
+	node string
+}
+
+func setNode(ctx context.Context, node string) {
+	if s, ok := ctx.Value(contextKey{}).(*servedBy); ok {
+		s.node = node
+	}
+}
+
+// Middleware sets Header on responses to calls that reached a Repository.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := &servedBy{}
+		ctx := context.WithValue(r.Context(), contextKey{}, s)
+		next.ServeHTTP(&nodeWriter{ResponseWriter: w, servedBy: s}, r.WithContext(ctx))
+	})
+}
+
+type nodeWriter struct {
+	http.ResponseWriter
+	servedBy    *servedBy
+	wroteHeader bool
+}
+
+func (w *nodeWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if w.servedBy.node != "" {
+			w.Header().Set(Header, w.servedBy.node)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *nodeWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *nodeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
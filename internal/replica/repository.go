@@ -0,0 +1,60 @@
+package replica
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/resilience"
+	"oracle-golang/internal/service"
+	"oracle-golang/pkg/util"
+)
+
+const (
+	NodePrimary = "primary"
+	NodeReplica = "replica"
+)
+
+// Repository sends the procedures the policy marks read-only to a standby and everything
+// else to the primary; they are not detected from metadata, but synonyms of them match.
+// When the standby is unreachable, or its circuit breaker is open, read-only calls fall back
+// to the primary. Dictionary lookups always use the primary.
+type Repository struct {
+	primary  service.Repository
+	replica  service.Repository
+	readOnly *service.Names
+}
+
+func NewRepository(primary, replica service.Repository, readOnly *service.Names) *Repository {
+	return &Repository{primary: primary, replica: replica, readOnly: readOnly}
+}
+
+func (r *Repository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	if _, ok := r.readOnly.Match(ctx, name); ok {
+		result, err := r.replica.CallProcedure(ctx, name, params)
+		if err == nil || !unhealthy(err) {
+			setNode(ctx, NodeReplica)
+			return result, err
+		}
+		slog.WarnContext(ctx, "replica unavailable, falling back to primary", "procedure", name, "error", err)
+	}
+
+	setNode(ctx, NodePrimary)
+	return r.primary.CallProcedure(ctx, name, params)
+}
+
+func (r *Repository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	setNode(ctx, NodePrimary)
+	return r.primary.GetProcedureInfo(ctx, procedureName)
+}
+
+func (r *Repository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	setNode(ctx, NodePrimary)
+	return r.primary.ListProcedures(ctx, filter)
+}
+
+func unhealthy(err error) bool {
+	var open *resilience.OpenError
+	return errors.As(err, &open) || util.IsConnectionError(err)
+}
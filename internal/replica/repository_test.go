@@ -0,0 +1,150 @@
+package replica
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/cache"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/resilience"
+	"oracle-golang/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	node  string
+	err   error
+	calls int
+}
+
+func (f *fakeRepository) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return map[string]any{"node": f.node}, nil
+}
+
+func (f *fakeRepository) GetProcedureInfo(context.Context, string) (*response.GetProcedureInfoResponse, error) {
+	f.calls++
+	return &response.GetProcedureInfoResponse{Name: f.node}, nil
+}
+
+func (f *fakeRepository) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	f.calls++
+	return response.ListProceduresResponse{Items: []response.ProcedureSummary{{Name: f.node}}}, nil
+}
+
+func TestRepository_CallProcedure(t *testing.T) {
+	procErr := errors.New("ORA-01403: no data found")
+
+	tests := []struct {
+		name        string
+		procedure   string
+		replicaErr  error
+		wantNode    string
+		wantErr     error
+		replicaHits int
+		primaryHits int
+	}{
+		{name: "read-only goes to replica", procedure: "pkg.get_report", wantNode: NodeReplica, replicaHits: 1},
+		{name: "names are case-insensitive", procedure: "PKG.GET_REPORT", wantNode: NodeReplica, replicaHits: 1},
+		{name: "synonyms of read-only procedures go to replica", procedure: "report", wantNode: NodeReplica, replicaHits: 1},
+		{name: "writes go to primary", procedure: "pkg.pay", wantNode: NodePrimary, primaryHits: 1},
+		{name: "connection error falls back", procedure: "pkg.get_report", replicaErr: driver.ErrBadConn, wantNode: NodePrimary, replicaHits: 1, primaryHits: 1},
+		{name: "open breaker falls back", procedure: "pkg.get_report", replicaErr: &resilience.OpenError{Wait: time.Second}, wantNode: NodePrimary, replicaHits: 1, primaryHits: 1},
+		{name: "procedure errors are returned", procedure: "pkg.get_report", replicaErr: procErr, wantNode: NodeReplica, wantErr: procErr, replicaHits: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeRepository{node: NodePrimary}
+			standby := &fakeRepository{node: NodeReplica, err: tt.replicaErr}
+			repo := NewRepository(primary, standby, newNames("pkg.get_report"))
+
+			served := &servedBy{}
+			ctx := context.WithValue(context.Background(), contextKey{}, served)
+			result, err := repo.CallProcedure(ctx, tt.procedure, nil)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantNode, result["node"])
+			}
+			assert.Equal(t, tt.wantNode, served.node)
+			assert.Equal(t, tt.replicaHits, standby.calls)
+			assert.Equal(t, tt.primaryHits, primary.calls)
+		})
+	}
+}
+
+func TestRepository_MetadataUsesPrimary(t *testing.T) {
+	primary := &fakeRepository{node: NodePrimary}
+	standby := &fakeRepository{node: NodeReplica}
+	repo := NewRepository(primary, standby, newNames("pkg.get_report"))
+
+	result, err := repo.GetProcedureInfo(context.Background(), "pkg.get_report")
+	require.NoError(t, err)
+	assert.Equal(t, NodePrimary, result.Name)
+
+	list, err := repo.ListProcedures(context.Background(), request.ListProceduresRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, NodePrimary, list.Items[0].Name)
+	assert.Zero(t, standby.calls)
+}
+
+func TestMiddleware(t *testing.T) {
+	primary := &fakeRepository{node: NodePrimary}
+	standby := &fakeRepository{node: NodeReplica}
+	repo := NewRepository(primary, standby, newNames("pkg.get_report"))
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name := r.URL.Query().Get("procedure"); name != "" {
+			_, _ = repo.CallProcedure(r.Context(), name, nil)
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, nil))
+		return w
+	}
+
+	assert.Equal(t, NodeReplica, serve("/call?procedure=pkg.get_report").Header().Get(Header))
+	assert.Equal(t, NodePrimary, serve("/call?procedure=pkg.pay").Header().Get(Header))
+	assert.Empty(t, serve("/call").Header().Get(Header))
+}
+
+// synonyms resolves REPORT and the procedure it stands for to the same target, as the data
+// dictionary would.
+type synonyms struct{}
+
+func (synonyms) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	return nil, nil
+}
+
+func (synonyms) GetProcedureInfo(_ context.Context, name string) (*response.GetProcedureInfoResponse, error) {
+	target := response.ProcedureTarget{Owner: "APP", Package: "PKG", Name: strings.ToUpper(name[strings.LastIndex(name, ".")+1:])}
+	if strings.EqualFold(name, "report") {
+		target.Name = "GET_REPORT"
+	}
+	return &response.GetProcedureInfoResponse{Name: name, Target: target, Overloads: []response.ProcedureOverload{{}}}, nil
+}
+
+func (synonyms) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{}, nil
+}
+
+func newNames(names ...string) *service.Names {
+	return service.NewNames(service.NewNameResolver(synonyms{}, cache.NewLRU[string](100, 0), time.Minute), names)
+}
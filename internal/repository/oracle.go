@@ -3,28 +3,117 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/redact"
+	"oracle-golang/internal/tracing"
+	"oracle-golang/pkg/util"
 	"strconv"
 	"strings"
 	"time"
 
 	goora "github.com/sijms/go-ora/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "oracle-golang/internal/repository"
+
 type OracleRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	redactor *redact.Policy
+	sessions Sessions
+	schema   func(context.Context) string
+	check    func(context.Context, response.ProcedureTarget) error
+}
+
+// Sessions picks the connection pool of the caller. A nil pool means the shared one.
+// release is called once the call is done with the pool.
+type Sessions interface {
+	DB(ctx context.Context) (db *sql.DB, release func(), err error)
+}
+
+type Option func(*OracleRepository)
+
+// WithRedactor sets the policy used to hide sensitive parameter values in logs.
+// Without it, redact.Default is used.
+func WithRedactor(p *redact.Policy) Option {
+	return func(r *OracleRepository) {
+		r.redactor = p
+	}
 }
 
-func NewOracleRepository(db *sql.DB) *OracleRepository {
-	return &OracleRepository{db: db}
+// WithSessions runs procedure calls in the caller's own database session. Metadata
+// lookups keep using the shared pool.
+func WithSessions(s Sessions) Option {
+	return func(r *OracleRepository) {
+		r.sessions = s
+	}
+}
+
+// WithCurrentSchema resolves unqualified names against the schema schema returns for the
+// call: calls run with CURRENT_SCHEMA set on their connection, and metadata lookups use it
+// as the owner. An empty schema keeps the connecting user's own.
+func WithCurrentSchema(schema func(context.Context) string) Option {
+	return func(r *OracleRepository) {
+		r.schema = schema
+	}
+}
+
+// WithTargetCheck refuses calls and metadata lookups whose name resolves to an object the
+// caller may not use, such as another tenant's procedure reached through a synonym.
+func WithTargetCheck(check func(ctx context.Context, target response.ProcedureTarget) error) Option {
+	return func(r *OracleRepository) {
+		r.check = check
+	}
+}
+
+func NewOracleRepository(db *sql.DB, opts ...Option) *OracleRepository {
+	r := &OracleRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.redactor == nil {
+		r.redactor = redact.Default()
+	}
+	return r
 }
 
 func (r *OracleRepository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
-	log.Printf("Calling procedure: %s with %d parameters", name, len(params))
-	for i, p := range params {
-		log.Printf("  Param[%d]: name=%s, type=%s, direction=%s, value=%v", i, p.Name, p.Type, p.Direction, p.Value)
+	ctx, span := startSpan(ctx, "OracleRepository.CallProcedure", name)
+	defer span.End()
+	span.SetAttributes(tracing.AttrParamCount.Int(len(params)))
+
+	result, err := r.callProcedure(ctx, name, params)
+	if err != nil {
+		endSpanWithError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(tracing.AttrRowCount.Int(countCursorRows(result)))
+	return result, nil
+}
+
+func (r *OracleRepository) callProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	slog.DebugContext(ctx, "calling procedure",
+		"procedure", name,
+		"params", r.redactor.Params(name, params),
+	)
+
+	// Names are written into the PL/SQL block below, so only identifiers may get there.
+	if err := request.ValidateName(name); err != nil {
+		return nil, &Error{Status: http.StatusBadRequest, Message: err.Error()}
+	}
+	for _, p := range params {
+		if err := request.ValidateParamName(p.Name); err != nil {
+			return nil, &Error{Status: http.StatusBadRequest, Message: err.Error()}
+		}
 	}
 
 	// Prepare named arguments for go-ora
@@ -72,8 +161,39 @@ func (r *OracleRepository) CallProcedure(ctx context.Context, name string, param
 		}
 	}
 
+	db, release, err := r.sessionDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var q querier = db
+	if schema := r.currentSchema(ctx); schema != "" {
+		conn, err := useSchema(ctx, db, schema)
+		if err != nil {
+			return nil, err
+		}
+		defer resetSchema(ctx, conn)
+		q = conn
+	}
+
+	// Call the object the name resolves to in this session, so synonyms mean the same as
+	// they do for GetProcedureInfo. The block only ever names the resolved object.
+	resolved, ok, err := resolveName(ctx, q, r.currentSchema(ctx), name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, &Error{Status: http.StatusNotFound, Message: fmt.Sprintf("procedure %s not found", name)}
+	}
+	if err := r.checkTarget(ctx, resolved); err != nil {
+		return nil, err
+	}
+	target := resolved.String()
+	slog.DebugContext(ctx, "resolved procedure", "procedure", name, "target", target)
+
 	// Construct the PL/SQL block with named parameters
-	query := fmt.Sprintf("BEGIN %s(", name)
+	query := fmt.Sprintf("BEGIN %s(", target)
 	for i, p := range params {
 		if i > 0 {
 			query += ", "
@@ -82,20 +202,99 @@ func (r *OracleRepository) CallProcedure(ctx context.Context, name string, param
 	}
 	query += "); END;"
 
-	log.Printf("Generated SQL: %s", query)
+	slog.DebugContext(ctx, "generated SQL", "query", query)
 
 	// Execute the procedure
-	_, err := r.db.ExecContext(ctx, query, args...)
+	_, err = q.ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("execution failed for procedure '%s': %w", name, err)
 	}
 
 	// Process output parameters
-	return r.processOutputParameters(params, outputParams)
+	return r.processOutputParameters(q, params, outputParams)
+}
+
+// querier is the part of *sql.DB and *sql.Conn used to run a call.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (r *OracleRepository) currentSchema(ctx context.Context) string {
+	if r.schema == nil {
+		return ""
+	}
+	return strings.ToUpper(r.schema(ctx))
+}
+
+func (r *OracleRepository) checkTarget(ctx context.Context, target response.ProcedureTarget) error {
+	if r.check == nil {
+		return nil
+	}
+	return r.check(ctx, target)
+}
+
+// useSchema takes a connection from db and sets its CURRENT_SCHEMA. The connection must be
+// given back with resetSchema so the schema does not leak to the next call.
+func useSchema(ctx context.Context, db *sql.DB, schema string) (*sql.Conn, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	// schema is a validated identifier; ALTER SESSION does not take bind variables.
+	if _, err := conn.ExecContext(ctx, "ALTER SESSION SET CURRENT_SCHEMA = "+schema); err != nil {
+		resetSchema(ctx, conn)
+		return nil, fmt.Errorf("failed to set current schema %s: %w", schema, err)
+	}
+	return conn, nil
+}
+
+// resetSchema restores the session user's schema and returns conn to the pool. A
+// connection that cannot be reset is discarded.
+func resetSchema(ctx context.Context, conn *sql.Conn) {
+	const reset = "BEGIN EXECUTE IMMEDIATE 'ALTER SESSION SET CURRENT_SCHEMA = ' || SYS_CONTEXT('USERENV', 'SESSION_USER'); END;"
+	if _, err := conn.ExecContext(context.WithoutCancel(ctx), reset); err != nil {
+		slog.WarnContext(ctx, "failed to reset current schema, discarding connection", "error", err)
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	if err := conn.Close(); err != nil {
+		slog.WarnContext(ctx, "failed to release connection", "error", err)
+	}
+}
+
+// sessionDB returns the caller's connection pool, falling back to the shared one, and the
+// function that releases it.
+func (r *OracleRepository) sessionDB(ctx context.Context) (*sql.DB, func(), error) {
+	if r.sessions == nil {
+		return r.db, func() {}, nil
+	}
+	db, release, err := r.sessions.DB(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if db == nil {
+		return r.db, release, nil
+	}
+	return db, release, nil
 }
 
 // GetProcedureInfo retrieves information about a stored procedure from Oracle's data dictionary
-func (r *OracleRepository) GetProcedureInfo(ctx context.Context, fullProcedureName string) ([]map[string]any, error) {
+func (r *OracleRepository) GetProcedureInfo(ctx context.Context, fullProcedureName string) (*response.GetProcedureInfoResponse, error) {
+	ctx, span := startSpan(ctx, "OracleRepository.GetProcedureInfo", fullProcedureName)
+	defer span.End()
+
+	result, err := r.getProcedureInfo(ctx, fullProcedureName)
+	if err != nil {
+		endSpanWithError(span, err)
+		return nil, err
+	}
+
+	span.SetAttributes(tracing.AttrRowCount.Int(len(result.Overloads)))
+	return result, nil
+}
+
+func (r *OracleRepository) getProcedureInfo(ctx context.Context, fullProcedureName string) (*response.GetProcedureInfoResponse, error) {
 	// Разделяем полное имя на пакет и процедуру
 	var owner, packageName, procedureName string
 
@@ -116,13 +315,41 @@ func (r *OracleRepository) GetProcedureInfo(ctx context.Context, fullProcedureNa
 		return nil, fmt.Errorf("invalid procedure name format: %s", fullProcedureName)
 	}
 
+	// Names that do not resolve are looked up as written, and come back without overloads.
+	schema := r.currentSchema(ctx)
+	target, ok, err := resolveName(ctx, r.db, schema, fullProcedureName)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		owner, packageName, procedureName = target.Owner, target.Package, target.Name
+	} else {
+		target = response.ProcedureTarget{Owner: owner, Package: packageName, Name: procedureName}
+		if owner == "" {
+			target.Owner = schema
+		}
+	}
+	if err := r.checkTarget(ctx, target); err != nil {
+		return nil, err
+	}
+
 	query := `
         SELECT 
             ARGUMENT_NAME,
             DATA_TYPE,
             IN_OUT,
             POSITION,
-            DEFAULT_VALUE
+            DATA_LEVEL,
+            OVERLOAD,
+            DEFAULT_VALUE,
+            DEFAULTED,
+            DATA_LENGTH,
+            DATA_PRECISION,
+            DATA_SCALE,
+            TYPE_OWNER,
+            TYPE_NAME,
+            TYPE_SUBNAME,
+            PLS_TYPE
         FROM ALL_ARGUMENTS
         WHERE OBJECT_NAME = :1
     `
@@ -131,15 +358,24 @@ func (r *OracleRepository) GetProcedureInfo(ctx context.Context, fullProcedureNa
 	if packageName != "" {
 		query += " AND PACKAGE_NAME = :2"
 		args = append(args, packageName)
+	} else {
+		// Without it, package subprograms of the same name would be read as overloads of
+		// the standalone procedure.
+		query += " AND PACKAGE_NAME IS NULL"
 	}
 	if owner != "" {
 		query += " AND OWNER = :3"
 		args = append(args, owner)
+	} else if schema != "" {
+		query += " AND OWNER = :3"
+		args = append(args, schema)
 	} else {
 		query += " AND OWNER = USER"
 	}
 
-	query += " ORDER BY POSITION"
+	// SEQUENCE lists each argument before the components of its type, which is the order
+	// the DATA_LEVEL tree is built in.
+	query += " ORDER BY TO_NUMBER(OVERLOAD), SEQUENCE"
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -147,24 +383,217 @@ func (r *OracleRepository) GetProcedureInfo(ctx context.Context, fullProcedureNa
 	}
 	defer rows.Close()
 
-	var result []map[string]any
+	result := &response.GetProcedureInfoResponse{Name: fullProcedureName, Target: target, Overloads: []response.ProcedureOverload{}}
+	var (
+		overload *response.ProcedureOverload
+		// parents[n] is the argument rows at DATA_LEVEL n+1 belong to.
+		parents []*response.ProcedureArgument
+	)
 	for rows.Next() {
-		var argName, dataType, inOut, defaultValue sql.NullString
-		var position sql.NullInt64
+		var argName, dataType, inOut, overloadNo, defaultValue, defaulted sql.NullString
+		var typeOwner, typeName, typeSubname, plsType sql.NullString
+		var position, level, length, precision, scale sql.NullInt64
 
-		err := rows.Scan(&argName, &dataType, &inOut, &position, &defaultValue)
+		err := rows.Scan(&argName, &dataType, &inOut, &position, &level, &overloadNo, &defaultValue, &defaulted,
+			&length, &precision, &scale, &typeOwner, &typeName, &typeSubname, &plsType)
 		if err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
 
-		row := map[string]any{
-			"argument_name": argName.String,
-			"data_type":     dataType.String,
-			"in_out":        inOut.String,
-			"position":      position.Int64,
-			"default_value": defaultValue.String,
+		if overload == nil || overload.Overload != overloadNo.String {
+			result.Overloads = append(result.Overloads, response.ProcedureOverload{Overload: overloadNo.String, Arguments: []response.ProcedureArgument{}})
+			overload = &result.Overloads[len(result.Overloads)-1]
+			parents = parents[:0]
+		}
+		// A subprogram without arguments still has a row, with no name or type.
+		if !argName.Valid && !dataType.Valid {
+			continue
+		}
+
+		arg := response.ProcedureArgument{
+			Name:          argName.String,
+			Position:      int(position.Int64),
+			DataType:      dataType.String,
+			InOut:         inOut.String,
+			DataLength:    nullInt(length),
+			DataPrecision: nullInt(precision),
+			DataScale:     nullInt(scale),
+			TypeOwner:     typeOwner.String,
+			TypeName:      typeName.String,
+			TypeSubname:   typeSubname.String,
+			PLSType:       plsType.String,
+			Defaulted:     defaulted.String == "Y",
+			DefaultValue:  defaultValue.String,
+		}
+
+		depth := int(level.Int64)
+		if depth > len(parents) {
+			return nil, fmt.Errorf("argument %s at DATA_LEVEL %d has no parent", arg.Name, depth)
+		}
+		parents = parents[:depth]
+
+		var added *response.ProcedureArgument
+		switch {
+		case depth > 0:
+			parent := parents[depth-1]
+			parent.Fields = append(parent.Fields, arg)
+			added = &parent.Fields[len(parent.Fields)-1]
+		case arg.Position == 0:
+			overload.Return = &arg
+			added = overload.Return
+		default:
+			overload.Arguments = append(overload.Arguments, arg)
+			added = &overload.Arguments[len(overload.Arguments)-1]
+		}
+		parents = append(parents, added)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read procedure info: %w", err)
+	}
+	if ok && len(result.Overloads) > 0 {
+		result.Description = r.describeProcedure(ctx, target)
+	}
+
+	return result, nil
+}
+
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
+
+// ListProcedures pages through the procedures, functions and packages visible to the
+// caller in ALL_PROCEDURES, or those of filter.Exposed when it is set. With sessions,
+// visibility is that of the caller's own user.
+func (r *OracleRepository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	ctx, span := startSpan(ctx, "OracleRepository.ListProcedures", filter.Name)
+	defer span.End()
+
+	if filter.Exposed != nil && len(filter.Exposed) == 0 {
+		return response.ListProceduresResponse{Items: []response.ProcedureSummary{}, Limit: filter.Limit, Offset: filter.Offset}, nil
+	}
+
+	result, err := r.listProcedures(ctx, filter)
+	if err != nil {
+		endSpanWithError(span, err)
+		return response.ListProceduresResponse{}, err
+	}
+
+	span.SetAttributes(tracing.AttrRowCount.Int(len(result.Items)))
+	return result, nil
+}
+
+func (r *OracleRepository) listProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	var args []any
+	bind := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf(":%d", len(args))
+	}
+
+	// Packages have one row for the package itself and one per overload of each subprogram.
+	query := `
+        SELECT
+            p.OWNER,
+            p.OBJECT_NAME,
+            p.PROCEDURE_NAME,
+            o.OBJECT_TYPE,
+            o.STATUS,
+            o.LAST_DDL_TIME,
+            COUNT(*),
+            CASE WHEN p.OWNER = USER THEN 1 ELSE 0 END
+        FROM ALL_PROCEDURES p
+        JOIN ALL_OBJECTS o
+            ON o.OWNER = p.OWNER AND o.OBJECT_NAME = p.OBJECT_NAME AND o.OBJECT_TYPE = p.OBJECT_TYPE
+        WHERE p.OBJECT_TYPE IN ('PROCEDURE', 'FUNCTION', 'PACKAGE')
+    `
+	if filter.Owner != "" {
+		query += " AND p.OWNER = " + bind(strings.ToUpper(filter.Owner))
+	}
+	if filter.Package != "" {
+		query += " AND p.OBJECT_TYPE = 'PACKAGE' AND p.OBJECT_NAME = " + bind(strings.ToUpper(filter.Package))
+	}
+	if filter.Name != "" {
+		query += " AND NVL(p.PROCEDURE_NAME, p.OBJECT_NAME) LIKE " + bind(filter.NamePattern()) + ` ESCAPE '\'`
+	}
+	if filter.Type != "" {
+		query += " AND p.OBJECT_TYPE = " + bind(strings.ToUpper(filter.Type))
+	}
+	if filter.Exposed != nil {
+		var subprograms, packages []string
+		for _, name := range filter.Exposed {
+			subprograms = append(subprograms, bind(name))
+			if i := strings.LastIndex(name, "."); strings.Count(name, ".") == 2 {
+				packages = append(packages, bind(name[:i]))
+			}
+		}
+		query += " AND (p.OWNER || '.' || p.OBJECT_NAME || NVL2(p.PROCEDURE_NAME, '.' || p.PROCEDURE_NAME, NULL) IN (" + strings.Join(subprograms, ", ") + ")"
+		if len(packages) > 0 {
+			query += " OR p.PROCEDURE_NAME IS NULL AND p.OWNER || '.' || p.OBJECT_NAME IN (" + strings.Join(packages, ", ") + ")"
+		}
+		query += ")"
+	}
+	query += `
+        GROUP BY p.OWNER, p.OBJECT_NAME, p.PROCEDURE_NAME, o.OBJECT_TYPE, o.STATUS, o.LAST_DDL_TIME
+        ORDER BY p.OWNER, p.OBJECT_NAME, p.PROCEDURE_NAME NULLS FIRST`
+	// One row more than the page tells whether there is a next one.
+	query += " OFFSET " + bind(filter.Offset) + " ROWS FETCH NEXT " + bind(filter.Limit+1) + " ROWS ONLY"
+
+	db, release, err := r.sessionDB(ctx)
+	if err != nil {
+		return response.ListProceduresResponse{}, err
+	}
+	defer release()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return response.ListProceduresResponse{}, fmt.Errorf("failed to query procedure catalog: %w", err)
+	}
+	defer rows.Close()
+
+	result := response.ListProceduresResponse{
+		Items:  []response.ProcedureSummary{},
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	for rows.Next() {
+		if len(result.Items) == filter.Limit {
+			next := filter.Offset + filter.Limit
+			result.NextOffset = &next
+			break
 		}
-		result = append(result, row)
+
+		var owner, objectName, procedureName, objectType, status sql.NullString
+		var lastDDL sql.NullTime
+		var overloads, own int
+		if err := rows.Scan(&owner, &objectName, &procedureName, &objectType, &status, &lastDDL, &overloads, &own); err != nil {
+			return response.ListProceduresResponse{}, fmt.Errorf("scan failed: %w", err)
+		}
+
+		item := response.ProcedureSummary{
+			Owner:       owner.String,
+			Name:        objectName.String,
+			ObjectType:  objectType.String,
+			Status:      status.String,
+			LastDDLTime: lastDDL.Time,
+		}
+		if procedureName.Valid {
+			item.Package = objectName.String
+			item.Name = procedureName.String
+			item.Overloads = overloads
+		}
+		item.FullName = item.Name
+		if item.Package != "" {
+			item.FullName = item.Package + "." + item.Name
+		}
+		if own == 0 {
+			item.FullName = item.Owner + "." + item.FullName
+		}
+		result.Items = append(result.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return response.ListProceduresResponse{}, fmt.Errorf("rows iteration error: %w", err)
 	}
 
 	return result, nil
@@ -257,7 +686,7 @@ func (r *OracleRepository) createOutputParameter(p request.ProcedureParam) goora
 }
 
 // processOutputParameters processes the output parameters and returns the result
-func (r *OracleRepository) processOutputParameters(params []request.ProcedureParam, outputParams map[string]interface{}) (map[string]any, error) {
+func (r *OracleRepository) processOutputParameters(q goora.Querier, params []request.ProcedureParam, outputParams map[string]interface{}) (map[string]any, error) {
 	result := make(map[string]any)
 
 	for _, p := range params {
@@ -275,7 +704,7 @@ func (r *OracleRepository) processOutputParameters(params []request.ProcedurePar
 			if cursorPtr, ok := dest.(*goora.RefCursor); ok && cursorPtr != nil {
 				if cursorPtr != nil {
 					// Convert RefCursor to sql.Rows
-					rows, err := goora.WrapRefCursor(context.Background(), r.db, cursorPtr)
+					rows, err := goora.WrapRefCursor(context.Background(), q, cursorPtr)
 					if err != nil {
 						return nil, fmt.Errorf("failed to wrap REF CURSOR for parameter %s: %w", p.Name, err)
 					}
@@ -331,7 +760,7 @@ func (r *OracleRepository) processOutputParameters(params []request.ProcedurePar
 func (r *OracleRepository) processRowsResult(rows *sql.Rows) ([]map[string]any, error) {
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			log.Printf("Warning: failed to close rows: %v", closeErr)
+			slog.Warn("failed to close rows", "error", closeErr)
 		}
 	}()
 
@@ -376,3 +805,32 @@ func (r *OracleRepository) processRowsResult(rows *sql.Rows) ([]map[string]any,
 
 	return allRows, nil
 }
+
+func startSpan(ctx context.Context, spanName, procedure string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "oracle"),
+			tracing.AttrProcedure.String(procedure),
+		),
+	)
+}
+
+func endSpanWithError(span trace.Span, err error) {
+	if code := util.OraErrorCode(err); code != "" {
+		span.SetAttributes(tracing.AttrErrorCode.String(code))
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// countCursorRows sums the rows returned through REF CURSOR parameters
+func countCursorRows(result map[string]any) int {
+	var total int
+	for _, v := range result {
+		if rows, ok := v.([]map[string]any); ok {
+			total += len(rows)
+		}
+	}
+	return total
+}
@@ -1,15 +1,28 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"log/slog"
 	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/redact"
+	"oracle-golang/internal/tracing"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestOracleRepository_CallProcedure(t *testing.T) {
@@ -30,7 +43,8 @@ func TestOracleRepository_CallProcedure(t *testing.T) {
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				// Based on logs, it uses named parameters (:param1, :param2)
-				mock.ExpectExec(`BEGIN test_procedure\(:param1, :param2\); END;`).
+				expectResolved(mock, "APP", "TEST_PROCEDURE", "PROCEDURE")
+				mock.ExpectExec(`BEGIN APP\.TEST_PROCEDURE\(:param1, :param2\); END;`).
 					WithArgs("value1", 123).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -42,7 +56,8 @@ func TestOracleRepository_CallProcedure(t *testing.T) {
 			procedureName: "simple_procedure",
 			params:        []request.ProcedureParam{},
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(`BEGIN simple_procedure\(\); END;`).
+				expectResolved(mock, "APP", "SIMPLE_PROCEDURE", "PROCEDURE")
+				mock.ExpectExec(`BEGIN APP\.SIMPLE_PROCEDURE\(\); END;`).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			expectedResult: map[string]any{},
@@ -53,7 +68,8 @@ func TestOracleRepository_CallProcedure(t *testing.T) {
 			procedureName: "error_procedure",
 			params:        []request.ProcedureParam{},
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(`BEGIN error_procedure\(\); END;`).
+				expectResolved(mock, "APP", "ERROR_PROCEDURE", "PROCEDURE")
+				mock.ExpectExec(`BEGIN APP\.ERROR_PROCEDURE\(\); END;`).
 					WillReturnError(errors.New("database connection error"))
 			},
 			expectedResult: nil,
@@ -81,7 +97,8 @@ func TestOracleRepository_CallProcedure(t *testing.T) {
 				{Name: "bool_param", Value: true, Type: "IN", Direction: "IN"},
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(`BEGIN mixed_params_procedure\(:str_param, :int_param, :float_param, :bool_param\); END;`).
+				expectResolved(mock, "APP", "MIXED_PARAMS_PROCEDURE", "PROCEDURE")
+				mock.ExpectExec(`BEGIN APP\.MIXED_PARAMS_PROCEDURE\(:str_param, :int_param, :float_param, :bool_param\); END;`).
 					WithArgs("test", 42, 3.14, true).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -125,67 +142,149 @@ func TestOracleRepository_CallProcedure(t *testing.T) {
 }
 
 func TestOracleRepository_GetProcedureInfo(t *testing.T) {
+	columns := []string{"ARGUMENT_NAME", "DATA_TYPE", "IN_OUT", "POSITION", "DATA_LEVEL", "OVERLOAD", "DEFAULT_VALUE", "DEFAULTED",
+		"DATA_LENGTH", "DATA_PRECISION", "DATA_SCALE", "TYPE_OWNER", "TYPE_NAME", "TYPE_SUBNAME", "PLS_TYPE"}
+	intPtr := func(n int) *int { return &n }
+
 	tests := []struct {
 		name           string
 		procedureName  string
 		setupMock      func(mock sqlmock.Sqlmock)
-		expectedResult []map[string]any
+		expectedResult *response.GetProcedureInfoResponse
 		expectedError  error
 	}{
 		{
 			name:          "successful procedure info retrieval",
 			procedureName: "test_procedure",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				// Based on actual output, columns are lowercase and include default_value
-				rows := sqlmock.NewRows([]string{"argument_name", "data_type", "in_out", "position", "default_value"}).
-					AddRow("param1", "VARCHAR2", "IN", 1, "100").
-					AddRow("param2", "NUMBER", "IN", 2, "").
-					AddRow("result", "VARCHAR2", "OUT", 3, "200")
-				mock.ExpectQuery(`SELECT.*FROM.*ALL_ARGUMENTS.*WHERE.*OBJECT_NAME.*`).
-					WithArgs("TEST_PROCEDURE").
+				rows := sqlmock.NewRows(columns).
+					AddRow("P_NAME", "VARCHAR2", "IN", 1, 0, nil, "'x'", "Y", 100, nil, nil, nil, nil, nil, "VARCHAR2").
+					AddRow("P_AMOUNT", "NUMBER", "IN", 2, 0, nil, nil, "N", 22, 10, 2, nil, nil, nil, "NUMBER").
+					AddRow("P_RESULT", "VARCHAR2", "OUT", 3, 0, nil, nil, "N", nil, nil, nil, nil, nil, nil, "VARCHAR2")
+				// Reached through a public synonym.
+				mock.ExpectQuery(`FROM ALL_SYNONYMS`).WithArgs("", "TEST_PROCEDURE", "", "TEST_PROCEDURE", "TEST_PROCEDURE", "Y").
+					WillReturnRows(sqlmock.NewRows(resolveColumns).AddRow("APP", "TEST_PROCEDURE", "SYNONYM", nil))
+				mock.ExpectQuery(`FROM ALL_SYNONYMS`).WithArgs("APP", "TEST_PROCEDURE", "APP", "TEST_PROCEDURE", "TEST_PROCEDURE", "N").
+					WillReturnRows(sqlmock.NewRows(resolveColumns).AddRow("APP", "TEST_PROCEDURE", "PROCEDURE", nil))
+				mock.ExpectQuery(`SELECT.*FROM.*ALL_ARGUMENTS.*WHERE.*OBJECT_NAME = :1 AND PACKAGE_NAME IS NULL AND OWNER = :3.*ORDER BY TO_NUMBER\(OVERLOAD\), SEQUENCE`).
+					WithArgs("TEST_PROCEDURE", "APP").
 					WillReturnRows(rows)
+				mock.ExpectQuery(`FROM ALL_SOURCE WHERE OWNER = :1 AND NAME = :2 AND TYPE IN \('PROCEDURE', 'FUNCTION'\)`).
+					WithArgs("APP", "TEST_PROCEDURE").
+					WillReturnRows(sqlmock.NewRows([]string{"TEXT"}).
+						AddRow("PROCEDURE test_procedure(p_name VARCHAR2 DEFAULT 'x', p_amount NUMBER, p_result OUT VARCHAR2) IS\n").
+						AddRow("  -- Records a payment.\n").
+						AddRow("BEGIN\n"))
 			},
-			expectedResult: []map[string]any{
-				{
-					"argument_name": "param1",
-					"data_type":     "VARCHAR2",
-					"in_out":        "IN",
-					"position":      int64(1),
-					"default_value": "100",
-				},
-				{
-					"argument_name": "param2",
-					"data_type":     "NUMBER",
-					"in_out":        "IN",
-					"position":      int64(2),
-					"default_value": "",
-				},
-				{
-					"argument_name": "result",
-					"data_type":     "VARCHAR2",
-					"in_out":        "OUT",
-					"position":      int64(3),
-					"default_value": "200",
+			expectedResult: &response.GetProcedureInfoResponse{
+				Name:        "test_procedure",
+				Target:      response.ProcedureTarget{Owner: "APP", Name: "TEST_PROCEDURE"},
+				Description: "Records a payment.",
+				Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+					{Name: "P_NAME", Position: 1, DataType: "VARCHAR2", InOut: "IN", DataLength: intPtr(100), PLSType: "VARCHAR2", Defaulted: true, DefaultValue: "'x'"},
+					{Name: "P_AMOUNT", Position: 2, DataType: "NUMBER", InOut: "IN", DataLength: intPtr(22), DataPrecision: intPtr(10), DataScale: intPtr(2), PLSType: "NUMBER"},
+					{Name: "P_RESULT", Position: 3, DataType: "VARCHAR2", InOut: "OUT", PLSType: "VARCHAR2"},
+				}}},
+			},
+		},
+		{
+			name:          "overloaded function returning a collection of records",
+			procedureName: "pkg.find_orders",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(nil, "TABLE", "OUT", 0, 0, "1", nil, "N", nil, nil, nil, "APP", "PKG", "ORDER_TAB", nil).
+					AddRow(nil, "PL/SQL RECORD", "OUT", 1, 1, "1", nil, "N", nil, nil, nil, "APP", "PKG", "ORDER_REC", nil).
+					AddRow("ID", "NUMBER", "OUT", 1, 2, "1", nil, "N", 22, nil, nil, nil, nil, nil, "NUMBER").
+					AddRow("STATUS", "VARCHAR2", "OUT", 2, 2, "1", nil, "N", 20, nil, nil, nil, nil, nil, "VARCHAR2").
+					AddRow("P_CUSTOMER_ID", "NUMBER", "IN", 1, 0, "1", nil, "N", 22, nil, nil, nil, nil, nil, "NUMBER").
+					AddRow(nil, "REF CURSOR", "OUT", 0, 0, "2", nil, "N", nil, nil, nil, nil, nil, nil, nil)
+				mock.ExpectQuery(`FROM ALL_SYNONYMS`).WithArgs("", "PKG", "", "PKG", "PKG", "Y").
+					WillReturnRows(sqlmock.NewRows(resolveColumns).AddRow("APP", "PKG", "PACKAGE", nil))
+				mock.ExpectQuery(`SELECT.*FROM.*ALL_ARGUMENTS.*WHERE.*OBJECT_NAME.*AND PACKAGE_NAME = :2`).
+					WithArgs("FIND_ORDERS", "PKG", "APP").
+					WillReturnRows(rows)
+				mock.ExpectQuery(`FROM ALL_SOURCE WHERE OWNER = :1 AND NAME = :2 AND TYPE = 'PACKAGE'`).
+					WithArgs("APP", "PKG").
+					WillReturnError(errors.New("ORA-00942: table or view does not exist"))
+			},
+			expectedResult: &response.GetProcedureInfoResponse{
+				Name:   "pkg.find_orders",
+				Target: response.ProcedureTarget{Owner: "APP", Package: "PKG", Name: "FIND_ORDERS"},
+				Overloads: []response.ProcedureOverload{
+					{
+						Overload: "1",
+						Return: &response.ProcedureArgument{
+							DataType: "TABLE", InOut: "OUT", TypeOwner: "APP", TypeName: "PKG", TypeSubname: "ORDER_TAB",
+							Fields: []response.ProcedureArgument{{
+								Position: 1, DataType: "PL/SQL RECORD", InOut: "OUT", TypeOwner: "APP", TypeName: "PKG", TypeSubname: "ORDER_REC",
+								Fields: []response.ProcedureArgument{
+									{Name: "ID", Position: 1, DataType: "NUMBER", InOut: "OUT", DataLength: intPtr(22), PLSType: "NUMBER"},
+									{Name: "STATUS", Position: 2, DataType: "VARCHAR2", InOut: "OUT", DataLength: intPtr(20), PLSType: "VARCHAR2"},
+								},
+							}},
+						},
+						Arguments: []response.ProcedureArgument{
+							{Name: "P_CUSTOMER_ID", Position: 1, DataType: "NUMBER", InOut: "IN", DataLength: intPtr(22), PLSType: "NUMBER"},
+						},
+					},
+					{
+						Overload:  "2",
+						Return:    &response.ProcedureArgument{DataType: "REF CURSOR", InOut: "OUT"},
+						Arguments: []response.ProcedureArgument{},
+					},
 				},
 			},
-			expectedError: nil,
+		},
+		{
+			name:          "procedure without arguments",
+			procedureName: "refresh_stats",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(nil, nil, "IN", 1, 0, nil, nil, "N", nil, nil, nil, nil, nil, nil, nil)
+				expectUnresolved(mock, 1)
+				mock.ExpectQuery(`SELECT.*FROM.*ALL_ARGUMENTS.*WHERE.*OBJECT_NAME.*`).
+					WithArgs("REFRESH_STATS").
+					WillReturnRows(rows)
+			},
+			expectedResult: &response.GetProcedureInfoResponse{
+				Name:      "refresh_stats",
+				Target:    response.ProcedureTarget{Name: "REFRESH_STATS"},
+				Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{}}},
+			},
 		},
 		{
 			name:          "procedure not found",
 			procedureName: "nonexistent_procedure",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"argument_name", "data_type", "in_out", "position", "default_value"})
+				expectUnresolved(mock, 1)
 				mock.ExpectQuery(`SELECT.*FROM.*ALL_ARGUMENTS.*WHERE.*OBJECT_NAME.*`).
 					WithArgs("NONEXISTENT_PROCEDURE").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectedResult: &response.GetProcedureInfoResponse{
+				Name:      "nonexistent_procedure",
+				Target:    response.ProcedureTarget{Name: "NONEXISTENT_PROCEDURE"},
+				Overloads: []response.ProcedureOverload{},
+			},
+		},
+		{
+			name:          "orphaned type component",
+			procedureName: "broken",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow("ID", "NUMBER", "IN", 1, 1, nil, nil, "N", 22, nil, nil, nil, nil, nil, "NUMBER")
+				expectUnresolved(mock, 1)
+				mock.ExpectQuery(`SELECT.*FROM.*ALL_ARGUMENTS.*WHERE.*OBJECT_NAME.*`).
+					WithArgs("BROKEN").
 					WillReturnRows(rows)
 			},
-			expectedResult: nil, // Change from []map[string]any{} to nil to match actual behavior
-			expectedError:  nil,
+			expectedError: errors.New("has no parent"),
 		},
 		{
 			name:          "database error during info retrieval",
 			procedureName: "error_procedure",
 			setupMock: func(mock sqlmock.Sqlmock) {
+				expectUnresolved(mock, 1)
 				mock.ExpectQuery(`SELECT.*FROM.*ALL_ARGUMENTS.*WHERE.*OBJECT_NAME.*`).
 					WithArgs("ERROR_PROCEDURE").
 					WillReturnError(errors.New("database connection error"))
@@ -218,12 +317,7 @@ func TestOracleRepository_GetProcedureInfo(t *testing.T) {
 				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
-				// Handle both nil and empty slice cases
-				if tt.expectedResult == nil {
-					assert.Nil(t, result)
-				} else {
-					assert.Equal(t, tt.expectedResult, result)
-				}
+				assert.Equal(t, tt.expectedResult, result)
 			}
 
 			// Ensure all expectations were met
@@ -270,7 +364,8 @@ func TestOracleRepository_DatabaseConnectionErrors(t *testing.T) {
 			defer db.Close()
 
 			// Setup mock to return specific error
-			mock.ExpectExec(`BEGIN test_procedure\(\); END;`).
+			expectResolved(mock, "APP", "TEST_PROCEDURE", "PROCEDURE")
+			mock.ExpectExec(`BEGIN APP\.TEST_PROCEDURE\(\); END;`).
 				WillReturnError(tt.dbError)
 
 			repo := NewOracleRepository(db)
@@ -344,12 +439,13 @@ func TestOracleRepository_ParameterHandling(t *testing.T) {
 			defer db.Close()
 
 			if !tt.expectErr {
+				expectResolved(mock, "APP", "TEST_PROCEDURE", "PROCEDURE")
 				// Setup mock expectation for valid cases
 				if len(tt.params) == 1 && tt.params[0].Direction == "OUT" {
 					// This will fail during execution due to go_ora.Out struct
 					// but that's expected behavior
 				} else {
-					mock.ExpectExec(`BEGIN test_procedure.*; END;`).
+					mock.ExpectExec(`BEGIN APP\.TEST_PROCEDURE.*; END;`).
 						WillReturnResult(sqlmock.NewResult(1, 1))
 				}
 			}
@@ -371,3 +467,286 @@ func TestOracleRepository_ParameterHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestOracleRepository_Tracing(t *testing.T) {
+	tests := []struct {
+		name              string
+		setupMock         func(mock sqlmock.Sqlmock)
+		expectedStatus    codes.Code
+		expectedErrorCode string
+	}{
+		{
+			name: "successful call",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectResolved(mock, "APP", "PKG", "PACKAGE")
+				mock.ExpectExec(`BEGIN APP\.PKG\.PROC\(:p_id\); END;`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedStatus: codes.Unset,
+		},
+		{
+			name: "oracle error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectResolved(mock, "APP", "PKG", "PACKAGE")
+				mock.ExpectExec(`BEGIN APP\.PKG\.PROC\(:p_id\); END;`).
+					WillReturnError(errors.New("ORA-06550: line 1, column 7"))
+			},
+			expectedStatus:    codes.Error,
+			expectedErrorCode: "ORA-06550",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			tt.setupMock(mock)
+
+			repo := NewOracleRepository(db)
+			_, _ = repo.CallProcedure(context.Background(), "pkg.proc", []request.ProcedureParam{
+				{Name: "p_id", Value: 1, Type: "NUMBER", Direction: "IN"},
+			})
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+
+			assert.Equal(t, "OracleRepository.CallProcedure", span.Name())
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+			assert.Equal(t, tt.expectedStatus, span.Status().Code)
+
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, kv := range span.Attributes() {
+				attrs[kv.Key] = kv.Value
+			}
+			assert.Equal(t, "pkg.proc", attrs[tracing.AttrProcedure].AsString())
+			assert.Equal(t, int64(1), attrs[tracing.AttrParamCount].AsInt64())
+			if tt.expectedErrorCode != "" {
+				assert.Equal(t, tt.expectedErrorCode, attrs[tracing.AttrErrorCode].AsString())
+			} else {
+				assert.Equal(t, int64(0), attrs[tracing.AttrRowCount].AsInt64())
+			}
+		})
+	}
+}
+
+func TestOracleRepository_RedactsLoggedParams(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	expectResolved(mock, "APP", "PKG", "PACKAGE")
+	mock.ExpectExec(`BEGIN APP\.PKG\.LOGIN\(:p_user, :p_password, :p_birth_date\); END;`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	redactor, err := redact.New(redact.Config{
+		Patterns:   redact.DefaultPatterns,
+		Procedures: map[string][]string{"pkg.login": {"p_birth_date"}},
+	})
+	require.NoError(t, err)
+
+	repo := NewOracleRepository(db, WithRedactor(redactor))
+	_, err = repo.CallProcedure(context.Background(), "pkg.login", []request.ProcedureParam{
+		{Name: "p_user", Value: "alice", Type: "VARCHAR2", Direction: "IN"},
+		{Name: "p_password", Value: "hunter2", Type: "VARCHAR2", Direction: "IN"},
+		{Name: "p_birth_date", Value: "1990-01-01", Type: "VARCHAR2", Direction: "IN"},
+	})
+	require.NoError(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, "alice")
+	assert.Contains(t, logs, redact.Mask)
+	assert.NotContains(t, logs, "hunter2")
+	assert.NotContains(t, logs, "1990-01-01")
+}
+
+type fakeSessions struct {
+	db  *sql.DB
+	err error
+}
+
+func (f fakeSessions) DB(context.Context) (*sql.DB, func(), error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return f.db, func() {}, nil
+}
+
+func TestOracleRepository_Sessions(t *testing.T) {
+	shared, sharedMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer shared.Close()
+	user, userMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer user.Close()
+
+	expectResolved(userMock, "APP", "PKG", "PACKAGE")
+	userMock.ExpectExec(`BEGIN APP\.PKG\.PAY\(\); END;`).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectResolved(sharedMock, "APP", "PKG", "PACKAGE")
+	sharedMock.ExpectExec(`BEGIN APP\.PKG\.PAY\(\); END;`).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectUnresolved(sharedMock, 2)
+	sharedMock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"ARGUMENT_NAME", "DATA_TYPE", "IN_OUT", "POSITION", "DEFAULT_VALUE"}))
+
+	_, err = NewOracleRepository(shared, WithSessions(fakeSessions{db: user})).CallProcedure(context.Background(), "pkg.pay", nil)
+	require.NoError(t, err)
+
+	// A nil pool falls back to the shared one.
+	_, err = NewOracleRepository(shared, WithSessions(fakeSessions{})).CallProcedure(context.Background(), "pkg.pay", nil)
+	require.NoError(t, err)
+
+	// Metadata always comes from the shared pool.
+	_, err = NewOracleRepository(shared, WithSessions(fakeSessions{db: user})).GetProcedureInfo(context.Background(), "pkg.pay")
+	require.NoError(t, err)
+
+	sessionErr := errors.New("no database credentials for principal mallory")
+	_, err = NewOracleRepository(shared, WithSessions(fakeSessions{err: sessionErr})).CallProcedure(context.Background(), "pkg.pay", nil)
+	assert.ErrorIs(t, err, sessionErr)
+
+	assert.NoError(t, sharedMock.ExpectationsWereMet())
+	assert.NoError(t, userMock.ExpectationsWereMet())
+}
+
+func TestOracleRepository_CurrentSchema(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	schemas := map[string]string{"acme": "acme", "globex": "GLOBEX"}
+	type tenantKey struct{}
+	repo := NewOracleRepository(db, WithCurrentSchema(func(ctx context.Context) string {
+		name, _ := ctx.Value(tenantKey{}).(string)
+		return schemas[name]
+	}))
+	reset := `BEGIN EXECUTE IMMEDIATE 'ALTER SESSION SET CURRENT_SCHEMA = ' \|\| SYS_CONTEXT\('USERENV', 'SESSION_USER'\); END;`
+
+	// Each tenant's call runs in its own schema, and the shared connection is reset
+	// in between so nothing carries over to the next caller.
+	for _, schema := range []string{"ACME", "GLOBEX"} {
+		mock.ExpectExec(`ALTER SESSION SET CURRENT_SCHEMA = ` + schema).WillReturnResult(sqlmock.NewResult(0, 0))
+		expectResolved(mock, schema, "PKG", "PACKAGE")
+		mock.ExpectExec(`BEGIN ` + schema + `\.PKG\.GET_ORDERS\(\); END;`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(reset).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	expectResolved(mock, "APP", "PKG", "PACKAGE")
+	mock.ExpectExec(`BEGIN APP\.PKG\.GET_ORDERS\(\); END;`).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectUnresolved(mock, 2)
+	mock.ExpectQuery(`AND OWNER = :3`).WithArgs("GET_ORDERS", "PKG", "GLOBEX").
+		WillReturnRows(sqlmock.NewRows([]string{"ARGUMENT_NAME", "DATA_TYPE", "IN_OUT", "POSITION", "DEFAULT_VALUE"}))
+
+	for _, name := range []string{"acme", "globex", ""} {
+		_, err := repo.CallProcedure(context.WithValue(context.Background(), tenantKey{}, name), "pkg.get_orders", nil)
+		require.NoError(t, err)
+	}
+	_, err = repo.GetProcedureInfo(context.WithValue(context.Background(), tenantKey{}, "globex"), "pkg.get_orders")
+	require.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOracleRepository_ListProcedures(t *testing.T) {
+	columns := []string{"OWNER", "OBJECT_NAME", "PROCEDURE_NAME", "OBJECT_TYPE", "STATUS", "LAST_DDL_TIME", "COUNT(*)", "OWN"}
+	ddl := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   request.ListProceduresRequest
+		args     []driver.Value
+		rows     [][]driver.Value
+		wantFull []string
+		wantNext *int
+	}{
+		{
+			name:   "filters",
+			filter: request.ListProceduresRequest{Owner: "app", Package: "pkg_orders", Name: "get_*", Type: "package", Limit: 2},
+			args:   []driver.Value{"APP", "PKG_ORDERS", `GET\_%`, "PACKAGE", int64(0), int64(3)},
+			rows: [][]driver.Value{
+				{"APP", "PKG_ORDERS", "GET_ORDER", "PACKAGE", "VALID", ddl, int64(2), int64(1)},
+				{"APP", "PKG_ORDERS", "GET_ORDERS", "PACKAGE", "INVALID", ddl, int64(1), int64(1)},
+			},
+			wantFull: []string{"PKG_ORDERS.GET_ORDER", "PKG_ORDERS.GET_ORDERS"},
+		},
+		{
+			name:   "next page",
+			filter: request.ListProceduresRequest{Limit: 2, Offset: 4},
+			args:   []driver.Value{int64(4), int64(3)},
+			rows: [][]driver.Value{
+				{"BILLING", "PKG_INVOICE", nil, "PACKAGE", "VALID", ddl, int64(1), int64(0)},
+				{"BILLING", "RECALC", nil, "PROCEDURE", "VALID", ddl, int64(1), int64(0)},
+				{"BILLING", "TOTALS", nil, "FUNCTION", "VALID", ddl, int64(1), int64(0)},
+			},
+			wantFull: []string{"BILLING.PKG_INVOICE", "BILLING.RECALC"},
+			wantNext: func() *int { n := 6; return &n }(),
+		},
+		{
+			name:   "exposed by the policy",
+			filter: request.ListProceduresRequest{Limit: 5, Exposed: []string{"APP.PKG_ORDERS.GET_ORDER", "APP.RECALC"}},
+			args:   []driver.Value{"APP.PKG_ORDERS.GET_ORDER", "APP.PKG_ORDERS", "APP.RECALC", int64(0), int64(6)},
+			rows: [][]driver.Value{
+				{"APP", "PKG_ORDERS", nil, "PACKAGE", "VALID", ddl, int64(1), int64(1)},
+				{"APP", "PKG_ORDERS", "GET_ORDER", "PACKAGE", "VALID", ddl, int64(1), int64(1)},
+				{"APP", "RECALC", nil, "PROCEDURE", "VALID", ddl, int64(1), int64(1)},
+			},
+			wantFull: []string{"PKG_ORDERS", "PKG_ORDERS.GET_ORDER", "RECALC"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			rows := sqlmock.NewRows(columns)
+			for _, row := range tt.rows {
+				rows.AddRow(row...)
+			}
+			mock.ExpectQuery(`FROM ALL_PROCEDURES p\s+JOIN ALL_OBJECTS o`).WithArgs(tt.args...).WillReturnRows(rows)
+
+			result, err := NewOracleRepository(db).ListProcedures(context.Background(), tt.filter)
+			require.NoError(t, err)
+
+			var full []string
+			for _, item := range result.Items {
+				full = append(full, item.FullName)
+			}
+			assert.Equal(t, tt.wantFull, full)
+			assert.Equal(t, tt.wantNext, result.NextOffset)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOracleRepository_ListProceduresNothingExposed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// A policy naming no procedure that exists exposes none, without a query.
+	result, err := NewOracleRepository(db).ListProcedures(context.Background(), request.ListProceduresRequest{Limit: 5, Exposed: []string{}})
+	require.NoError(t, err)
+	assert.Empty(t, result.Items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var resolveColumns = []string{"TARGET_OWNER", "TARGET_NAME", "TARGET_TYPE", "DB_LINK"}
+
+// expectResolved expects a name lookup that finds an object of owner.
+func expectResolved(mock sqlmock.Sqlmock, owner, object, objectType string) {
+	mock.ExpectQuery(`FROM ALL_SYNONYMS`).WillReturnRows(sqlmock.NewRows(resolveColumns).AddRow(owner, object, objectType, nil))
+}
+
+// expectUnresolved expects n name lookups that find neither an object nor a synonym.
+func expectUnresolved(mock sqlmock.Sqlmock, n int) {
+	for range n {
+		mock.ExpectQuery(`FROM ALL_SYNONYMS`).WillReturnRows(sqlmock.NewRows(resolveColumns))
+	}
+}
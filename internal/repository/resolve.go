@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"oracle-golang/internal/model/response"
+	"strings"
+)
+
+// Error is returned for calls the repository refuses to run.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// maxSynonymHops bounds synonym chains; Oracle reports longer ones as looping (ORA-01775).
+const maxSynonymHops = 10
+
+// resolveObjectQuery looks a name up in the namespace of procedures and packages: an
+// object of the schema, a private synonym of the schema, or a public synonym. An empty
+// schema binds as NULL and means the session's current schema.
+const resolveObjectQuery = `
+        SELECT TARGET_OWNER, TARGET_NAME, TARGET_TYPE, DB_LINK FROM (
+            SELECT OWNER TARGET_OWNER, OBJECT_NAME TARGET_NAME, OBJECT_TYPE TARGET_TYPE, NULL DB_LINK, 1 PRECEDENCE
+            FROM ALL_OBJECTS
+            WHERE OWNER = NVL(:1, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')) AND OBJECT_NAME = :2
+                AND OBJECT_TYPE IN ('PACKAGE', 'PROCEDURE', 'FUNCTION')
+            UNION ALL
+            SELECT TABLE_OWNER, TABLE_NAME, 'SYNONYM', DB_LINK, 2
+            FROM ALL_SYNONYMS
+            WHERE OWNER = NVL(:3, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')) AND SYNONYM_NAME = :4
+            UNION ALL
+            SELECT TABLE_OWNER, TABLE_NAME, 'SYNONYM', DB_LINK, 3
+            FROM ALL_SYNONYMS
+            WHERE OWNER = 'PUBLIC' AND SYNONYM_NAME = :5 AND :6 = 'Y'
+        )
+        ORDER BY PRECEDENCE
+        FETCH FIRST 1 ROWS ONLY
+    `
+
+// resolveName finds the procedure name refers to the way PL/SQL does: the first part of
+// the name is an object of the current schema, else a public synonym, else a schema.
+// Synonyms are followed to their target. ok is false when nothing matches, so the caller
+// can fall back to the name as given and let Oracle report it.
+func resolveName(ctx context.Context, q querier, schema, name string) (target response.ProcedureTarget, ok bool, err error) {
+	parts := strings.Split(strings.ToUpper(name), ".")
+	switch len(parts) {
+	case 1:
+		owner, object, objectType, found, err := resolveObject(ctx, q, schema, parts[0], true)
+		if err != nil || !found || objectType == "PACKAGE" {
+			return target, false, err
+		}
+		return response.ProcedureTarget{Owner: owner, Name: object}, true, nil
+	case 2:
+		owner, object, objectType, found, err := resolveObject(ctx, q, schema, parts[0], true)
+		if err != nil {
+			return target, false, err
+		}
+		if found {
+			if objectType != "PACKAGE" {
+				return target, false, nil
+			}
+			return response.ProcedureTarget{Owner: owner, Package: object, Name: parts[1]}, true, nil
+		}
+		owner, object, objectType, found, err = resolveObject(ctx, q, parts[0], parts[1], false)
+		if err != nil || !found || objectType == "PACKAGE" {
+			return target, false, err
+		}
+		return response.ProcedureTarget{Owner: owner, Name: object}, true, nil
+	case 3:
+		owner, object, objectType, found, err := resolveObject(ctx, q, parts[0], parts[1], false)
+		if err != nil || !found || objectType != "PACKAGE" {
+			return target, false, err
+		}
+		return response.ProcedureTarget{Owner: owner, Package: object, Name: parts[2]}, true, nil
+	default:
+		return target, false, nil
+	}
+}
+
+// resolveObject finds the package, procedure or function name stands for in schema,
+// following synonyms. Public synonyms are only considered for the name itself when
+// public is set; synonym targets are always owner-qualified.
+func resolveObject(ctx context.Context, q querier, schema, name string, public bool) (owner, object, objectType string, found bool, err error) {
+	requested := name
+	for hop := 0; hop < maxSynonymHops; hop++ {
+		usePublic := "N"
+		if public && hop == 0 {
+			usePublic = "Y"
+		}
+
+		var dbLink sql.NullString
+		err := q.QueryRowContext(ctx, resolveObjectQuery, schema, name, schema, name, name, usePublic).
+			Scan(&owner, &object, &objectType, &dbLink)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", "", false, nil
+		}
+		if err != nil {
+			return "", "", "", false, fmt.Errorf("failed to resolve %s: %w", requested, err)
+		}
+		if dbLink.String != "" {
+			return "", "", "", false, fmt.Errorf("%s is a synonym for a remote object over %s, which cannot be called", requested, dbLink.String)
+		}
+		if objectType != "SYNONYM" {
+			return owner, object, objectType, true, nil
+		}
+		schema, name = owner, object
+	}
+	return "", "", "", false, fmt.Errorf("synonym chain for %s is longer than %d", requested, maxSynonymHops)
+}
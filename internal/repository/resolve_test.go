@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lookup is one expected name lookup and the row it finds, if any.
+type lookup struct {
+	schema, name, public string
+	found                []any
+}
+
+func TestResolveName(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		input   string
+		lookups []lookup
+		want    response.ProcedureTarget
+		wantOK  bool
+		wantErr string
+	}{
+		{
+			name:    "package in the current schema",
+			input:   "pkg.get_orders",
+			lookups: []lookup{{name: "PKG", public: "Y", found: []any{"APP", "PKG", "PACKAGE", nil}}},
+			want:    response.ProcedureTarget{Owner: "APP", Package: "PKG", Name: "GET_ORDERS"},
+			wantOK:  true,
+		},
+		{
+			name:   "public synonym for a package",
+			schema: "ACME",
+			input:  "orders_api.get_orders",
+			lookups: []lookup{
+				{schema: "ACME", name: "ORDERS_API", public: "Y", found: []any{"APP", "ORDERS_PKG", "SYNONYM", nil}},
+				{schema: "APP", name: "ORDERS_PKG", public: "N", found: []any{"APP", "ORDERS_PKG", "PACKAGE", nil}},
+			},
+			want:   response.ProcedureTarget{Owner: "APP", Package: "ORDERS_PKG", Name: "GET_ORDERS"},
+			wantOK: true,
+		},
+		{
+			name:  "chained synonyms for a function",
+			input: "next_id",
+			lookups: []lookup{
+				{name: "NEXT_ID", public: "Y", found: []any{"API", "NEXT_ID", "SYNONYM", nil}},
+				{schema: "API", name: "NEXT_ID", public: "N", found: []any{"CORE", "SEQ_NEXT", "SYNONYM", nil}},
+				{schema: "CORE", name: "SEQ_NEXT", public: "N", found: []any{"CORE", "SEQ_NEXT", "FUNCTION", nil}},
+			},
+			want:   response.ProcedureTarget{Owner: "CORE", Name: "SEQ_NEXT"},
+			wantOK: true,
+		},
+		{
+			name:  "schema and procedure",
+			input: "billing.close_day",
+			lookups: []lookup{
+				{name: "BILLING", public: "Y"},
+				{schema: "BILLING", name: "CLOSE_DAY", public: "N", found: []any{"BILLING", "CLOSE_DAY", "PROCEDURE", nil}},
+			},
+			want:   response.ProcedureTarget{Owner: "BILLING", Name: "CLOSE_DAY"},
+			wantOK: true,
+		},
+		{
+			name:  "synonym for a package in another schema",
+			input: "billing.api.close_day",
+			lookups: []lookup{
+				{schema: "BILLING", name: "API", public: "N", found: []any{"BILLING_CORE", "DAY_PKG", "SYNONYM", nil}},
+				{schema: "BILLING_CORE", name: "DAY_PKG", public: "N", found: []any{"BILLING_CORE", "DAY_PKG", "PACKAGE", nil}},
+			},
+			want:   response.ProcedureTarget{Owner: "BILLING_CORE", Package: "DAY_PKG", Name: "CLOSE_DAY"},
+			wantOK: true,
+		},
+		{
+			name:    "package without a subprogram",
+			input:   "pkg",
+			lookups: []lookup{{name: "PKG", public: "Y", found: []any{"APP", "PKG", "PACKAGE", nil}}},
+		},
+		{
+			name:    "unknown name",
+			input:   "missing",
+			lookups: []lookup{{name: "MISSING", public: "Y"}},
+		},
+		{
+			name:    "remote object",
+			input:   "remote_proc",
+			lookups: []lookup{{name: "REMOTE_PROC", public: "Y", found: []any{"APP", "PROC", "SYNONYM", "HQ.EXAMPLE.COM"}}},
+			wantErr: "REMOTE_PROC is a synonym for a remote object over HQ.EXAMPLE.COM, which cannot be called",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			for _, l := range tt.lookups {
+				rows := sqlmock.NewRows(resolveColumns)
+				if l.found != nil {
+					rows.AddRow(toValues(l.found)...)
+				}
+				mock.ExpectQuery(`FROM ALL_SYNONYMS`).
+					WithArgs(l.schema, l.name, l.schema, l.name, l.name, l.public).
+					WillReturnRows(rows)
+			}
+
+			got, ok, err := resolveName(context.Background(), db, tt.schema, tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantOK, ok)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOracleRepository_CallsResolvedTarget(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM ALL_SYNONYMS`).
+		WillReturnRows(sqlmock.NewRows(resolveColumns).AddRow("APP", "ORDERS_PKG", "SYNONYM", nil))
+	mock.ExpectQuery(`FROM ALL_SYNONYMS`).
+		WillReturnRows(sqlmock.NewRows(resolveColumns).AddRow("APP", "ORDERS_PKG", "PACKAGE", nil))
+	mock.ExpectExec(`BEGIN APP\.ORDERS_PKG\.GET_ORDERS\(\); END;`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = NewOracleRepository(db).CallProcedure(context.Background(), "orders_api.get_orders", nil)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOracleRepository_RejectsUnsafeCalls(t *testing.T) {
+	tests := []struct {
+		name       string
+		procedure  string
+		params     []request.ProcedureParam
+		lookup     func(mock sqlmock.Sqlmock)
+		wantStatus int
+	}{
+		{
+			name:       "injected name",
+			procedure:  "X(NULL); EXECUTE IMMEDIATE 'select 1 from B' || CHR(46) || 'T'; Y",
+			wantStatus: http.StatusBadRequest,
+		},
+		{name: "four parts", procedure: "a.b.c.d", wantStatus: http.StatusBadRequest},
+		{
+			name:       "injected bind name",
+			procedure:  "pkg.pay",
+			params:     []request.ProcedureParam{{Name: "p); DROP TABLE t; --", Type: "NUMBER", Direction: "IN"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unresolved name",
+			procedure:  "missing_proc",
+			lookup:     func(mock sqlmock.Sqlmock) { expectUnresolved(mock, 1) },
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "synonym for another tenant's procedure",
+			procedure:  "pay",
+			lookup:     func(mock sqlmock.Sqlmock) { expectResolved(mock, "GLOBEX", "PAY", "PROCEDURE") },
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			db.SetMaxOpenConns(1)
+			if tt.lookup != nil {
+				mock.ExpectExec(`ALTER SESSION SET CURRENT_SCHEMA = ACME`).WillReturnResult(sqlmock.NewResult(0, 0))
+				tt.lookup(mock)
+				mock.ExpectExec(`BEGIN EXECUTE IMMEDIATE`).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			// A tenant is set, so a call that escaped its schema would reach another tenant's data.
+			repo := NewOracleRepository(db,
+				WithCurrentSchema(func(context.Context) string { return "ACME" }),
+				WithTargetCheck(func(_ context.Context, target response.ProcedureTarget) error {
+					if target.Owner != "ACME" {
+						return &Error{Status: http.StatusForbidden, Message: "another tenant"}
+					}
+					return nil
+				}))
+			_, err = repo.CallProcedure(context.Background(), tt.procedure, tt.params)
+
+			var repoErr *Error
+			require.ErrorAs(t, err, &repoErr)
+			assert.Equal(t, tt.wantStatus, repoErr.StatusCode())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func toValues(values []any) []driver.Value {
+	out := make([]driver.Value, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"oracle-golang/internal/model/response"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// describeProcedure returns the comment documenting target in ALL_SOURCE: the comment
+// block above its declaration in the package specification or, for a standalone
+// subprogram, the one just above or below its header. Descriptions are optional, so
+// lookup errors are logged and give an empty description.
+func (r *OracleRepository) describeProcedure(ctx context.Context, target response.ProcedureTarget) string {
+	query := "SELECT TEXT FROM ALL_SOURCE WHERE OWNER = :1 AND NAME = :2 AND TYPE IN ('PROCEDURE', 'FUNCTION') ORDER BY LINE"
+	name := target.Name
+	if target.Package != "" {
+		query = "SELECT TEXT FROM ALL_SOURCE WHERE OWNER = :1 AND NAME = :2 AND TYPE = 'PACKAGE' ORDER BY LINE"
+		name = target.Package
+	}
+
+	lines, err := r.sourceLines(ctx, query, target.Owner, name)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to read procedure comments", "target", target.String(), "error", err)
+		return ""
+	}
+	return procedureComment(lines, target.Name, target.Package == "")
+}
+
+func (r *OracleRepository) sourceLines(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		lines = append(lines, strings.TrimRight(text, "\r\n"))
+	}
+	return lines, rows.Err()
+}
+
+// procedureComment finds the first declaration of the subprogram name in lines and returns
+// the comment above it. With standalone set, a comment just below the header also counts;
+// in a package, that one belongs to the next declaration.
+func procedureComment(lines []string, name string, standalone bool) string {
+	declaration := regexp.MustCompile(`(?i)^\s*(?:PROCEDURE|FUNCTION)\s+(?:"?\w+"?\.)?"?` + regexp.QuoteMeta(name) + `"?(?:[^\w$#]|$)`)
+	for i, line := range lines {
+		if !declaration.MatchString(line) {
+			continue
+		}
+		if comment := commentAbove(lines, i); comment != "" || !standalone {
+			return comment
+		}
+		return commentBelow(lines, i)
+	}
+	return ""
+}
+
+// commentAbove collects the -- lines or /* */ block directly above lines[i].
+func commentAbove(lines []string, i int) string {
+	var comment []string
+	inBlock := false
+scan:
+	for j := i - 1; j >= 0; j-- {
+		line := strings.TrimSpace(lines[j])
+		switch {
+		case inBlock:
+			comment = append(comment, line)
+			if strings.HasPrefix(line, "/*") {
+				inBlock = false
+			}
+		case strings.HasPrefix(line, "--"):
+			comment = append(comment, line)
+		case strings.HasSuffix(line, "*/"):
+			comment = append(comment, line)
+			inBlock = !strings.HasPrefix(line, "/*")
+		default:
+			break scan
+		}
+	}
+	slices.Reverse(comment)
+	return commentText(comment)
+}
+
+// commentBelow collects the -- lines or /* */ block directly below lines[i].
+func commentBelow(lines []string, i int) string {
+	var comment []string
+	inBlock := false
+scan:
+	for j := i + 1; j < len(lines); j++ {
+		line := strings.TrimSpace(lines[j])
+		switch {
+		case inBlock:
+			comment = append(comment, line)
+			inBlock = !strings.HasSuffix(line, "*/")
+		case strings.HasPrefix(line, "--"):
+			comment = append(comment, line)
+		case strings.HasPrefix(line, "/*"):
+			comment = append(comment, line)
+			inBlock = line == "/*" || !strings.HasSuffix(line, "*/")
+		default:
+			break scan
+		}
+	}
+	return commentText(comment)
+}
+
+// commentText strips comment markers and joins the lines of a comment.
+func commentText(lines []string) string {
+	text := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimPrefix(line, "--")
+		line = strings.TrimPrefix(line, "/*")
+		line = strings.TrimSuffix(line, "*/")
+		line = strings.TrimPrefix(strings.TrimSpace(line), "* ")
+		if line = strings.TrimSpace(line); line != "" && line != "*" {
+			text = append(text, line)
+		}
+	}
+	return strings.Join(text, "\n")
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcedureComment(t *testing.T) {
+	spec := []string{
+		"PACKAGE orders_pkg AS",
+		"  -- Returns the open orders of a customer,",
+		"  -- newest first.",
+		"  FUNCTION get_orders(p_customer_id NUMBER) RETURN SYS_REFCURSOR;",
+		"",
+		"  /*",
+		"   * Cancels an order.",
+		"   */",
+		"  PROCEDURE cancel_order(p_order_id NUMBER);",
+		"",
+		"  PROCEDURE cancel_order_line(p_line_id NUMBER);",
+		"  -- Moves old orders to the archive.",
+		"  PROCEDURE \"ARCHIVE\"(p_before DATE);",
+		"END orders_pkg;",
+	}
+	standalone := []string{
+		"PROCEDURE close_day(p_date DATE) IS",
+		"  /* Closes the business day",
+		"     and books the totals. */",
+		"BEGIN",
+		"  NULL; -- nothing yet",
+		"END;",
+	}
+
+	tests := []struct {
+		name       string
+		lines      []string
+		proc       string
+		standalone bool
+		want       string
+	}{
+		{name: "line comments", lines: spec, proc: "GET_ORDERS", want: "Returns the open orders of a customer,\nnewest first."},
+		{name: "block comment", lines: spec, proc: "CANCEL_ORDER", want: "Cancels an order."},
+		{name: "no comment", lines: spec, proc: "CANCEL_ORDER_LINE", want: ""},
+		{name: "quoted name", lines: spec, proc: "ARCHIVE", want: "Moves old orders to the archive."},
+		{name: "missing", lines: spec, proc: "GET_ORDER", want: ""},
+		{name: "below a standalone header", lines: standalone, proc: "CLOSE_DAY", standalone: true, want: "Closes the business day\nand books the totals."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, procedureComment(tt.lines, tt.proc, tt.standalone))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"oracle-golang/pkg/util"
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half-open"
+)
+
+type BreakerOptions struct {
+	// FailureThreshold is the number of consecutive connection errors that opens the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before it lets a single probe call through.
+	OpenTimeout time.Duration
+}
+
+// OpenError is returned without calling the database while the breaker is open.
+type OpenError struct {
+	Wait time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return "database unavailable: circuit breaker is open"
+}
+
+func (e *OpenError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
+
+func (e *OpenError) RetryAfter() time.Duration {
+	return e.Wait
+}
+
+// Breaker counts consecutive connection-class errors (see util.IsConnectionError). Errors
+// raised by the procedure itself prove that the database is reachable and reset the count.
+type Breaker struct {
+	opts BreakerOptions
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(opts BreakerOptions) *Breaker {
+	if opts.FailureThreshold < 1 {
+		opts.FailureThreshold = 1
+	}
+	return &Breaker{opts: opts, now: time.Now, state: StateClosed}
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow returns an *OpenError when the call must not reach the database. Every allowed
+// call must be followed by Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		remaining := b.opts.OpenTimeout - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return &OpenError{Wait: remaining}
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		if b.probing {
+			return &OpenError{Wait: time.Second}
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	probe := b.probing
+	b.probing = false
+
+	switch {
+	case util.IsConnectionError(err):
+		b.failures++
+		if probe || b.failures >= b.opts.FailureThreshold {
+			b.state = StateOpen
+			b.openedAt = b.now()
+		}
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		// The caller gave up; this says nothing about the database.
+	default:
+		b.failures = 0
+		b.state = StateClosed
+	}
+}
@@ -0,0 +1,91 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sijms/go-ora/v2/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errLostContact = network.NewOracleError(3113)
+	errNoData      = network.NewOracleError(1403)
+)
+
+func newTestBreaker(threshold int) (*Breaker, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBreaker(BreakerOptions{FailureThreshold: threshold, OpenTimeout: 10 * time.Second})
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreaker_OpensOnConsecutiveConnectionErrors(t *testing.T) {
+	b, now := newTestBreaker(3)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.Allow())
+		b.Record(errLostContact)
+	}
+	// An application error proves the database is reachable.
+	require.NoError(t, b.Allow())
+	b.Record(errNoData)
+	assert.Equal(t, StateClosed, b.State())
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Allow())
+		b.Record(errLostContact)
+	}
+	assert.Equal(t, StateOpen, b.State())
+
+	*now = now.Add(4 * time.Second)
+	err := b.Allow()
+	var openErr *OpenError
+	require.ErrorAs(t, err, &openErr)
+	assert.Equal(t, 6*time.Second, openErr.RetryAfter())
+	assert.Equal(t, 503, openErr.StatusCode())
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	tests := []struct {
+		name          string
+		probeErr      error
+		expectedState State
+	}{
+		{name: "successful probe closes", probeErr: nil, expectedState: StateClosed},
+		{name: "failed probe reopens", probeErr: errLostContact, expectedState: StateOpen},
+		{name: "canceled probe stays half-open", probeErr: context.Canceled, expectedState: StateHalfOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, now := newTestBreaker(1)
+			require.NoError(t, b.Allow())
+			b.Record(errLostContact)
+			require.Equal(t, StateOpen, b.State())
+
+			*now = now.Add(10 * time.Second)
+			require.NoError(t, b.Allow(), "one probe is let through")
+			assert.Error(t, b.Allow(), "other calls fail fast while probing")
+
+			b.Record(tt.probeErr)
+			assert.Equal(t, tt.expectedState, b.State())
+		})
+	}
+}
+
+func TestBreaker_ContextErrorsAreNeutral(t *testing.T) {
+	b, _ := newTestBreaker(2)
+
+	require.NoError(t, b.Allow())
+	b.Record(errLostContact)
+	require.NoError(t, b.Allow())
+	b.Record(errors.Join(errors.New("execution failed"), context.DeadlineExceeded))
+	require.NoError(t, b.Allow())
+	b.Record(errLostContact)
+
+	assert.Equal(t, StateOpen, b.State())
+}
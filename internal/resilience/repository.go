@@ -0,0 +1,118 @@
+package resilience
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/service"
+	"oracle-golang/pkg/util"
+	"time"
+)
+
+type RetryOptions struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Repository guards a service.Repository with a Breaker and retries connection errors.
+// Procedure calls are only retried when the procedure is listed as idempotent, because a
+// lost connection does not tell whether the procedure committed. Dictionary lookups are
+// always safe to retry. Names match idempotent procedures through synonyms too.
+type Repository struct {
+	next       service.Repository
+	breaker    *Breaker
+	retry      RetryOptions
+	idempotent *service.Names
+	sleep      func(ctx context.Context, d time.Duration) error
+	jitter     func(d time.Duration) time.Duration
+}
+
+func NewRepository(next service.Repository, breaker *Breaker, retry RetryOptions, idempotent *service.Names) *Repository {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+
+	return &Repository{
+		next:       next,
+		breaker:    breaker,
+		retry:      retry,
+		idempotent: idempotent,
+		sleep:      sleep,
+		jitter:     fullJitter,
+	}
+}
+
+func (r *Repository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	attempts := 1
+	if _, ok := r.idempotent.Match(ctx, name); ok {
+		attempts = r.retry.MaxAttempts
+	}
+
+	return call(ctx, r, attempts, func() (map[string]any, error) {
+		return r.next.CallProcedure(ctx, name, params)
+	})
+}
+
+func (r *Repository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	return call(ctx, r, r.retry.MaxAttempts, func() (*response.GetProcedureInfoResponse, error) {
+		return r.next.GetProcedureInfo(ctx, procedureName)
+	})
+}
+
+func (r *Repository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return call(ctx, r, r.retry.MaxAttempts, func() (response.ListProceduresResponse, error) {
+		return r.next.ListProcedures(ctx, filter)
+	})
+}
+
+func call[T any](ctx context.Context, r *Repository, attempts int, fn func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		if err := r.breaker.Allow(); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		result, err := fn()
+		r.breaker.Record(err)
+		if err == nil || attempt >= attempts || !util.IsConnectionError(err) {
+			return result, err
+		}
+
+		slog.WarnContext(ctx, "retrying after connection error", "attempt", attempt, "max_attempts", attempts, "error", err)
+		if serr := r.sleep(ctx, r.backoff(attempt)); serr != nil {
+			return result, err
+		}
+	}
+}
+
+func (r *Repository) backoff(retry int) time.Duration {
+	d := r.retry.BaseDelay << (retry - 1)
+	if r.retry.MaxDelay > 0 && (d > r.retry.MaxDelay || d <= 0) {
+		d = r.retry.MaxDelay
+	}
+	return r.jitter(d)
+}
+
+// fullJitter spreads retries from many clients over [0, d) so that they do not hit a
+// recovering database at the same moment.
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,178 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"oracle-golang/internal/cache"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository returns the queued errors in order, then succeeds.
+type fakeRepository struct {
+	errs  []error
+	calls int
+}
+
+func (f *fakeRepository) next() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakeRepository) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return map[string]any{"ok": true}, nil
+}
+
+func (f *fakeRepository) GetProcedureInfo(context.Context, string) (*response.GetProcedureInfoResponse, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &response.GetProcedureInfoResponse{}, nil
+}
+
+func (f *fakeRepository) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	if err := f.next(); err != nil {
+		return response.ListProceduresResponse{}, err
+	}
+	return response.ListProceduresResponse{}, nil
+}
+
+func newTestRepository(next *fakeRepository, threshold int) (*Repository, *[]time.Duration) {
+	var delays []time.Duration
+	repo := NewRepository(next, NewBreaker(BreakerOptions{FailureThreshold: threshold, OpenTimeout: time.Minute}),
+		RetryOptions{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 150 * time.Millisecond},
+		newNames("pkg.get_rates"))
+	repo.jitter = func(d time.Duration) time.Duration { return d }
+	repo.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return repo, &delays
+}
+
+func TestRepository_CallProcedure(t *testing.T) {
+	tests := []struct {
+		name           string
+		procedure      string
+		errs           []error
+		expectedCalls  int
+		expectedErr    error
+		expectedDelays []time.Duration
+	}{
+		{
+			name:           "idempotent procedure is retried",
+			procedure:      "PKG.GET_RATES",
+			errs:           []error{errLostContact, errLostContact},
+			expectedCalls:  3,
+			expectedDelays: []time.Duration{100 * time.Millisecond, 150 * time.Millisecond},
+		},
+		{
+			name:          "idempotent procedure gives up after max attempts",
+			procedure:     "pkg.get_rates",
+			errs:          []error{errLostContact, errLostContact, errLostContact, errLostContact},
+			expectedCalls: 3,
+			expectedErr:   errLostContact,
+			expectedDelays: []time.Duration{
+				100 * time.Millisecond, 150 * time.Millisecond,
+			},
+		},
+		{
+			name:           "synonym of an idempotent procedure is retried",
+			procedure:      "rates",
+			errs:           []error{errLostContact},
+			expectedCalls:  2,
+			expectedDelays: []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:          "non-idempotent procedure is not retried",
+			procedure:     "pkg.pay",
+			errs:          []error{errLostContact},
+			expectedCalls: 1,
+			expectedErr:   errLostContact,
+		},
+		{
+			name:          "application errors are not retried",
+			procedure:     "pkg.get_rates",
+			errs:          []error{errNoData},
+			expectedCalls: 1,
+			expectedErr:   errNoData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &fakeRepository{errs: tt.errs}
+			repo, delays := newTestRepository(next, 10)
+
+			_, err := repo.CallProcedure(context.Background(), tt.procedure, nil)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedCalls, next.calls)
+			assert.Equal(t, tt.expectedDelays, *delays)
+		})
+	}
+}
+
+func TestRepository_FailsFastWhenOpen(t *testing.T) {
+	next := &fakeRepository{errs: []error{errLostContact, errLostContact}}
+	repo, _ := newTestRepository(next, 2)
+
+	_, err := repo.CallProcedure(context.Background(), "pkg.get_rates", nil)
+	var openErr *OpenError
+	require.ErrorAs(t, err, &openErr, "the breaker opens before the last retry")
+	assert.Equal(t, 2, next.calls)
+
+	_, err = repo.GetProcedureInfo(context.Background(), "pkg.get_rates")
+	assert.True(t, errors.As(err, &openErr))
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestRepository_StopsRetryingWhenCanceled(t *testing.T) {
+	next := &fakeRepository{errs: []error{errLostContact, errLostContact}}
+	repo, _ := newTestRepository(next, 10)
+	repo.sleep = func(ctx context.Context, _ time.Duration) error { return context.Canceled }
+
+	_, err := repo.GetProcedureInfo(context.Background(), "pkg.get_rates")
+
+	assert.Equal(t, errLostContact, err)
+	assert.Equal(t, 1, next.calls)
+}
+
+// synonyms resolves RATES and the procedure it stands for to the same target, as the data
+// dictionary would.
+type synonyms struct{}
+
+func (synonyms) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	return nil, nil
+}
+
+func (synonyms) GetProcedureInfo(_ context.Context, name string) (*response.GetProcedureInfoResponse, error) {
+	target := response.ProcedureTarget{Owner: "APP", Package: "PKG", Name: strings.ToUpper(name[strings.LastIndex(name, ".")+1:])}
+	if strings.EqualFold(name, "rates") {
+		target.Name = "GET_RATES"
+	}
+	return &response.GetProcedureInfoResponse{Name: name, Target: target, Overloads: []response.ProcedureOverload{{}}}, nil
+}
+
+func (synonyms) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{}, nil
+}
+
+func newNames(names ...string) *service.Names {
+	return service.NewNames(service.NewNameResolver(synonyms{}, cache.NewLRU[string](100, 0), time.Minute), names)
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"oracle-golang/internal/model/request"
+	"os"
+	"strings"
+)
+
+// Definition describes a single scheduled procedure call.
+type Definition struct {
+	Name     string                       `json:"name"`
+	Cron     string                       `json:"cron"`
+	Timezone string                       `json:"timezone,omitempty"`
+	Timeout  string                       `json:"timeout,omitempty"`
+	Paused   bool                         `json:"paused,omitempty"`
+	Request  request.CallProcedureRequest `json:"request"`
+}
+
+type File struct {
+	Schedules []Definition `json:"schedules"`
+}
+
+// LoadFile reads schedule definitions from a JSON file.
+func LoadFile(path string) ([]Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schedules file: %w", err)
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse schedules file: %w", err)
+	}
+
+	seen := make(map[string]bool, len(f.Schedules))
+	for i, d := range f.Schedules {
+		if strings.TrimSpace(d.Name) == "" {
+			return nil, fmt.Errorf("schedule[%d] name is required", i)
+		}
+		if seen[d.Name] {
+			return nil, fmt.Errorf("schedule %s is defined more than once", d.Name)
+		}
+		seen[d.Name] = true
+
+		if err := d.Request.Validate(); err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", d.Name, err)
+		}
+	}
+
+	return f.Schedules, nil
+}
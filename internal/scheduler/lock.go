@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	goora "github.com/sijms/go-ora/v2"
+)
+
+// Locker makes sure a schedule runs on a single replica at a time.
+type Locker interface {
+	// TryLock acquires the named lock without waiting. It reports false when another
+	// holder owns the lock. The returned function releases the lock.
+	TryLock(ctx context.Context, name string) (func(), bool, error)
+}
+
+// NoopLocker always grants the lock. It is meant for single instance deployments.
+type NoopLocker struct{}
+
+func (NoopLocker) TryLock(context.Context, string) (func(), bool, error) {
+	return func() {}, true, nil
+}
+
+const (
+	lockRequestSQL = `DECLARE
+    l_handle VARCHAR2(128);
+BEGIN
+    DBMS_LOCK.ALLOCATE_UNIQUE(:name, l_handle);
+    :status := DBMS_LOCK.REQUEST(l_handle, DBMS_LOCK.X_MODE, 0, FALSE);
+    :handle := l_handle;
+END;`
+	lockReleaseSQL = `DECLARE
+    l_status INTEGER;
+BEGIN
+    l_status := DBMS_LOCK.RELEASE(:handle);
+END;`
+)
+
+// DBMS_LOCK.REQUEST return codes
+const (
+	lockGranted    = 0
+	lockTimeout    = 1
+	lockAlreadyOwn = 4
+)
+
+// OracleLocker takes exclusive user locks through DBMS_LOCK. The lock belongs to the database
+// session, so a dedicated connection is held until the lock is released.
+type OracleLocker struct {
+	db     *sql.DB
+	prefix string
+}
+
+func NewOracleLocker(db *sql.DB, prefix string) *OracleLocker {
+	return &OracleLocker{db: db, prefix: prefix}
+}
+
+func (l *OracleLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("lock connection: %w", err)
+	}
+
+	var status sql.NullInt64
+	var handle sql.NullString
+	_, err = conn.ExecContext(ctx, lockRequestSQL,
+		sql.Named("name", l.prefix+name),
+		sql.Named("status", goora.Out{Dest: &status}),
+		sql.Named("handle", goora.Out{Dest: &handle, Size: 128}),
+	)
+	if err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("request lock %s: %w", name, err)
+	}
+
+	switch status.Int64 {
+	case lockGranted, lockAlreadyOwn:
+	case lockTimeout:
+		_ = conn.Close()
+		return nil, false, nil
+	default:
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("request lock %s: DBMS_LOCK.REQUEST returned %d", name, status.Int64)
+	}
+
+	unlock := func() {
+		defer conn.Close()
+		_, _ = conn.ExecContext(context.Background(), lockReleaseSQL, sql.Named("handle", handle.String))
+	}
+	return unlock, true, nil
+}
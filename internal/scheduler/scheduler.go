@@ -0,0 +1,425 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type Executor interface {
+	CallProcedure(ctx context.Context, r request.CallProcedureRequest) (response.CallProcedureResponse, error)
+}
+
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+	RunSkipped   RunStatus = "skipped"
+)
+
+const (
+	TriggerCron   = "cron"
+	TriggerManual = "manual"
+)
+
+var (
+	ErrNotFound = errors.New("schedule not found")
+	// ErrLocalPause is returned by Pause and Resume when other instances run the same
+	// schedules and there is no shared State to tell them.
+	ErrLocalPause = errors.New("pause and resume need a shared scheduler state when several instances run the schedules")
+
+	errLockedElsewhere = errors.New("schedule is running on another instance")
+)
+
+type Run struct {
+	Trigger    string     `json:"trigger"`
+	Status     RunStatus  `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Schedule is a point-in-time view of a schedule and its recent runs, newest first.
+type Schedule struct {
+	Name     string                       `json:"name"`
+	Cron     string                       `json:"cron"`
+	Timezone string                       `json:"timezone,omitempty"`
+	Paused   bool                         `json:"paused"`
+	Running  bool                         `json:"running"`
+	NextRun  *time.Time                   `json:"next_run,omitempty"`
+	Request  request.CallProcedureRequest `json:"request"`
+	History  []Run                        `json:"history"`
+}
+
+type Options struct {
+	// LockHold is the minimum time the replica lock is held after a run starts. It keeps
+	// replicas whose clocks lag slightly behind from starting the same run again.
+	LockHold    time.Duration
+	HistorySize int
+	// State shares pause flags and run history between instances. Without it they are
+	// kept in memory.
+	State State
+	// Replicated says other instances run the same schedules. Without a State, Pause and
+	// Resume then fail with ErrLocalPause rather than pause a single instance.
+	Replicated bool
+}
+
+type entry struct {
+	def     Definition
+	id      cron.EntryID
+	timeout time.Duration
+	paused  bool
+	running bool
+	history []*Run
+}
+
+// Scheduler runs procedure calls on cron schedules. Pause flags and run history live in
+// Options.State when set, so every instance follows a pause; otherwise they only exist on
+// the instance that received the request.
+type Scheduler struct {
+	executor Executor
+	locker   Locker
+	opts     Options
+	cron     *cron.Cron
+	now      func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func New(executor Executor, locker Locker, defs []Definition, opts Options) (*Scheduler, error) {
+	if opts.HistorySize < 1 {
+		opts.HistorySize = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		executor: executor,
+		locker:   locker,
+		opts:     opts,
+		cron:     cron.New(),
+		now:      time.Now,
+		ctx:      ctx,
+		cancel:   cancel,
+		entries:  make(map[string]*entry, len(defs)),
+	}
+
+	for _, def := range defs {
+		e := &entry{def: def, paused: def.Paused}
+
+		if def.Timeout != "" {
+			timeout, err := time.ParseDuration(def.Timeout)
+			if err != nil {
+				cancel()
+				return nil, fmt.Errorf("schedule %s: invalid timeout: %w", def.Name, err)
+			}
+			e.timeout = timeout
+		}
+
+		spec := def.Cron
+		if def.Timezone != "" {
+			spec = "CRON_TZ=" + def.Timezone + " " + spec
+		}
+		name := def.Name
+		id, err := s.cron.AddFunc(spec, func() { s.run(name, TriggerCron) })
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("schedule %s: invalid cron expression: %w", def.Name, err)
+		}
+		e.id = id
+		s.entries[def.Name] = e
+	}
+
+	return s, nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop stops scheduling new runs, cancels the running ones and waits for them to return.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cron.Stop()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) List(ctx context.Context) ([]Schedule, error) {
+	if err := s.syncPaused(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	result := make([]Schedule, 0, len(s.entries))
+	for _, e := range s.entries {
+		result = append(result, s.view(e))
+	}
+	s.mu.Unlock()
+
+	for i := range result {
+		if err := s.sharedHistory(ctx, &result[i]); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (s *Scheduler) Get(ctx context.Context, name string) (*Schedule, error) {
+	if err := s.syncPaused(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	v := s.view(e)
+	s.mu.Unlock()
+
+	if err := s.sharedHistory(ctx, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Trigger starts a run immediately, regardless of the pause state.
+func (s *Scheduler) Trigger(ctx context.Context, name string) (*Schedule, error) {
+	if _, err := s.Get(ctx, name); err != nil {
+		return nil, err
+	}
+
+	s.run(name, TriggerManual)
+	return s.Get(ctx, name)
+}
+
+func (s *Scheduler) Pause(ctx context.Context, name string) (*Schedule, error) {
+	return s.setPaused(ctx, name, true)
+}
+
+func (s *Scheduler) Resume(ctx context.Context, name string) (*Schedule, error) {
+	return s.setPaused(ctx, name, false)
+}
+
+func (s *Scheduler) setPaused(ctx context.Context, name string, paused bool) (*Schedule, error) {
+	s.mu.Lock()
+	_, ok := s.entries[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	switch {
+	case s.opts.State != nil:
+		if err := s.opts.State.SetPaused(ctx, name, paused); err != nil {
+			return nil, err
+		}
+	case s.opts.Replicated:
+		return nil, ErrLocalPause
+	default:
+		s.mu.Lock()
+		s.entries[name].paused = paused
+		s.mu.Unlock()
+	}
+	return s.Get(ctx, name)
+}
+
+// syncPaused applies the pause flags of the shared state.
+func (s *Scheduler) syncPaused(ctx context.Context) error {
+	if s.opts.State == nil {
+		return nil
+	}
+	flags, err := s.opts.State.Paused(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, paused := range flags {
+		if e, ok := s.entries[name]; ok {
+			e.paused = paused
+		}
+	}
+	return nil
+}
+
+// sharedHistory adds the finished runs of the shared state to v, which only holds the runs
+// in progress on this instance.
+func (s *Scheduler) sharedHistory(ctx context.Context, v *Schedule) error {
+	if s.opts.State == nil {
+		return nil
+	}
+	runs, err := s.opts.State.Runs(ctx, v.Name, s.opts.HistorySize)
+	if err != nil {
+		return err
+	}
+	v.History = append(v.History, runs...)
+	if len(v.History) > s.opts.HistorySize {
+		v.History = v.History[:s.opts.HistorySize]
+	}
+	return nil
+}
+
+// run records a new run and executes it in the background.
+func (s *Scheduler) run(name, trigger string) {
+	if trigger == TriggerCron {
+		if err := s.syncPaused(s.ctx); err != nil {
+			slog.Error("failed to read schedule state", "schedule", name, "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[name]
+	if !ok || s.ctx.Err() != nil {
+		return
+	}
+	if trigger == TriggerCron && e.paused {
+		return
+	}
+
+	r := &Run{Trigger: trigger, Status: RunRunning, StartedAt: s.now()}
+	s.record(e, r)
+
+	if e.running {
+		err := errors.New("previous run is still in progress")
+		s.finishLocked(r, RunSkipped, err)
+		s.persistLocked(name, r, err)
+		return
+	}
+	e.running = true
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		status, err := s.execute(e, r)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		e.running = false
+		s.finishLocked(r, status, err)
+		s.persistLocked(name, r, err)
+	}()
+}
+
+func (s *Scheduler) execute(e *entry, r *Run) (RunStatus, error) {
+	unlock, ok, err := s.locker.TryLock(s.ctx, e.def.Name)
+	if err != nil {
+		slog.Error("failed to lock schedule", "schedule", e.def.Name, "error", err)
+		return RunFailed, err
+	}
+	if !ok {
+		return RunSkipped, errLockedElsewhere
+	}
+	defer s.release(unlock, r.StartedAt)
+
+	ctx := s.ctx
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	if _, err := s.executor.CallProcedure(ctx, e.def.Request); err != nil {
+		slog.Error("schedule failed", "schedule", e.def.Name, "procedure", e.def.Request.Name, "error", err)
+		return RunFailed, err
+	}
+	return RunSucceeded, nil
+}
+
+func (s *Scheduler) release(unlock func(), startedAt time.Time) {
+	remaining := s.opts.LockHold - s.now().Sub(startedAt)
+	if remaining <= 0 || s.ctx.Err() != nil {
+		unlock()
+		return
+	}
+	time.AfterFunc(remaining, unlock)
+}
+
+func (s *Scheduler) record(e *entry, r *Run) {
+	e.history = append(e.history, r)
+	if len(e.history) > s.opts.HistorySize {
+		e.history = e.history[len(e.history)-s.opts.HistorySize:]
+	}
+}
+
+func (s *Scheduler) finishLocked(r *Run, status RunStatus, err error) {
+	now := s.now()
+	r.Status = status
+	r.FinishedAt = &now
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// persistLocked saves a finished run to the shared state in the background. Runs skipped
+// because another instance holds the lock are left out, as that instance records its own.
+func (s *Scheduler) persistLocked(name string, r *Run, err error) {
+	if s.opts.State == nil || errors.Is(err, errLockedElsewhere) {
+		return
+	}
+
+	run := *r
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.opts.State.AddRun(ctx, name, run); err != nil {
+			slog.Error("failed to save schedule run", "schedule", name, "error", err)
+		}
+	}()
+}
+
+func (s *Scheduler) view(e *entry) Schedule {
+	v := Schedule{
+		Name:     e.def.Name,
+		Cron:     e.def.Cron,
+		Timezone: e.def.Timezone,
+		Paused:   e.paused,
+		Running:  e.running,
+		Request:  e.def.Request,
+		History:  make([]Run, 0, len(e.history)),
+	}
+
+	if next := s.cron.Entry(e.id).Next; !next.IsZero() && !e.paused {
+		v.NextRun = &next
+	}
+	for i := len(e.history) - 1; i >= 0; i-- {
+		if s.opts.State != nil && e.history[i].Status != RunRunning {
+			continue
+		}
+		v.History = append(v.History, *e.history[i])
+	}
+	return v
+}
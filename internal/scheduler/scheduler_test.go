@@ -0,0 +1,355 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExecutor struct {
+	mu    sync.Mutex
+	calls []string
+	err   error
+}
+
+func (e *fakeExecutor) CallProcedure(_ context.Context, r request.CallProcedureRequest) (response.CallProcedureResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls = append(e.calls, r.Name)
+	return response.CallProcedureResponse{}, e.err
+}
+
+func (e *fakeExecutor) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.calls)
+}
+
+// fakeLocker simulates a lock shared by several replicas
+type fakeLocker struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	events []string
+}
+
+func newFakeLocker() *fakeLocker {
+	return &fakeLocker{held: make(map[string]bool)}
+}
+
+func (l *fakeLocker) TryLock(_ context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+	l.events = append(l.events, "lock")
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, name)
+		l.events = append(l.events, "unlock")
+	}, true, nil
+}
+
+func (l *fakeLocker) isHeld(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held[name]
+}
+
+// fakeState is a State shared by several schedulers, as the database is by instances.
+type fakeState struct {
+	mu     sync.Mutex
+	paused map[string]bool
+	runs   map[string][]Run
+}
+
+func newFakeState() *fakeState {
+	return &fakeState{paused: make(map[string]bool), runs: make(map[string][]Run)}
+}
+
+func (s *fakeState) Paused(context.Context) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make(map[string]bool, len(s.paused))
+	for name, paused := range s.paused {
+		result[name] = paused
+	}
+	return result, nil
+}
+
+func (s *fakeState) SetPaused(_ context.Context, name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused[name] = paused
+	return nil
+}
+
+func (s *fakeState) AddRun(_ context.Context, name string, r Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[name] = append([]Run{r}, s.runs[name]...)
+	return nil
+}
+
+func (s *fakeState) Runs(_ context.Context, name string, limit int) ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := s.runs[name]
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return append([]Run(nil), runs...), nil
+}
+
+func nightly() Definition {
+	return Definition{
+		Name:    "nightly",
+		Cron:    "0 2 * * *",
+		Request: request.CallProcedureRequest{Name: "PKG_BATCH.NIGHTLY"},
+	}
+}
+
+func waitForRun(t *testing.T, s *Scheduler, name string, status RunStatus) Schedule {
+	t.Helper()
+
+	var v *Schedule
+	require.Eventually(t, func() bool {
+		var err error
+		v, err = s.Get(context.Background(), name)
+		return err == nil && len(v.History) > 0 && v.History[0].Status == status
+	}, time.Second, 5*time.Millisecond)
+	return *v
+}
+
+func TestNew_InvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name string
+		def  Definition
+	}{
+		{name: "invalid cron", def: Definition{Name: "bad", Cron: "every day"}},
+		{name: "invalid timezone", def: Definition{Name: "bad", Cron: "0 2 * * *", Timezone: "Mars/Olympus"}},
+		{name: "invalid timeout", def: Definition{Name: "bad", Cron: "0 2 * * *", Timeout: "soon"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&fakeExecutor{}, NoopLocker{}, []Definition{tt.def}, Options{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "schedule bad")
+		})
+	}
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	tests := []struct {
+		name          string
+		executorErr   error
+		expected      RunStatus
+		expectedError string
+	}{
+		{name: "successful run", expected: RunSucceeded},
+		{name: "failed run", executorErr: errors.New("ORA-20001: period is closed"), expected: RunFailed, expectedError: "ORA-20001: period is closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &fakeExecutor{err: tt.executorErr}
+			locker := newFakeLocker()
+
+			s, err := New(executor, locker, []Definition{nightly()}, Options{HistorySize: 10})
+			require.NoError(t, err)
+			defer s.Stop(context.Background())
+
+			_, err = s.Trigger(context.Background(), "nightly")
+			require.NoError(t, err)
+
+			v := waitForRun(t, s, "nightly", tt.expected)
+			assert.Equal(t, TriggerManual, v.History[0].Trigger)
+			assert.Equal(t, tt.expectedError, v.History[0].Error)
+			assert.Equal(t, 1, executor.count())
+			assert.False(t, locker.isHeld("nightly"))
+		})
+	}
+}
+
+func TestScheduler_SkipsWhenLockedElsewhere(t *testing.T) {
+	executor := &fakeExecutor{}
+	locker := newFakeLocker()
+
+	// Another replica holds the lock
+	unlock, ok, err := locker.TryLock(context.Background(), "nightly")
+	require.NoError(t, err)
+	require.True(t, ok)
+	defer unlock()
+
+	s, err := New(executor, locker, []Definition{nightly()}, Options{HistorySize: 10})
+	require.NoError(t, err)
+	defer s.Stop(context.Background())
+
+	_, err = s.Trigger(context.Background(), "nightly")
+	require.NoError(t, err)
+
+	v := waitForRun(t, s, "nightly", RunSkipped)
+	assert.Equal(t, "schedule is running on another instance", v.History[0].Error)
+	assert.Equal(t, 0, executor.count())
+}
+
+func TestScheduler_HoldsLock(t *testing.T) {
+	locker := newFakeLocker()
+
+	s, err := New(&fakeExecutor{}, locker, []Definition{nightly()}, Options{LockHold: 50 * time.Millisecond, HistorySize: 10})
+	require.NoError(t, err)
+	defer s.Stop(context.Background())
+
+	_, err = s.Trigger(context.Background(), "nightly")
+	require.NoError(t, err)
+	waitForRun(t, s, "nightly", RunSucceeded)
+
+	assert.True(t, locker.isHeld("nightly"), "lock is released before the hold period ends")
+	assert.Eventually(t, func() bool { return !locker.isHeld("nightly") }, time.Second, 5*time.Millisecond)
+}
+
+func TestScheduler_PauseResume(t *testing.T) {
+	s, err := New(&fakeExecutor{}, NoopLocker{}, []Definition{nightly()}, Options{HistorySize: 10})
+	require.NoError(t, err)
+	s.Start()
+	defer s.Stop(context.Background())
+
+	v, err := s.Pause(context.Background(), "nightly")
+	require.NoError(t, err)
+	assert.True(t, v.Paused)
+	assert.Nil(t, v.NextRun)
+
+	// Cron runs are ignored while paused
+	s.run("nightly", TriggerCron)
+	v, err = s.Get(context.Background(), "nightly")
+	require.NoError(t, err)
+	assert.Empty(t, v.History)
+
+	v, err = s.Resume(context.Background(), "nightly")
+	require.NoError(t, err)
+	assert.False(t, v.Paused)
+	assert.NotNil(t, v.NextRun)
+
+	_, err = s.Pause(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestScheduler_ReplicatedPauseNeedsState(t *testing.T) {
+	s, err := New(&fakeExecutor{}, NoopLocker{}, []Definition{nightly()}, Options{HistorySize: 10, Replicated: true})
+	require.NoError(t, err)
+	defer s.Stop(context.Background())
+
+	_, err = s.Pause(context.Background(), "nightly")
+	assert.ErrorIs(t, err, ErrLocalPause)
+	_, err = s.Pause(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestScheduler_SharedState(t *testing.T) {
+	state := newFakeState()
+	opts := Options{HistorySize: 10, State: state, Replicated: true}
+	first, err := New(&fakeExecutor{}, NoopLocker{}, []Definition{nightly()}, opts)
+	require.NoError(t, err)
+	defer first.Stop(context.Background())
+	executor := &fakeExecutor{}
+	second, err := New(executor, NoopLocker{}, []Definition{nightly()}, opts)
+	require.NoError(t, err)
+	defer second.Stop(context.Background())
+
+	_, err = first.Pause(context.Background(), "nightly")
+	require.NoError(t, err)
+
+	// The other instance follows the pause
+	v, err := second.Get(context.Background(), "nightly")
+	require.NoError(t, err)
+	assert.True(t, v.Paused)
+	second.run("nightly", TriggerCron)
+	assert.Equal(t, 0, executor.count())
+
+	// and sees the runs of the first
+	_, err = first.Trigger(context.Background(), "nightly")
+	require.NoError(t, err)
+	run := waitForRun(t, second, "nightly", RunSucceeded)
+	assert.Equal(t, TriggerManual, run.History[0].Trigger)
+
+	_, err = second.Resume(context.Background(), "nightly")
+	require.NoError(t, err)
+	v, err = first.Get(context.Background(), "nightly")
+	require.NoError(t, err)
+	assert.False(t, v.Paused)
+}
+
+func TestScheduler_HistorySize(t *testing.T) {
+	s, err := New(&fakeExecutor{}, NoopLocker{}, []Definition{nightly()}, Options{HistorySize: 2})
+	require.NoError(t, err)
+	defer s.Stop(context.Background())
+
+	for i := 0; i < 3; i++ {
+		_, err := s.Trigger(context.Background(), "nightly")
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			v, _ := s.Get(context.Background(), "nightly")
+			return !v.Running
+		}, time.Second, 5*time.Millisecond)
+	}
+
+	v, err := s.Get(context.Background(), "nightly")
+	require.NoError(t, err)
+	assert.Len(t, v.History, 2)
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedCount int
+		expectedError string
+	}{
+		{
+			name:          "valid file",
+			content:       `{"schedules": [{"name": "nightly", "cron": "0 2 * * *", "request": {"name": "PKG.NIGHTLY"}}]}`,
+			expectedCount: 1,
+		},
+		{
+			name:          "missing procedure name",
+			content:       `{"schedules": [{"name": "nightly", "cron": "0 2 * * *", "request": {}}]}`,
+			expectedError: "schedule nightly: procedure name is required",
+		},
+		{
+			name: "duplicate name",
+			content: `{"schedules": [
+				{"name": "nightly", "cron": "0 2 * * *", "request": {"name": "A"}},
+				{"name": "nightly", "cron": "0 3 * * *", "request": {"name": "B"}}
+			]}`,
+			expectedError: "schedule nightly is defined more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "schedules.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			defs, err := LoadFile(path)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, defs, tt.expectedCount)
+		})
+	}
+}
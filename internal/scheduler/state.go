@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// State keeps pause flags and finished runs where every instance running the schedules
+// sees them.
+type State interface {
+	// Paused returns the flags set through Pause and Resume, by schedule name. Schedules
+	// without one keep the "paused" setting of the file.
+	Paused(ctx context.Context) (map[string]bool, error)
+	SetPaused(ctx context.Context, name string, paused bool) error
+	AddRun(ctx context.Context, name string, r Run) error
+	// Runs returns the latest finished runs of a schedule, newest first.
+	Runs(ctx context.Context, name string, limit int) ([]Run, error)
+}
+
+var tableName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]*(\.[A-Za-z][A-Za-z0-9_$#]*)?$`)
+
+// OracleState keeps the scheduler state in two tables, expected to look like:
+//
+//	CREATE TABLE SCHEDULE_STATE (
+//	    SCHEDULE_NAME VARCHAR2(128) PRIMARY KEY,
+//	    PAUSED        NUMBER(1) NOT NULL,
+//	    UPDATED_AT    TIMESTAMP WITH TIME ZONE NOT NULL
+//	);
+//	CREATE TABLE SCHEDULE_RUNS (
+//	    SCHEDULE_NAME VARCHAR2(128) NOT NULL,
+//	    TRIGGER_TYPE  VARCHAR2(16) NOT NULL,
+//	    STATUS        VARCHAR2(16) NOT NULL,
+//	    ERROR_MESSAGE VARCHAR2(4000),
+//	    STARTED_AT    TIMESTAMP WITH TIME ZONE NOT NULL,
+//	    FINISHED_AT   TIMESTAMP WITH TIME ZONE
+//	);
+//
+// Old runs are not deleted; only the latest HistorySize are read.
+type OracleState struct {
+	db         *sql.DB
+	stateTable string
+	runsTable  string
+	now        func() time.Time
+}
+
+func NewOracleState(db *sql.DB, stateTable, runsTable string) (*OracleState, error) {
+	for _, table := range []string{stateTable, runsTable} {
+		if !tableName.MatchString(table) {
+			return nil, fmt.Errorf("invalid scheduler table name: %q", table)
+		}
+	}
+	return &OracleState{db: db, stateTable: stateTable, runsTable: runsTable, now: time.Now}, nil
+}
+
+func (s *OracleState) Paused(ctx context.Context) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT SCHEDULE_NAME, PAUSED FROM "+s.stateTable)
+	if err != nil {
+		return nil, fmt.Errorf("query schedule state: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		var paused int
+		if err := rows.Scan(&name, &paused); err != nil {
+			return nil, fmt.Errorf("scan schedule state: %w", err)
+		}
+		result[name] = paused != 0
+	}
+	return result, rows.Err()
+}
+
+func (s *OracleState) SetPaused(ctx context.Context, name string, paused bool) error {
+	flag := 0
+	if paused {
+		flag = 1
+	}
+	_, err := s.db.ExecContext(ctx, "MERGE INTO "+s.stateTable+" s"+
+		" USING (SELECT :1 AS SCHEDULE_NAME, :2 AS PAUSED, :3 AS UPDATED_AT FROM DUAL) v"+
+		" ON (s.SCHEDULE_NAME = v.SCHEDULE_NAME)"+
+		" WHEN MATCHED THEN UPDATE SET s.PAUSED = v.PAUSED, s.UPDATED_AT = v.UPDATED_AT"+
+		" WHEN NOT MATCHED THEN INSERT (SCHEDULE_NAME, PAUSED, UPDATED_AT) VALUES (v.SCHEDULE_NAME, v.PAUSED, v.UPDATED_AT)",
+		name, flag, s.now())
+	if err != nil {
+		return fmt.Errorf("save schedule state: %w", err)
+	}
+	return nil
+}
+
+func (s *OracleState) AddRun(ctx context.Context, name string, r Run) error {
+	_, err := s.db.ExecContext(ctx, "INSERT INTO "+s.runsTable+
+		" (SCHEDULE_NAME, TRIGGER_TYPE, STATUS, ERROR_MESSAGE, STARTED_AT, FINISHED_AT)"+
+		" VALUES (:1, :2, :3, :4, :5, :6)",
+		name, r.Trigger, string(r.Status), truncate(r.Error, 4000), r.StartedAt, r.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("save schedule run: %w", err)
+	}
+	return nil
+}
+
+func (s *OracleState) Runs(ctx context.Context, name string, limit int) ([]Run, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT TRIGGER_TYPE, STATUS, ERROR_MESSAGE, STARTED_AT, FINISHED_AT FROM "+s.runsTable+
+		" WHERE SCHEDULE_NAME = :1 ORDER BY STARTED_AT DESC FETCH FIRST :2 ROWS ONLY", name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query schedule runs: %w", err)
+	}
+	defer rows.Close()
+
+	result := []Run{}
+	for rows.Next() {
+		var r Run
+		var status string
+		var errorMessage sql.NullString
+		var finishedAt sql.NullTime
+		if err := rows.Scan(&r.Trigger, &status, &errorMessage, &r.StartedAt, &finishedAt); err != nil {
+			return nil, fmt.Errorf("scan schedule run: %w", err)
+		}
+		r.Status = RunStatus(status)
+		r.Error = errorMessage.String
+		if finishedAt.Valid {
+			r.FinishedAt = &finishedAt.Time
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOracleState_InvalidTable(t *testing.T) {
+	_, err := NewOracleState(nil, "SCHEDULE_STATE", "RUNS; DROP TABLE X")
+	assert.EqualError(t, err, `invalid scheduler table name: "RUNS; DROP TABLE X"`)
+}
+
+func TestOracleState(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	state, err := NewOracleState(db, "SCHEDULE_STATE", "SCHEDULE_RUNS")
+	require.NoError(t, err)
+	now := time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC)
+	state.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	mock.ExpectExec(`MERGE INTO SCHEDULE_STATE`).WithArgs("nightly", 1, now).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, state.SetPaused(ctx, "nightly", true))
+
+	mock.ExpectQuery(`SELECT SCHEDULE_NAME, PAUSED FROM SCHEDULE_STATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"SCHEDULE_NAME", "PAUSED"}).AddRow("nightly", 1).AddRow("hourly", 0))
+	paused, err := state.Paused(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"nightly": true, "hourly": false}, paused)
+
+	finished := now.Add(time.Minute)
+	mock.ExpectExec(`INSERT INTO SCHEDULE_RUNS`).
+		WithArgs("nightly", TriggerCron, "failed", "ORA-20001: period is closed", now, &finished).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, state.AddRun(ctx, "nightly", Run{Trigger: TriggerCron, Status: RunFailed, Error: "ORA-20001: period is closed", StartedAt: now, FinishedAt: &finished}))
+
+	mock.ExpectQuery(`SELECT TRIGGER_TYPE, STATUS, ERROR_MESSAGE, STARTED_AT, FINISHED_AT FROM SCHEDULE_RUNS WHERE SCHEDULE_NAME = :1 ORDER BY STARTED_AT DESC FETCH FIRST :2 ROWS ONLY`).
+		WithArgs("nightly", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"TRIGGER_TYPE", "STATUS", "ERROR_MESSAGE", "STARTED_AT", "FINISHED_AT"}).
+			AddRow(TriggerCron, "failed", "ORA-20001: period is closed", now, finished))
+	runs, err := state.Runs(ctx, "nightly", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []Run{{Trigger: TriggerCron, Status: RunFailed, Error: "ORA-20001: period is closed", StartedAt: now, FinishedAt: &finished}}, runs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
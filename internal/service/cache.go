@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/cache"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"oracle-golang/internal/tracing"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CachingRepository answers calls to the procedures listed in its TTLs from a cache. It
+// belongs below the audit and rate limit decorators, so that cached answers are recorded
+// and counted like any other call. Only read-only procedures belong there: a cached call
+// does not reach the database, and the cache is shared by every caller. Calls through a
+// synonym or another spelling of a listed procedure share its entries.
+type CachingRepository struct {
+	next  Repository
+	cache *cache.LRU[response.CallProcedureResponse]
+	ttls  map[string]time.Duration
+	names *Names
+	// perPrincipal keeps cached responses apart per caller, for deployments that call
+	// procedures in per-user database sessions.
+	perPrincipal bool
+}
+
+func NewCachingRepository(next Repository, c *cache.LRU[response.CallProcedureResponse], ttls map[string]time.Duration, names *Names, perPrincipal bool) *CachingRepository {
+	return &CachingRepository{next: next, cache: c, ttls: normalizeTTLs(ttls), names: names, perPrincipal: perPrincipal}
+}
+
+func (r *CachingRepository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	listed, ok := r.names.Match(ctx, name)
+	if !ok {
+		return r.next.CallProcedure(ctx, name, params)
+	}
+	ttl := r.ttls[listed]
+
+	// Tenants run the same procedures against their own schemas.
+	key := tenant.Name(ctx) + "\x00" + cacheKey(listed, params)
+	if r.perPrincipal {
+		key = auth.PrincipalFrom(ctx) + "\x00" + key
+	}
+	span := trace.SpanFromContext(ctx)
+	if cached, ok := r.cache.Get(key); ok {
+		span.SetAttributes(tracing.AttrCacheHit.Bool(true))
+		return cached, nil
+	}
+	span.SetAttributes(tracing.AttrCacheHit.Bool(false))
+
+	result, err := r.next.CallProcedure(ctx, name, params)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(result); err == nil {
+		r.cache.Set(key, result, int64(len(data)), ttl)
+	}
+	return result, nil
+}
+
+func (r *CachingRepository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	return r.next.GetProcedureInfo(ctx, procedureName)
+}
+
+func (r *CachingRepository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return r.next.ListProcedures(ctx, filter)
+}
+
+func normalizeTTLs(ttls map[string]time.Duration) map[string]time.Duration {
+	result := make(map[string]time.Duration, len(ttls))
+	for name, ttl := range ttls {
+		result[normalize(name)] = ttl
+	}
+	return result
+}
+
+// cacheKey identifies a call by procedure name and its parameters in name order. OUT
+// parameters are part of the key because they decide what the response contains.
+func cacheKey(name string, params []request.ProcedureParam) string {
+	type param struct {
+		Name      string `json:"n"`
+		Type      string `json:"t"`
+		Direction string `json:"d"`
+		Value     any    `json:"v,omitempty"`
+	}
+
+	keyed := make([]param, 0, len(params))
+	for _, p := range params {
+		np := param{Name: normalize(p.Name), Type: normalize(p.Type), Direction: normalize(p.Direction)}
+		if np.Direction != "OUT" {
+			np.Value = p.Value
+		}
+		keyed = append(keyed, np)
+	}
+	sort.Slice(keyed, func(i, j int) bool { return keyed[i].Name < keyed[j].Name })
+
+	data, _ := json.Marshal(keyed)
+	return normalize(name) + "\x00" + string(data)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/cache"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCachingRepository_Cache(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockRepo.On("CallProcedure", mock.Anything, "pkg.get_rates", mock.Anything).
+		Return(map[string]any{"p_rate": 470.5}, nil).Twice()
+	mockRepo.On("CallProcedure", mock.Anything, "pkg.pay", mock.Anything).
+		Return(map[string]any{"p_id": 1}, nil).Twice()
+
+	repo := NewCachingRepository(mockRepo, cache.NewLRU[response.CallProcedureResponse](100, 0),
+		map[string]time.Duration{"PKG.GET_RATES": time.Minute}, NewNames(nil, []string{"PKG.GET_RATES"}), false)
+
+	call := func(name string, params ...request.ProcedureParam) map[string]any {
+		t.Helper()
+		result, err := repo.CallProcedure(context.Background(), name, params)
+		assert.NoError(t, err)
+		return result
+	}
+	usd := request.ProcedureParam{Name: "p_currency", Type: "VARCHAR2", Value: "USD", Direction: "IN"}
+	eur := request.ProcedureParam{Name: "p_currency", Type: "VARCHAR2", Value: "EUR", Direction: "IN"}
+	day := request.ProcedureParam{Name: "p_day", Type: "VARCHAR2", Value: "2026-01-01", Direction: "IN"}
+	rate := request.ProcedureParam{Name: "p_rate", Type: "NUMBER", Direction: "OUT"}
+
+	assert.Equal(t, map[string]any{"p_rate": 470.5}, call("pkg.get_rates", usd, day, rate))
+	// Same arguments in a different order hit the cache.
+	call("pkg.get_rates", day, usd, rate)
+	// Different IN values miss it.
+	call("pkg.get_rates", eur, day, rate)
+	// Procedures without a TTL are never cached.
+	call("pkg.pay")
+	call("pkg.pay")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachingRepository_CacheSkipsErrors(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockRepo.On("CallProcedure", mock.Anything, "pkg.get_rates", mock.Anything).
+		Return(nil, errors.New("ORA-03113: end-of-file on communication channel")).Twice()
+
+	repo := NewCachingRepository(mockRepo, cache.NewLRU[response.CallProcedureResponse](100, 0),
+		map[string]time.Duration{"pkg.get_rates": time.Minute}, NewNames(nil, []string{"pkg.get_rates"}), false)
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.CallProcedure(context.Background(), "pkg.get_rates", nil)
+		assert.Error(t, err)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachingRepository_CachePerPrincipal(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockRepo.On("CallProcedure", mock.Anything, "pkg.get_rates", mock.Anything).
+		Return(map[string]any{"p_rate": 470.5}, nil).Twice()
+
+	repo := NewCachingRepository(mockRepo, cache.NewLRU[response.CallProcedureResponse](100, 0),
+		map[string]time.Duration{"pkg.get_rates": time.Minute}, NewNames(nil, []string{"pkg.get_rates"}), true)
+
+	for _, principal := range []string{"alice", "bob", "alice"} {
+		ctx := auth.WithAuthenticatedPrincipal(context.Background(), principal)
+		_, err := repo.CallProcedure(ctx, "pkg.get_rates", nil)
+		assert.NoError(t, err)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachingRepository_CachePerTenant(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockRepo.On("CallProcedure", mock.Anything, "pkg.get_orders", mock.Anything).
+		Return(map[string]any{"p_count": 3}, nil).Twice()
+
+	repo := NewCachingRepository(mockRepo, cache.NewLRU[response.CallProcedureResponse](100, 0),
+		map[string]time.Duration{"pkg.get_orders": time.Minute}, NewNames(nil, []string{"pkg.get_orders"}), false)
+
+	// The same call from two tenants reaches the database once for each of them.
+	for _, name := range []string{"acme", "globex", "acme", "globex"} {
+		ctx := tenant.With(context.Background(), tenant.Tenant{Name: name, Schema: name})
+		_, err := repo.CallProcedure(ctx, "pkg.get_orders", nil)
+		assert.NoError(t, err)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCachingRepository_CacheThroughSynonym(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockRepo.On("CallProcedure", mock.Anything, "pkg.get_rates", mock.Anything).
+		Return(map[string]any{"p_rate": 470.5}, nil).Once()
+
+	resolver := NewNameResolver(newSynonyms(), cache.NewLRU[string](100, 0), time.Minute)
+	repo := NewCachingRepository(mockRepo, cache.NewLRU[response.CallProcedureResponse](100, 0),
+		map[string]time.Duration{"pkg.get_rates": time.Minute}, NewNames(resolver, []string{"pkg.get_rates"}), false)
+
+	// Calls through a synonym or the owner share the entry of the listed procedure.
+	for _, name := range []string{"pkg.get_rates", "rates", "app.pkg.get_rates"} {
+		result, err := repo.CallProcedure(context.Background(), name, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"p_rate": 470.5}, result)
+	}
+	mockRepo.AssertExpectations(t)
+}
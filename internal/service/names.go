@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"oracle-golang/internal/cache"
+	"oracle-golang/internal/tenant"
+	"sort"
+	"time"
+)
+
+// NameResolver finds the object a procedure name stands for, owner.package.name, after
+// synonyms. Resolutions are kept for ttl per tenant, as tenants resolve names in their own
+// schemas.
+type NameResolver struct {
+	repo  Repository
+	cache *cache.LRU[string]
+	ttl   time.Duration
+}
+
+func NewNameResolver(repo Repository, c *cache.LRU[string], ttl time.Duration) *NameResolver {
+	return &NameResolver{repo: repo, cache: c, ttl: ttl}
+}
+
+// resolve returns the target of name, or "" when it cannot be resolved.
+func (r *NameResolver) resolve(ctx context.Context, name string) string {
+	key := tenant.Name(ctx) + "\x00" + normalize(name)
+	if target, ok := r.cache.Get(key); ok {
+		return target
+	}
+
+	info, err := r.repo.GetProcedureInfo(ctx, name)
+	if err != nil {
+		// Not kept, so that a lookup failing with the database does not stick.
+		slog.DebugContext(ctx, "failed to resolve procedure name", "procedure", name, "error", err)
+		return ""
+	}
+	target := ""
+	if len(info.Overloads) > 0 {
+		target = info.Target.String()
+	}
+	r.cache.Set(key, target, int64(len(key)+len(target)), r.ttl)
+	return target
+}
+
+// Names is a set of procedure names from a policy. A call matches it by the name as
+// written or, with a resolver, by the object both names stand for, so that owner-qualified
+// names, names relative to the schema and synonyms of a listed procedure all match.
+type Names struct {
+	resolver *NameResolver
+	names    map[string]bool
+}
+
+// NewNames returns the set of names. Without a resolver, names only match as written.
+func NewNames(resolver *NameResolver, names []string) *Names {
+	n := &Names{resolver: resolver, names: make(map[string]bool, len(names))}
+	for _, name := range names {
+		n.names[normalize(name)] = true
+	}
+	return n
+}
+
+// Match returns the listed name that name stands for, normalized.
+func (n *Names) Match(ctx context.Context, name string) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	if key := normalize(name); n.names[key] {
+		return key, true
+	}
+	if n.resolver == nil || len(n.names) == 0 {
+		return "", false
+	}
+
+	target := n.resolver.resolve(ctx, name)
+	if target == "" {
+		return "", false
+	}
+	for listed := range n.names {
+		if n.resolver.resolve(ctx, listed) == target {
+			return listed, true
+		}
+	}
+	return "", false
+}
+
+// Targets returns the objects the listed names resolve to, sorted. Names that do not
+// resolve, such as procedures that do not exist in the tenant's schema, are left out.
+func (n *Names) Targets(ctx context.Context) []string {
+	targets := []string{}
+	if n == nil || n.resolver == nil {
+		return targets
+	}
+
+	seen := make(map[string]bool, len(n.names))
+	for listed := range n.names {
+		if target := n.resolver.resolve(ctx, listed); target != "" && !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
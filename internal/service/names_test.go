@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"oracle-golang/internal/cache"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// synonyms resolves the names it lists to their targets, as the data dictionary would,
+// and counts the lookups.
+type synonyms struct {
+	targets map[string]response.ProcedureTarget
+	lookups int
+}
+
+func (s *synonyms) CallProcedure(context.Context, string, []request.ProcedureParam) (map[string]any, error) {
+	return nil, nil
+}
+
+func (s *synonyms) GetProcedureInfo(ctx context.Context, name string) (*response.GetProcedureInfoResponse, error) {
+	s.lookups++
+	target, ok := s.targets[normalize(tenant.Name(ctx)+":"+name)]
+	if !ok {
+		target, ok = s.targets[normalize(name)]
+	}
+	if !ok {
+		return nil, errors.New("ORA-06564: object does not exist")
+	}
+	return &response.GetProcedureInfoResponse{Name: name, Target: target, Overloads: []response.ProcedureOverload{{}}}, nil
+}
+
+func (s *synonyms) ListProcedures(context.Context, request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	return response.ListProceduresResponse{}, nil
+}
+
+func newSynonyms() *synonyms {
+	rates := response.ProcedureTarget{Owner: "APP", Package: "PKG", Name: "GET_RATES"}
+	return &synonyms{targets: map[string]response.ProcedureTarget{
+		"PKG.GET_RATES":     rates,
+		"APP.PKG.GET_RATES": rates,
+		"RATES":             rates,
+		"PKG.PAY":           {Owner: "APP", Package: "PKG", Name: "PAY"},
+		// In the acme schema, RATES is a synonym of another procedure.
+		"ACME:RATES": {Owner: "ACME", Name: "RATES"},
+	}}
+}
+
+func TestNames_Match(t *testing.T) {
+	acme := tenant.With(context.Background(), tenant.Tenant{Name: "acme", Schema: "ACME"})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		procedure string
+		resolve   bool
+		expected  string
+		matched   bool
+	}{
+		{name: "as written", procedure: " pkg.get_rates ", expected: "PKG.GET_RATES", matched: true},
+		{name: "owner-qualified", procedure: "app.pkg.get_rates", resolve: true, expected: "PKG.GET_RATES", matched: true},
+		{name: "synonym", procedure: "rates", resolve: true, expected: "PKG.GET_RATES", matched: true},
+		{name: "synonym of another procedure in the tenant", ctx: acme, procedure: "rates", resolve: true},
+		{name: "another procedure", procedure: "pkg.pay", resolve: true},
+		{name: "unknown procedure", procedure: "pkg.missing", resolve: true},
+		{name: "synonym without a resolver", procedure: "rates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolver *NameResolver
+			if tt.resolve {
+				resolver = NewNameResolver(newSynonyms(), cache.NewLRU[string](100, 0), time.Minute)
+			}
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			listed, ok := NewNames(resolver, []string{"pkg.get_rates"}).Match(ctx, tt.procedure)
+			assert.Equal(t, tt.matched, ok)
+			assert.Equal(t, tt.expected, listed)
+		})
+	}
+}
+
+func TestNames_MatchCachesResolutions(t *testing.T) {
+	repo := newSynonyms()
+	names := NewNames(NewNameResolver(repo, cache.NewLRU[string](100, 0), time.Minute), []string{"pkg.get_rates"})
+
+	for i := 0; i < 3; i++ {
+		_, ok := names.Match(context.Background(), "rates")
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 2, repo.lookups)
+
+	var none *Names
+	_, ok := none.Match(context.Background(), "pkg.get_rates")
+	assert.False(t, ok)
+}
@@ -2,35 +2,143 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"oracle-golang/internal/cache"
 	"oracle-golang/internal/model/request"
 	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
+	"oracle-golang/internal/tracing"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
 )
 
+const tracerName = "oracle-golang/internal/service"
+
 type Repository interface {
 	CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error)
-	GetProcedureInfo(ctx context.Context, procedureName string) ([]map[string]any, error)
+	GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error)
+	ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error)
 }
 
 type ProcedureService struct {
-	repo Repository
+	repo        Repository
+	cacheTTLs   map[string]time.Duration
+	cached      *Names
+	catalog     *Names
+	metadata    *cache.LRU[*response.GetProcedureInfoResponse]
+	metadataTTL time.Duration
+}
+
+type Option func(*ProcedureService)
+
+// WithCacheTTLs reports the TTLs of the procedures a CachingRepository below the service
+// caches, for the handlers' cache headers. names must be the ones the CachingRepository
+// matches calls with.
+func WithCacheTTLs(ttls map[string]time.Duration, names *Names) Option {
+	return func(ps *ProcedureService) {
+		ps.cacheTTLs = normalizeTTLs(ttls)
+		ps.cached = names
+	}
+}
+
+// WithCatalog limits the procedure catalog to names, the procedures the data source's
+// policy exposes, as the OpenAPI document and the REST routes do.
+func WithCatalog(names *Names) Option {
+	return func(ps *ProcedureService) {
+		ps.catalog = names
+	}
+}
+
+// WithMetadataCache keeps procedure metadata for ttl, per tenant, so that routes binding
+// arguments from it do not query the data dictionary on every call. Changes to a
+// procedure's signature show up once its entry expires.
+func WithMetadataCache(c *cache.LRU[*response.GetProcedureInfoResponse], ttl time.Duration) Option {
+	return func(ps *ProcedureService) {
+		ps.metadata = c
+		ps.metadataTTL = ttl
+	}
 }
 
-func NewProcedureService(repo Repository) *ProcedureService {
-	return &ProcedureService{repo: repo}
+func NewProcedureService(repo Repository, opts ...Option) *ProcedureService {
+	ps := &ProcedureService{repo: repo}
+	for _, opt := range opts {
+		opt(ps)
+	}
+	return ps
+}
+
+// CacheTTL reports whether responses to r are cached and for how long.
+func (ps *ProcedureService) CacheTTL(ctx context.Context, r request.CallProcedureRequest) (time.Duration, bool) {
+	listed, ok := ps.cached.Match(ctx, r.Name)
+	if !ok {
+		return 0, false
+	}
+	return ps.cacheTTLs[listed], true
 }
 
 func (ps *ProcedureService) CallProcedure(ctx context.Context, r request.CallProcedureRequest) (response.CallProcedureResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ProcedureService.CallProcedure")
+	defer span.End()
+	span.SetAttributes(tracing.AttrProcedure.String(r.Name), tracing.AttrParamCount.Int(len(r.Params)))
+
 	result, err := ps.repo.CallProcedure(ctx, r.Name, r.Params)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 	return result, nil
 }
 
-func (ps *ProcedureService) GetProcedureInfo(ctx context.Context, procedureName string) (response.GetProcedureInfoResponse, error) {
+func (ps *ProcedureService) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ProcedureService.GetProcedureInfo")
+	defer span.End()
+	span.SetAttributes(tracing.AttrProcedure.String(procedureName))
+
+	var key string
+	if ps.metadata != nil {
+		key = tenant.Name(ctx) + "\x00" + normalize(procedureName)
+		if cached, ok := ps.metadata.Get(key); ok {
+			span.SetAttributes(tracing.AttrCacheHit.Bool(true))
+			return cached, nil
+		}
+		span.SetAttributes(tracing.AttrCacheHit.Bool(false))
+	}
+
 	result, err := ps.repo.GetProcedureInfo(ctx, procedureName)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
+
+	if ps.metadata != nil {
+		if data, err := json.Marshal(result); err == nil {
+			ps.metadata.Set(key, result, int64(len(data)), ps.metadataTTL)
+		}
+	}
 	return result, nil
 }
+
+func (ps *ProcedureService) ListProcedures(ctx context.Context, r request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ProcedureService.ListProcedures")
+	defer span.End()
+
+	if ps.catalog != nil {
+		r.Exposed = ps.catalog.Targets(ctx)
+	}
+	result, err := ps.repo.ListProcedures(ctx, r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return response.ListProceduresResponse{}, err
+	}
+	return result, nil
+}
+
+func normalize(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
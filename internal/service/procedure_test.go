@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"oracle-golang/internal/cache"
 	"oracle-golang/internal/model/request"
 	"oracle-golang/internal/model/response"
+	"oracle-golang/internal/tenant"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,12 +27,17 @@ func (m *MockRepository) CallProcedure(ctx context.Context, name string, params
 	return args.Get(0).(map[string]any), args.Error(1)
 }
 
-func (m *MockRepository) GetProcedureInfo(ctx context.Context, procedureName string) ([]map[string]any, error) {
+func (m *MockRepository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
 	args := m.Called(ctx, procedureName)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]map[string]any), args.Error(1)
+	return args.Get(0).(*response.GetProcedureInfoResponse), args.Error(1)
+}
+
+func (m *MockRepository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(response.ListProceduresResponse), args.Error(1)
 }
 
 func TestNewProcedureService(t *testing.T) {
@@ -177,50 +185,31 @@ func TestProcedureService_CallProcedure(t *testing.T) {
 }
 
 func TestProcedureService_GetProcedureInfo(t *testing.T) {
+	info := &response.GetProcedureInfoResponse{
+		Name: "test_procedure",
+		Overloads: []response.ProcedureOverload{{Arguments: []response.ProcedureArgument{
+			{Name: "PARAM1", Position: 1, DataType: "VARCHAR2", InOut: "IN"},
+			{Name: "PARAM2", Position: 2, DataType: "NUMBER", InOut: "OUT"},
+		}}},
+	}
+
 	tests := []struct {
 		name           string
 		procedureName  string
 		setupMock      func(*MockRepository)
-		expectedResult response.GetProcedureInfoResponse
+		expectedResult *response.GetProcedureInfoResponse
 		expectedError  error
 	}{
 		{
 			name:          "successful procedure info retrieval",
 			procedureName: "test_procedure",
 			setupMock: func(mockRepo *MockRepository) {
-				expectedResult := []map[string]any{
-					{
-						"ARGUMENT_NAME": "param1",
-						"DATA_TYPE":     "VARCHAR2",
-						"IN_OUT":        "IN",
-						"POSITION":      1,
-					},
-					{
-						"ARGUMENT_NAME": "param2",
-						"DATA_TYPE":     "NUMBER",
-						"IN_OUT":        "OUT",
-						"POSITION":      2,
-					},
-				}
 				mockRepo.On("GetProcedureInfo",
 					mock.Anything,
-					"test_procedure").Return(expectedResult, nil)
-			},
-			expectedResult: response.GetProcedureInfoResponse{
-				{
-					"ARGUMENT_NAME": "param1",
-					"DATA_TYPE":     "VARCHAR2",
-					"IN_OUT":        "IN",
-					"POSITION":      1,
-				},
-				{
-					"ARGUMENT_NAME": "param2",
-					"DATA_TYPE":     "NUMBER",
-					"IN_OUT":        "OUT",
-					"POSITION":      2,
-				},
+					"test_procedure").Return(info, nil)
 			},
-			expectedError: nil,
+			expectedResult: info,
+			expectedError:  nil,
 		},
 		{
 			name:          "procedure not found",
@@ -228,9 +217,9 @@ func TestProcedureService_GetProcedureInfo(t *testing.T) {
 			setupMock: func(mockRepo *MockRepository) {
 				mockRepo.On("GetProcedureInfo",
 					mock.Anything,
-					"nonexistent_procedure").Return([]map[string]any{}, nil)
+					"nonexistent_procedure").Return(&response.GetProcedureInfoResponse{Name: "nonexistent_procedure"}, nil)
 			},
-			expectedResult: response.GetProcedureInfoResponse{},
+			expectedResult: &response.GetProcedureInfoResponse{Name: "nonexistent_procedure"},
 			expectedError:  nil,
 		},
 		{
@@ -250,9 +239,9 @@ func TestProcedureService_GetProcedureInfo(t *testing.T) {
 			setupMock: func(mockRepo *MockRepository) {
 				mockRepo.On("GetProcedureInfo",
 					mock.Anything,
-					"").Return([]map[string]any{}, nil)
+					"").Return(&response.GetProcedureInfoResponse{}, nil)
 			},
-			expectedResult: response.GetProcedureInfoResponse{},
+			expectedResult: &response.GetProcedureInfoResponse{},
 			expectedError:  nil,
 		},
 	}
@@ -338,7 +327,7 @@ func BenchmarkProcedureService_CallProcedure(b *testing.B) {
 
 func BenchmarkProcedureService_GetProcedureInfo(b *testing.B) {
 	mockRepo := &MockRepository{}
-	expectedResult := []map[string]any{{"info": "test"}}
+	expectedResult := &response.GetProcedureInfoResponse{Name: "test_procedure"}
 
 	mockRepo.On("GetProcedureInfo",
 		mock.Anything,
@@ -351,3 +340,58 @@ func BenchmarkProcedureService_GetProcedureInfo(b *testing.B) {
 		_, _ = service.GetProcedureInfo(context.Background(), "test_procedure")
 	}
 }
+
+func TestProcedureService_CacheTTL(t *testing.T) {
+	resolver := NewNameResolver(newSynonyms(), cache.NewLRU[string](100, 0), time.Minute)
+	service := NewProcedureService(&MockRepository{}, WithCacheTTLs(
+		map[string]time.Duration{"PKG.GET_RATES": time.Minute},
+		NewNames(resolver, []string{"PKG.GET_RATES"}),
+	))
+
+	for _, name := range []string{"pkg.get_rates", "rates"} {
+		ttl, ok := service.CacheTTL(context.Background(), request.CallProcedureRequest{Name: name})
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, ttl)
+	}
+	_, ok := service.CacheTTL(context.Background(), request.CallProcedureRequest{Name: "pkg.pay"})
+	assert.False(t, ok)
+}
+
+func TestProcedureService_MetadataCache(t *testing.T) {
+	info := &response.GetProcedureInfoResponse{Name: "pkg.get_orders"}
+	mockRepo := &MockRepository{}
+	mockRepo.On("GetProcedureInfo", mock.Anything, "pkg.get_orders").Return(info, nil).Twice()
+	mockRepo.On("GetProcedureInfo", mock.Anything, "pkg.missing").Return(nil, errors.New("boom")).Twice()
+
+	service := NewProcedureService(mockRepo, WithMetadataCache(cache.NewLRU[*response.GetProcedureInfoResponse](100, 0), time.Minute))
+
+	// Metadata is looked up once per tenant, as tenants resolve names in their own schemas.
+	for _, name := range []string{"acme", "globex", "acme", "globex"} {
+		ctx := tenant.With(context.Background(), tenant.Tenant{Name: name, Schema: name})
+		result, err := service.GetProcedureInfo(ctx, "pkg.get_orders")
+		assert.NoError(t, err)
+		assert.Same(t, info, result)
+
+		if name == "acme" {
+			_, err = service.GetProcedureInfo(ctx, "pkg.missing")
+			assert.Error(t, err)
+		}
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcedureService_ListProceduresCatalog(t *testing.T) {
+	page := response.ListProceduresResponse{Items: []response.ProcedureSummary{{Name: "GET_RATES"}}}
+	mockRepo := &MockRepository{}
+	// Names that resolve to the same procedure are listed once; unknown ones not at all.
+	mockRepo.On("ListProcedures", mock.Anything, request.ListProceduresRequest{Limit: 10, Exposed: []string{"APP.PKG.GET_RATES", "APP.PKG.PAY"}}).
+		Return(page, nil).Once()
+
+	resolver := NewNameResolver(newSynonyms(), cache.NewLRU[string](100, 0), time.Minute)
+	service := NewProcedureService(mockRepo, WithCatalog(NewNames(resolver, []string{"pkg.get_rates", "rates", "pkg.pay", "pkg.missing"})))
+
+	result, err := service.ListProcedures(context.Background(), request.ListProceduresRequest{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+	mockRepo.AssertExpectations(t)
+}
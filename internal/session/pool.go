@@ -0,0 +1,228 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"oracle-golang/internal/auth"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Mode string
+
+const (
+	ModeProxy       Mode = "proxy"
+	ModeCredentials Mode = "credentials"
+)
+
+// Identity is the database identity a session is opened with. ProxyUser is set in proxy
+// mode; User and Password in credentials mode.
+type Identity struct {
+	ProxyUser string
+	User      string
+	Password  string
+}
+
+type Credential struct {
+	User     string
+	Password string
+}
+
+type Config struct {
+	Mode Mode
+	// Required rejects requests without an authenticated principal instead of serving them
+	// from the shared pool. Internal callers such as the scheduler always use the shared pool.
+	Required bool
+	Users    map[string]string
+	// AllowUnmapped lets principals missing from Users through as database user names.
+	AllowUnmapped bool
+	Credentials   map[string]Credential
+
+	MaxUsers    int
+	IdleTimeout time.Duration
+}
+
+// Error is returned when the caller cannot be given a session of its own.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// Opener opens a connection pool for an identity. It should not wait for a connection, so
+// that a slow database does not hold up other users.
+type Opener func(id Identity) (*sql.DB, error)
+
+// oracleUser matches unquoted Oracle user names, which are safe to pass as proxy clients.
+var oracleUser = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_$#]{0,127}$`)
+
+// Pool keeps a connection pool per end user. Pools idle for longer than IdleTimeout are
+// closed, and when MaxUsers pools are open the least recently used idle one makes room.
+// A pool is not idle while a caller holds it, from DB until it calls release, since the
+// caller may not have checked out a connection yet.
+type Pool struct {
+	cfg  Config
+	open Opener
+	now  func() time.Time
+
+	mu     sync.Mutex
+	pools  map[string]*userPool
+	closed bool
+}
+
+type userPool struct {
+	db       *sql.DB
+	lastUsed time.Time
+	holders  int
+}
+
+func New(cfg Config, open Opener) *Pool {
+	if cfg.MaxUsers < 1 {
+		cfg.MaxUsers = 1
+	}
+	return &Pool{
+		cfg:   cfg,
+		open:  open,
+		now:   time.Now,
+		pools: make(map[string]*userPool),
+	}
+}
+
+// DB returns the connection pool of the caller, or nil when the shared pool should be used.
+// The caller must call release once it is done with the pool, so that it can be evicted.
+func (p *Pool) DB(ctx context.Context) (db *sql.DB, release func(), err error) {
+	principal := auth.PrincipalFrom(ctx)
+	if principal == "" {
+		return nil, func() {}, nil
+	}
+	if !auth.Authenticated(ctx) {
+		if p.cfg.Required {
+			return nil, nil, &Error{Status: http.StatusUnauthorized, Message: "an authenticated principal is required for database sessions"}
+		}
+		return nil, func() {}, nil
+	}
+
+	key, id, err := p.identity(principal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, nil, errors.New("session pool is closed")
+	}
+
+	now := p.now()
+	up, ok := p.pools[key]
+	if !ok {
+		p.evictLocked(now)
+		if len(p.pools) >= p.cfg.MaxUsers {
+			return nil, nil, &Error{Status: http.StatusServiceUnavailable, Message: "too many concurrent database users, try again later"}
+		}
+
+		db, err := p.open(id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open session for %s: %w", principal, err)
+		}
+		up = &userPool{db: db}
+		p.pools[key] = up
+	}
+	up.lastUsed = now
+	up.holders++
+
+	var once sync.Once
+	return up.db, func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			up.holders--
+			up.lastUsed = p.now()
+		})
+	}, nil
+}
+
+func (p *Pool) identity(principal string) (string, Identity, error) {
+	switch p.cfg.Mode {
+	case ModeProxy:
+		user, ok := p.cfg.Users[principal]
+		if !ok && p.cfg.AllowUnmapped {
+			user = principal
+		}
+		if !oracleUser.MatchString(user) {
+			return "", Identity{}, &Error{Status: http.StatusForbidden, Message: fmt.Sprintf("principal %s is not mapped to a database user", principal)}
+		}
+		user = strings.ToUpper(user)
+		return user, Identity{ProxyUser: user}, nil
+	case ModeCredentials:
+		cred, ok := p.cfg.Credentials[principal]
+		if !ok {
+			return "", Identity{}, &Error{Status: http.StatusForbidden, Message: fmt.Sprintf("no database credentials for principal %s", principal)}
+		}
+		return principal, Identity{User: cred.User, Password: cred.Password}, nil
+	default:
+		return "", Identity{}, fmt.Errorf("unknown session mode: %s", p.cfg.Mode)
+	}
+}
+
+// evictLocked closes pools idle for longer than IdleTimeout and, when still at MaxUsers,
+// the least recently used pool that no caller holds.
+func (p *Pool) evictLocked(now time.Time) {
+	var lruKey string
+	var lru *userPool
+	for key, up := range p.pools {
+		if up.holders > 0 || up.db.Stats().InUse > 0 {
+			continue
+		}
+		if p.cfg.IdleTimeout > 0 && now.Sub(up.lastUsed) > p.cfg.IdleTimeout {
+			p.closeLocked(key, up)
+			continue
+		}
+		if lru == nil || up.lastUsed.Before(lru.lastUsed) {
+			lruKey, lru = key, up
+		}
+	}
+	if len(p.pools) >= p.cfg.MaxUsers && lru != nil {
+		p.closeLocked(lruKey, lru)
+	}
+}
+
+func (p *Pool) closeLocked(key string, up *userPool) {
+	delete(p.pools, key)
+	if err := up.db.Close(); err != nil {
+		slog.Error("failed to close session pool", "user", key, "error", err)
+	}
+}
+
+// Len returns the number of open per-user pools.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pools)
+}
+
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	var errs []error
+	for key, up := range p.pools {
+		delete(p.pools, key)
+		errs = append(errs, up.db.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,271 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"oracle-golang/internal/auth"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type opener struct {
+	t      *testing.T
+	opened []Identity
+	err    error
+}
+
+func (o *opener) open(id Identity) (*sql.DB, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	o.opened = append(o.opened, id)
+	db, mock, err := sqlmock.New()
+	require.NoError(o.t, err)
+	mock.ExpectBegin()
+	mock.ExpectClose()
+	return db, nil
+}
+
+func TestPool_DB(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		ctx        context.Context
+		wantShared bool
+		wantID     Identity
+		wantStatus int
+	}{
+		{
+			name:       "no principal uses the shared pool",
+			cfg:        Config{Mode: ModeProxy, Required: true},
+			ctx:        context.Background(),
+			wantShared: true,
+		},
+		{
+			name:       "client IP uses the shared pool",
+			cfg:        Config{Mode: ModeProxy},
+			ctx:        auth.WithPrincipal(context.Background(), "10.0.0.1"),
+			wantShared: true,
+		},
+		{
+			name:       "client IP is rejected when required",
+			cfg:        Config{Mode: ModeProxy, Required: true},
+			ctx:        auth.WithPrincipal(context.Background(), "10.0.0.1"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "proxy uses the principal",
+			cfg:    Config{Mode: ModeProxy, AllowUnmapped: true},
+			ctx:    auth.WithAuthenticatedPrincipal(context.Background(), "alice"),
+			wantID: Identity{ProxyUser: "ALICE"},
+		},
+		{
+			name:   "proxy maps the principal",
+			cfg:    Config{Mode: ModeProxy, Users: map[string]string{"alice@example.com": "app_alice"}},
+			ctx:    auth.WithAuthenticatedPrincipal(context.Background(), "alice@example.com"),
+			wantID: Identity{ProxyUser: "APP_ALICE"},
+		},
+		{
+			name:       "proxy rejects unmapped principals",
+			cfg:        Config{Mode: ModeProxy, Users: map[string]string{"alice@example.com": "app_alice"}},
+			ctx:        auth.WithAuthenticatedPrincipal(context.Background(), "bob"),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "proxy rejects principals that are not user names",
+			cfg:        Config{Mode: ModeProxy, AllowUnmapped: true},
+			ctx:        auth.WithAuthenticatedPrincipal(context.Background(), `x" identified by y`),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "credentials",
+			cfg:    Config{Mode: ModeCredentials, Credentials: map[string]Credential{"svc-report": {User: "report", Password: "secret"}}},
+			ctx:    auth.WithAuthenticatedPrincipal(context.Background(), "svc-report"),
+			wantID: Identity{User: "report", Password: "secret"},
+		},
+		{
+			name:       "credentials must be configured",
+			cfg:        Config{Mode: ModeCredentials},
+			ctx:        auth.WithAuthenticatedPrincipal(context.Background(), "svc-report"),
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &opener{t: t}
+			tt.cfg.MaxUsers = 10
+			p := New(tt.cfg, o.open)
+			defer p.Close()
+
+			db, release, err := p.DB(tt.ctx)
+			if tt.wantStatus != 0 {
+				var sessionErr *Error
+				require.ErrorAs(t, err, &sessionErr)
+				assert.Equal(t, tt.wantStatus, sessionErr.StatusCode())
+				return
+			}
+			require.NoError(t, err)
+			defer release()
+			if tt.wantShared {
+				assert.Nil(t, db)
+				assert.Empty(t, o.opened)
+				return
+			}
+			assert.NotNil(t, db)
+			assert.Equal(t, []Identity{tt.wantID}, o.opened)
+		})
+	}
+}
+
+func TestPool_ReusesPools(t *testing.T) {
+	o := &opener{t: t}
+	p := New(Config{Mode: ModeProxy, AllowUnmapped: true, MaxUsers: 10}, o.open)
+	defer p.Close()
+
+	first, _, err := p.DB(auth.WithAuthenticatedPrincipal(context.Background(), "alice"))
+	require.NoError(t, err)
+	second, _, err := p.DB(auth.WithAuthenticatedPrincipal(context.Background(), "ALICE"))
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Len(t, o.opened, 1)
+}
+
+func TestPool_Eviction(t *testing.T) {
+	o := &opener{t: t}
+	p := New(Config{Mode: ModeProxy, AllowUnmapped: true, MaxUsers: 2, IdleTimeout: time.Minute}, o.open)
+	defer p.Close()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	as := func(principal string) (*sql.DB, error) {
+		db, release, err := p.DB(auth.WithAuthenticatedPrincipal(context.Background(), principal))
+		if err == nil {
+			release()
+		}
+		return db, err
+	}
+
+	alice, err := as("alice")
+	require.NoError(t, err)
+	// Alice holds a connection, so her pool cannot be evicted.
+	tx, err := alice.Begin()
+	require.NoError(t, err)
+	now = now.Add(time.Second)
+	_, err = as("bob")
+	require.NoError(t, err)
+
+	// Bob's pool is the only idle one and makes room for Carol.
+	now = now.Add(time.Second)
+	_, err = as("carol")
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Len())
+
+	// With every pool in use, new users are turned away.
+	carol, err := as("carol")
+	require.NoError(t, err)
+	carolTx, err := carol.Begin()
+	require.NoError(t, err)
+	_, err = as("dave")
+	var sessionErr *Error
+	require.ErrorAs(t, err, &sessionErr)
+	assert.Equal(t, http.StatusServiceUnavailable, sessionErr.StatusCode())
+
+	// Pools idle past the timeout are closed.
+	_ = tx.Rollback()
+	_ = carolTx.Rollback()
+	now = now.Add(2 * time.Minute)
+	_, err = as("dave")
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.Len())
+}
+
+func TestPool_OpenError(t *testing.T) {
+	openErr := errors.New("ORA-28150: proxy not authorized to connect as client")
+	p := New(Config{Mode: ModeProxy, AllowUnmapped: true, MaxUsers: 1}, (&opener{t: t, err: openErr}).open)
+
+	_, _, err := p.DB(auth.WithAuthenticatedPrincipal(context.Background(), "alice"))
+	assert.ErrorIs(t, err, openErr)
+	assert.Zero(t, p.Len())
+}
+
+func TestPool_HeldPoolsAreNotEvicted(t *testing.T) {
+	o := &opener{t: t}
+	p := New(Config{Mode: ModeProxy, AllowUnmapped: true, MaxUsers: 1, IdleTimeout: time.Minute}, o.open)
+	defer p.Close()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	// Alice holds her pool without a connection checked out yet, even past the timeout.
+	alice, release, err := p.DB(auth.WithAuthenticatedPrincipal(context.Background(), "alice"))
+	require.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+	_, _, err = p.DB(auth.WithAuthenticatedPrincipal(context.Background(), "bob"))
+	var sessionErr *Error
+	require.ErrorAs(t, err, &sessionErr)
+	assert.Equal(t, http.StatusServiceUnavailable, sessionErr.StatusCode())
+	require.NoError(t, alice.Ping())
+
+	// Once released, it makes room. Releasing twice counts once.
+	release()
+	release()
+	_, bobRelease, err := p.DB(auth.WithAuthenticatedPrincipal(context.Background(), "bob"))
+	require.NoError(t, err)
+	bobRelease()
+	assert.Error(t, alice.Ping())
+}
+
+func TestPool_ConcurrentEviction(t *testing.T) {
+	var mu sync.Mutex
+	o := &opener{t: t}
+	p := New(Config{Mode: ModeProxy, AllowUnmapped: true, MaxUsers: 2, IdleTimeout: time.Minute}, func(id Identity) (*sql.DB, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return o.open(id)
+	})
+	defer p.Close()
+
+	// Every caller gets a pool that stays open until it releases it, while the others
+	// keep evicting pools to make room.
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(principal string) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				db, release, err := p.DB(auth.WithAuthenticatedPrincipal(context.Background(), principal))
+				var sessionErr *Error
+				if errors.As(err, &sessionErr) {
+					continue
+				}
+				if err == nil {
+					// Work done before a connection is checked out, such as binding
+					// arguments, leaves the pool without connections in use.
+					time.Sleep(100 * time.Microsecond)
+					err = db.Ping()
+					release()
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(fmt.Sprintf("user%d", i%3))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,121 @@
+package tenant
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"oracle-golang/internal/auth"
+	"oracle-golang/internal/logger"
+	"oracle-golang/internal/model/response"
+	"strings"
+	"time"
+)
+
+type Source string
+
+const (
+	SourceHeader    Source = "header"
+	SourceJWT       Source = "jwt"
+	SourceSubdomain Source = "subdomain"
+)
+
+type Config struct {
+	Source Source
+	// Header carries the tenant name with SourceHeader. It is only read from requests that
+	// came through a trusted proxy, as recorded by auth.RealIP; others have no tenant.
+	Header string
+	// Claim names the JWT claim holding the tenant with SourceJWT. The token is read from
+	// the Authorization header. Only HS256 tokens signed with JWTSecret are accepted, unless
+	// TrustGatewayVerified says the API gateway has verified the token already.
+	Claim                string
+	JWTSecret            []byte
+	TrustGatewayVerified bool
+	// Domain is the parent domain with SourceSubdomain: acme.api.example.com is tenant acme
+	// when Domain is api.example.com.
+	Domain string
+
+	// Required rejects requests without a tenant instead of running them in the connecting
+	// user's schema.
+	Required bool
+	// Schemas maps tenant names to their schemas. Other tenants are rejected.
+	Schemas map[string]string
+}
+
+// Middleware resolves the tenant of each request and adds it to the request context.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name, err := resolve(cfg, r, time.Now())
+			if err != nil {
+				response.WriteJSON(w, http.StatusUnauthorized, response.ErrorResponse(err.Error(), nil))
+				return
+			}
+			if name == "" {
+				if cfg.Required {
+					response.WriteJSON(w, http.StatusBadRequest, response.ErrorResponse("tenant is required", nil))
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			schema, ok := cfg.Schemas[name]
+			if !ok {
+				response.WriteJSON(w, http.StatusForbidden, response.ErrorResponse("unknown tenant: "+name, nil))
+				return
+			}
+
+			ctx := With(r.Context(), Tenant{Name: name, Schema: strings.ToUpper(schema)})
+			ctx = logger.With(ctx, "tenant", name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func resolve(cfg Config, r *http.Request, now time.Time) (string, error) {
+	switch cfg.Source {
+	case SourceHeader:
+		if !auth.FromTrustedProxy(r.Context()) {
+			return "", nil
+		}
+		return strings.TrimSpace(r.Header.Get(cfg.Header)), nil
+	case SourceJWT:
+		if len(cfg.JWTSecret) == 0 && !cfg.TrustGatewayVerified {
+			return "", errUnverifiable
+		}
+		return claimFrom(r.Header.Get("Authorization"), cfg.Claim, cfg.JWTSecret, now)
+	case SourceSubdomain:
+		return subdomain(r.Host, cfg.Domain), nil
+	default:
+		return "", nil
+	}
+}
+
+// subdomain returns the label of host directly below domain, or "" when host is not a
+// subdomain of it.
+func subdomain(host, domain string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	label, ok := strings.CutSuffix(host, "."+strings.ToLower(domain))
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
+
+// errUnverifiable rejects tokens when there is neither a secret to verify them with nor a
+// gateway trusted to have done so. Config validation prevents this.
+var errUnverifiable = errors.New("bearer token cannot be verified: no JWT secret is configured")
+
+// claimFrom returns the string claim of the bearer token in authorization, or "" without
+// a token.
+func claimFrom(authorization, claim string, secret []byte, now time.Time) (string, error) {
+	claims, err := auth.Claims(authorization, secret, now)
+	if err != nil {
+		return "", err
+	}
+	value, _ := claims[claim].(string)
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,149 @@
+package tenant
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/auth"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedToken(t *testing.T, secret string, claims map[string]any) string {
+	t.Helper()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestMiddleware(t *testing.T) {
+	schemas := map[string]string{"acme": "acme", "globex": "GLOBEX_PROD"}
+	future := float64(time.Now().Add(time.Hour).Unix())
+	past := float64(time.Now().Add(-time.Hour).Unix())
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		setup      func(r *http.Request)
+		wantStatus int
+		wantTenant Tenant
+	}{
+		{
+			name:       "header",
+			cfg:        Config{Source: SourceHeader, Header: "X-Tenant"},
+			setup:      func(r *http.Request) { r.Header.Set("X-Tenant", "globex"); r.RemoteAddr = "10.0.0.5:4000" },
+			wantStatus: http.StatusOK,
+			wantTenant: Tenant{Name: "globex", Schema: "GLOBEX_PROD"},
+		},
+		{
+			name:       "unknown tenant",
+			cfg:        Config{Source: SourceHeader, Header: "X-Tenant"},
+			setup:      func(r *http.Request) { r.Header.Set("X-Tenant", "initech"); r.RemoteAddr = "10.0.0.5:4000" },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "header from an untrusted address",
+			cfg:        Config{Source: SourceHeader, Header: "X-Tenant", Required: true},
+			setup:      func(r *http.Request) { r.Header.Set("X-Tenant", "globex"); r.RemoteAddr = "203.0.113.9:4000" },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing tenant",
+			cfg:        Config{Source: SourceHeader, Header: "X-Tenant", Required: true},
+			setup:      func(r *http.Request) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "optional tenant",
+			cfg:        Config{Source: SourceHeader, Header: "X-Tenant"},
+			setup:      func(r *http.Request) {},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "signed JWT",
+			cfg:  Config{Source: SourceJWT, Claim: "tenant", JWTSecret: []byte("secret")},
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+signedToken(t, "secret", map[string]any{"tenant": "acme", "exp": future}))
+			},
+			wantStatus: http.StatusOK,
+			wantTenant: Tenant{Name: "acme", Schema: "ACME"},
+		},
+		{
+			name: "JWT signed with another key",
+			cfg:  Config{Source: SourceJWT, Claim: "tenant", JWTSecret: []byte("secret")},
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+signedToken(t, "guess", map[string]any{"tenant": "acme"}))
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "expired JWT",
+			cfg:  Config{Source: SourceJWT, Claim: "tenant", JWTSecret: []byte("secret")},
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+signedToken(t, "secret", map[string]any{"tenant": "acme", "exp": past}))
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "JWT verified by the gateway",
+			cfg:  Config{Source: SourceJWT, Claim: "org", TrustGatewayVerified: true},
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+signedToken(t, "gateway", map[string]any{"org": "globex"}))
+			},
+			wantStatus: http.StatusOK,
+			wantTenant: Tenant{Name: "globex", Schema: "GLOBEX_PROD"},
+		},
+		{
+			name: "unverified JWT",
+			cfg:  Config{Source: SourceJWT, Claim: "org"},
+			setup: func(r *http.Request) {
+				r.Header.Set("Authorization", "Bearer "+signedToken(t, "forged", map[string]any{"org": "globex"}))
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "subdomain",
+			cfg:        Config{Source: SourceSubdomain, Domain: "api.example.com"},
+			setup:      func(r *http.Request) { r.Host = "acme.api.example.com:8080" },
+			wantStatus: http.StatusOK,
+			wantTenant: Tenant{Name: "acme", Schema: "ACME"},
+		},
+		{
+			name:       "nested subdomain",
+			cfg:        Config{Source: SourceSubdomain, Domain: "api.example.com", Required: true},
+			setup:      func(r *http.Request) { r.Host = "globex.acme.api.example.com" },
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	trusted, err := auth.ParseNetworks([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Schemas = schemas
+			var got Tenant
+			handler := auth.RealIP(trusted)(Middleware(tt.cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = From(r.Context())
+			})))
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/procedures/call", nil)
+			tt.setup(r)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantTenant, got)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"strings"
+)
+
+type Mode string
+
+const (
+	// ModePrefix qualifies procedure names with the tenant schema.
+	ModePrefix Mode = "prefix"
+	// ModeCurrentSchema sets CURRENT_SCHEMA on the connection for the duration of the call.
+	ModeCurrentSchema Mode = "current_schema"
+)
+
+// repository is service.Repository, which this package cannot import because the service
+// keys its cache by tenant.
+type repository interface {
+	CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error)
+	GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error)
+	ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error)
+}
+
+// Repository runs procedure calls in the schema of the request's tenant. Metadata is
+// looked up against the tenant's schema in both modes. Calls without a tenant pass through
+// unchanged.
+type Repository struct {
+	next    repository
+	mode    Mode
+	schemas map[string]bool
+}
+
+// NewRepository wraps next for tenants whose schemas are listed in schemas. Names
+// qualified with another tenant's schema are rejected.
+func NewRepository(next repository, mode Mode, schemas []string) *Repository {
+	r := &Repository{next: next, mode: mode, schemas: make(map[string]bool, len(schemas))}
+	for _, schema := range schemas {
+		r.schemas[strings.ToUpper(schema)] = true
+	}
+	return r
+}
+
+func (r *Repository) CallProcedure(ctx context.Context, name string, params []request.ProcedureParam) (map[string]any, error) {
+	t, ok := From(ctx)
+	if !ok {
+		return r.next.CallProcedure(ctx, name, params)
+	}
+	if err := r.check(t, name); err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModePrefix {
+		return r.next.CallProcedure(ctx, t.Schema+"."+name, params)
+	}
+	return r.next.CallProcedure(withSchema(ctx, t.Schema), name, params)
+}
+
+func (r *Repository) GetProcedureInfo(ctx context.Context, procedureName string) (*response.GetProcedureInfoResponse, error) {
+	t, ok := From(ctx)
+	if !ok {
+		return r.next.GetProcedureInfo(ctx, procedureName)
+	}
+	if err := r.check(t, procedureName); err != nil {
+		return nil, err
+	}
+	return r.next.GetProcedureInfo(withSchema(ctx, t.Schema), procedureName)
+}
+
+// ListProcedures lists the tenant's schema only. Full names are given without the schema,
+// as the tenant calls them.
+func (r *Repository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	t, ok := From(ctx)
+	if !ok {
+		return r.next.ListProcedures(ctx, filter)
+	}
+	if filter.Owner != "" && !strings.EqualFold(filter.Owner, t.Schema) {
+		return response.ListProceduresResponse{}, &Error{Status: http.StatusForbidden, Message: "owner " + filter.Owner + " belongs to another tenant"}
+	}
+
+	filter.Owner = t.Schema
+	result, err := r.next.ListProcedures(ctx, filter)
+	if err != nil {
+		return result, err
+	}
+	for i := range result.Items {
+		result.Items[i].FullName = strings.TrimPrefix(result.Items[i].FullName, t.Schema+".")
+	}
+	return result, nil
+}
+
+// TargetCheck refuses targets owned by another tenant's schema, which a tenant can reach
+// through a synonym of its own or a public one. It is meant for repository.WithTargetCheck.
+func TargetCheck(schemas []string) func(ctx context.Context, target response.ProcedureTarget) error {
+	owners := make(map[string]bool, len(schemas))
+	for _, schema := range schemas {
+		owners[strings.ToUpper(schema)] = true
+	}
+	return func(ctx context.Context, target response.ProcedureTarget) error {
+		t, ok := From(ctx)
+		if !ok || target.Owner == t.Schema || !owners[target.Owner] {
+			return nil
+		}
+		return &Error{Status: http.StatusForbidden, Message: "procedure " + target.String() + " belongs to another tenant"}
+	}
+}
+
+// check rejects names that could reach outside the tenant's schema: anything but
+// identifiers, names with an owner, and package names that are another tenant's schema.
+func (r *Repository) check(t Tenant, name string) error {
+	if err := request.ValidateName(name); err != nil {
+		return &Error{Status: http.StatusBadRequest, Message: err.Error()}
+	}
+	parts := strings.Split(name, ".")
+	if len(parts) > 2 {
+		return &Error{Status: http.StatusBadRequest, Message: "procedure names must not include a schema when a tenant is set"}
+	}
+	if first := strings.ToUpper(strings.TrimSpace(parts[0])); len(parts) == 2 && first != t.Schema && r.schemas[first] {
+		return &Error{Status: http.StatusForbidden, Message: "procedure " + name + " belongs to another tenant"}
+	}
+	return nil
+}
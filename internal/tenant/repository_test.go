@@ -0,0 +1,126 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"oracle-golang/internal/model/request"
+	"oracle-golang/internal/model/response"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	name   string
+	schema string
+}
+
+type fakeRepository struct {
+	calls []call
+}
+
+func (f *fakeRepository) CallProcedure(ctx context.Context, name string, _ []request.ProcedureParam) (map[string]any, error) {
+	f.calls = append(f.calls, call{name: name, schema: SchemaFrom(ctx)})
+	return map[string]any{}, nil
+}
+
+func (f *fakeRepository) GetProcedureInfo(ctx context.Context, name string) (*response.GetProcedureInfoResponse, error) {
+	f.calls = append(f.calls, call{name: name, schema: SchemaFrom(ctx)})
+	return nil, nil
+}
+
+func (f *fakeRepository) ListProcedures(ctx context.Context, filter request.ListProceduresRequest) (response.ListProceduresResponse, error) {
+	f.calls = append(f.calls, call{name: filter.Owner})
+	return response.ListProceduresResponse{Items: []response.ProcedureSummary{
+		{Owner: filter.Owner, Package: "PKG", Name: "GET_ORDERS", FullName: filter.Owner + ".PKG.GET_ORDERS"},
+	}}, nil
+}
+
+func TestRepository(t *testing.T) {
+	acme := With(context.Background(), Tenant{Name: "acme", Schema: "ACME"})
+
+	tests := []struct {
+		name       string
+		mode       Mode
+		ctx        context.Context
+		procedure  string
+		info       bool
+		want       call
+		wantStatus int
+	}{
+		{name: "prefix", mode: ModePrefix, ctx: acme, procedure: "pkg.get_orders", want: call{name: "ACME.pkg.get_orders"}},
+		{name: "current schema", mode: ModeCurrentSchema, ctx: acme, procedure: "pkg.get_orders", want: call{name: "pkg.get_orders", schema: "ACME"}},
+		{name: "metadata with prefix", mode: ModePrefix, ctx: acme, procedure: "pkg.get_orders", info: true, want: call{name: "pkg.get_orders", schema: "ACME"}},
+		{name: "own schema", mode: ModeCurrentSchema, ctx: acme, procedure: "acme.get_orders", want: call{name: "acme.get_orders", schema: "ACME"}},
+		{name: "no tenant", mode: ModeCurrentSchema, ctx: context.Background(), procedure: "globex_prod.pkg.get_orders", want: call{name: "globex_prod.pkg.get_orders"}},
+		{name: "owner in name", mode: ModeCurrentSchema, ctx: acme, procedure: "globex_prod.pkg.get_orders", wantStatus: http.StatusBadRequest},
+		{name: "owner in metadata lookup", mode: ModePrefix, ctx: acme, procedure: "globex_prod.pkg.get_orders", info: true, wantStatus: http.StatusBadRequest},
+		{name: "injected name", mode: ModeCurrentSchema, ctx: acme, procedure: "X(NULL); EXECUTE IMMEDIATE 'select 1 from globex_prod' || CHR(46) || 'T'; Y", wantStatus: http.StatusBadRequest},
+		{name: "injected name with prefix", mode: ModePrefix, ctx: acme, procedure: "get_orders(1); DELETE FROM t", wantStatus: http.StatusBadRequest},
+		{name: "another tenant's schema", mode: ModeCurrentSchema, ctx: acme, procedure: "Globex_Prod.get_orders", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &fakeRepository{}
+			repo := NewRepository(next, tt.mode, []string{"acme", "globex_prod"})
+
+			var err error
+			if tt.info {
+				_, err = repo.GetProcedureInfo(tt.ctx, tt.procedure)
+			} else {
+				_, err = repo.CallProcedure(tt.ctx, tt.procedure, nil)
+			}
+
+			if tt.wantStatus != 0 {
+				var tenantErr *Error
+				require.ErrorAs(t, err, &tenantErr)
+				assert.Equal(t, tt.wantStatus, tenantErr.StatusCode())
+				assert.Empty(t, next.calls)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []call{tt.want}, next.calls)
+		})
+	}
+}
+
+func TestRepository_ListProcedures(t *testing.T) {
+	acme := With(context.Background(), Tenant{Name: "acme", Schema: "ACME"})
+	next := &fakeRepository{}
+	repo := NewRepository(next, ModeCurrentSchema, []string{"acme", "globex_prod"})
+
+	// Tenants only see their own schema, listed under the names they call.
+	result, err := repo.ListProcedures(acme, request.ListProceduresRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "PKG.GET_ORDERS", result.Items[0].FullName)
+	_, err = repo.ListProcedures(acme, request.ListProceduresRequest{Owner: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, []call{{name: "ACME"}, {name: "ACME"}}, next.calls)
+
+	_, err = repo.ListProcedures(acme, request.ListProceduresRequest{Owner: "globex_prod"})
+	var tenantErr *Error
+	require.ErrorAs(t, err, &tenantErr)
+	assert.Equal(t, http.StatusForbidden, tenantErr.StatusCode())
+
+	result, err = repo.ListProcedures(context.Background(), request.ListProceduresRequest{Owner: "GLOBEX_PROD"})
+	require.NoError(t, err)
+	assert.Equal(t, "GLOBEX_PROD.PKG.GET_ORDERS", result.Items[0].FullName)
+}
+
+func TestTargetCheck(t *testing.T) {
+	check := TargetCheck([]string{"acme", "globex_prod"})
+	acme := With(context.Background(), Tenant{Name: "acme", Schema: "ACME"})
+
+	assert.NoError(t, check(acme, response.ProcedureTarget{Owner: "ACME", Package: "PKG", Name: "PAY"}))
+	// Shared code reached through public synonyms, such as SYS packages, stays callable.
+	assert.NoError(t, check(acme, response.ProcedureTarget{Owner: "SYS", Package: "DBMS_OUTPUT", Name: "PUT_LINE"}))
+	assert.NoError(t, check(context.Background(), response.ProcedureTarget{Owner: "GLOBEX_PROD", Name: "PAY"}))
+
+	err := check(acme, response.ProcedureTarget{Owner: "GLOBEX_PROD", Name: "PAY"})
+	var tenantErr *Error
+	require.ErrorAs(t, err, &tenantErr)
+	assert.Equal(t, http.StatusForbidden, tenantErr.StatusCode())
+	assert.Equal(t, "procedure GLOBEX_PROD.PAY belongs to another tenant", tenantErr.Error())
+}
@@ -0,0 +1,57 @@
+package tenant
+
+import "context"
+
+// Tenant is the customer a request acts for and the schema holding its data.
+type Tenant struct {
+	Name   string
+	Schema string
+}
+
+type (
+	tenantKey struct{}
+	schemaKey struct{}
+)
+
+// With returns a copy of ctx acting for t.
+func With(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// From returns the tenant of the request, if one was resolved. Internal callers such as
+// the scheduler have none.
+func From(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(tenantKey{}).(Tenant)
+	return t, ok
+}
+
+// Name returns the tenant name of the request, or "" without one.
+func Name(ctx context.Context) string {
+	t, _ := From(ctx)
+	return t.Name
+}
+
+func withSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, schemaKey{}, schema)
+}
+
+// SchemaFrom returns the schema unqualified names resolve against for this call, or "" for
+// the connecting user's own schema. It is set by Repository.
+func SchemaFrom(ctx context.Context) string {
+	schema, _ := ctx.Value(schemaKey{}).(string)
+	return schema
+}
+
+// Error is returned for requests a tenant may not make.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) StatusCode() int {
+	return e.Status
+}
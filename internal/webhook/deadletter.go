@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"oracle-golang/internal/job"
+	"os"
+	"sync"
+	"time"
+)
+
+// DeadLetter records a callback that could not be delivered.
+type DeadLetter struct {
+	JobID    string    `json:"job_id"`
+	URL      string    `json:"url"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+	Job      *job.Job  `json:"job"`
+}
+
+type DeadLetterStore interface {
+	Add(ctx context.Context, d DeadLetter) error
+}
+
+// FileDeadLetterStore appends dead letters to a JSON Lines file.
+type FileDeadLetterStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileDeadLetterStore(path string) *FileDeadLetterStore {
+	return &FileDeadLetterStore{path: path}
+}
+
+func (s *FileDeadLetterStore) Add(_ context.Context, d DeadLetter) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open dead letter file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+type MemoryDeadLetterStore struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{}
+}
+
+func (s *MemoryDeadLetterStore) Add(_ context.Context, d DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.letters = append(s.letters, d)
+	return nil
+}
+
+func (s *MemoryDeadLetterStore) List() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.letters...)
+}
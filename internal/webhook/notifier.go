@@ -0,0 +1,242 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"oracle-golang/internal/job"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+)
+
+// ErrPrivateAddress is returned for callbacks to addresses inside the network.
+var ErrPrivateAddress = errors.New("callback address is not public")
+
+const (
+	SignatureHeader = "X-Signature-256"
+	TimestampHeader = "X-Webhook-Timestamp"
+	JobIDHeader     = "X-Job-ID"
+)
+
+type Options struct {
+	// Secret signs deliveries. Without it the signature header is left out.
+	Secret string
+	// AllowPrivateNetworks lets callbacks reach loopback, link-local and private addresses,
+	// which are refused by default so that job submitters cannot reach internal services.
+	AllowPrivateNetworks bool
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Timeout              time.Duration
+}
+
+// Notifier POSTs finished jobs to their callback URL. When a secret is configured every
+// request is signed with HMAC-SHA256 over "<timestamp>.<body>", so receivers can verify both
+// the payload and its age.
+// Failed deliveries are retried with exponential backoff and end up in the dead letter store.
+type Notifier struct {
+	client      *http.Client
+	opts        Options
+	deadLetters DeadLetterStore
+	now         func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+func NewNotifier(opts Options, deadLetters DeadLetterStore) *Notifier {
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !opts.AllowPrivateNetworks {
+		// Checked on the resolved address at dial time, so DNS cannot point around it.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: refusePrivate}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+
+	return &Notifier{
+		client:      &http.Client{Timeout: opts.Timeout, Transport: transport},
+		opts:        opts,
+		deadLetters: deadLetters,
+		now:         time.Now,
+		sleep:       sleep,
+	}
+}
+
+func (n *Notifier) Notify(ctx context.Context, j *job.Job) {
+	if j.Callback == nil {
+		return
+	}
+
+	body, err := json.Marshal(j)
+	if err != nil {
+		n.deadLetter(ctx, j, 0, fmt.Errorf("encode payload: %w", err))
+		return
+	}
+
+	headers, err := renderHeaders(j)
+	if err != nil {
+		n.deadLetter(ctx, j, 0, err)
+		return
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= n.opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := n.sleep(ctx, n.backoff(attempt-1)); err != nil {
+				lastErr = err
+				n.deadLetter(ctx, j, attempt-1, lastErr)
+				return
+			}
+		}
+
+		retry, err := n.deliver(ctx, j, headers, body)
+		if err == nil {
+			return
+		}
+		lastErr = err
+		slog.WarnContext(ctx, "webhook delivery failed",
+			"job_id", j.ID,
+			"attempt", attempt,
+			"max_attempts", n.opts.MaxAttempts,
+			"error", err,
+		)
+
+		if !retry {
+			n.deadLetter(ctx, j, attempt, lastErr)
+			return
+		}
+	}
+
+	n.deadLetter(ctx, j, n.opts.MaxAttempts, lastErr)
+}
+
+// deliver sends a single request and reports whether a failure is worth retrying.
+func (n *Notifier) deliver(ctx context.Context, j *job.Job, headers map[string]string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.Callback.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("build request: %w", err)
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	timestamp := strconv.FormatInt(n.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(JobIDHeader, j.ID)
+	req.Header.Set(TimestampHeader, timestamp)
+	if n.opts.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.opts.Secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, ErrPrivateAddress), err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("callback responded with status %d", resp.StatusCode)
+	retry := resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+	return retry, err
+}
+
+func (n *Notifier) backoff(retry int) time.Duration {
+	d := n.opts.BaseDelay << (retry - 1)
+	if n.opts.MaxDelay > 0 && (d > n.opts.MaxDelay || d <= 0) {
+		d = n.opts.MaxDelay
+	}
+	return d
+}
+
+func (n *Notifier) deadLetter(ctx context.Context, j *job.Job, attempts int, err error) {
+	if n.deadLetters == nil {
+		return
+	}
+
+	record := DeadLetter{
+		JobID:    j.ID,
+		URL:      j.Callback.URL,
+		Attempts: attempts,
+		Error:    err.Error(),
+		FailedAt: n.now(),
+		Job:      j,
+	}
+	if err := n.deadLetters.Add(context.WithoutCancel(ctx), record); err != nil {
+		slog.ErrorContext(ctx, "failed to store webhook dead letter", "job_id", j.ID, "error", err)
+	}
+}
+
+// refusePrivate is a net.Dialer Control function that refuses addresses inside the network.
+func refusePrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func renderHeaders(j *job.Job) (map[string]string, error) {
+	headers := make(map[string]string, len(j.Callback.Headers))
+	for name, value := range j.Callback.Headers {
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("parse header %s: %w", name, err)
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, j); err != nil {
+			return nil, fmt.Errorf("render header %s: %w", name, err)
+		}
+		headers[name] = sb.String()
+	}
+	return headers, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
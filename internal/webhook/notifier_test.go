@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"oracle-golang/internal/job"
+	"oracle-golang/internal/model/request"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNotifier(maxAttempts int, deadLetters DeadLetterStore) *Notifier {
+	n := NewNotifier(Options{
+		Secret:               "s3cret",
+		AllowPrivateNetworks: true,
+		MaxAttempts:          maxAttempts,
+		BaseDelay:            time.Millisecond,
+		MaxDelay:             5 * time.Millisecond,
+	}, deadLetters)
+	n.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return n
+}
+
+func newFinishedJob(url string) *job.Job {
+	return &job.Job{
+		ID:      "job-1",
+		Status:  job.StatusSucceeded,
+		Request: request.CallProcedureRequest{Name: "PKG.MONTH_END"},
+		Result:  map[string]any{"p_count": float64(3)},
+		Callback: &job.Callback{
+			URL:     url,
+			Headers: map[string]string{"Authorization": "Bearer token", "X-Procedure": "{{.Request.Name}}/{{.Status}}"},
+		},
+	}
+}
+
+func TestNotifier_DeliversSignedPayload(t *testing.T) {
+	var (
+		gotHeaders http.Header
+		gotBody    []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	deadLetters := NewMemoryDeadLetterStore()
+	newTestNotifier(3, deadLetters).Notify(context.Background(), newFinishedJob(server.URL))
+
+	require.NotNil(t, gotBody)
+	assert.Equal(t, "Bearer token", gotHeaders.Get("Authorization"))
+	assert.Equal(t, "PKG.MONTH_END/succeeded", gotHeaders.Get("X-Procedure"))
+	assert.Equal(t, "job-1", gotHeaders.Get(JobIDHeader))
+	assert.Equal(t, "1700000000", gotHeaders.Get(TimestampHeader))
+	assert.Equal(t, "sha256="+Sign("s3cret", "1700000000", gotBody), gotHeaders.Get(SignatureHeader))
+
+	var payload job.Job
+	require.NoError(t, json.Unmarshal(gotBody, &payload))
+	assert.Equal(t, "job-1", payload.ID)
+	assert.Equal(t, float64(3), payload.Result["p_count"])
+	assert.NotContains(t, string(gotBody), "Bearer token")
+
+	assert.Empty(t, deadLetters.List())
+}
+
+func TestNotifier_Unsigned(t *testing.T) {
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	NewNotifier(Options{AllowPrivateNetworks: true}, nil).Notify(context.Background(), newFinishedJob(server.URL))
+
+	require.NotNil(t, gotHeaders)
+	assert.Empty(t, gotHeaders.Get(SignatureHeader))
+}
+
+func TestNotifier_RefusesPrivateNetworks(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	deadLetters := NewMemoryDeadLetterStore()
+	n := NewNotifier(Options{Secret: "s3cret", MaxAttempts: 3}, deadLetters)
+	n.Notify(context.Background(), newFinishedJob(server.URL))
+
+	assert.Zero(t, atomic.LoadInt32(&calls))
+	letters := deadLetters.List()
+	require.Len(t, letters, 1)
+	assert.Equal(t, 1, letters[0].Attempts)
+	assert.Contains(t, letters[0].Error, ErrPrivateAddress.Error())
+}
+
+func TestRefusePrivate(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "[::1]:443", "10.1.2.3:80", "192.168.0.1:80", "169.254.169.254:80", "[fe80::1]:80", "0.0.0.0:80"} {
+		assert.ErrorIs(t, refusePrivate("tcp", address, nil), ErrPrivateAddress, address)
+	}
+	assert.NoError(t, refusePrivate("tcp", "93.184.216.34:443", nil))
+}
+
+func TestNotifier_Retries(t *testing.T) {
+	tests := []struct {
+		name               string
+		statuses           []int
+		maxAttempts        int
+		expectedCalls      int32
+		expectedDeadLetter bool
+	}{
+		{
+			name:          "succeeds after server errors",
+			statuses:      []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			maxAttempts:   3,
+			expectedCalls: 3,
+		},
+		{
+			name:               "gives up after max attempts",
+			statuses:           []int{http.StatusInternalServerError},
+			maxAttempts:        3,
+			expectedCalls:      3,
+			expectedDeadLetter: true,
+		},
+		{
+			name:               "does not retry client errors",
+			statuses:           []int{http.StatusBadRequest},
+			maxAttempts:        3,
+			expectedCalls:      1,
+			expectedDeadLetter: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := int(atomic.AddInt32(&calls, 1)) - 1
+				if i >= len(tt.statuses) {
+					i = len(tt.statuses) - 1
+				}
+				w.WriteHeader(tt.statuses[i])
+			}))
+			defer server.Close()
+
+			deadLetters := NewMemoryDeadLetterStore()
+			newTestNotifier(tt.maxAttempts, deadLetters).Notify(context.Background(), newFinishedJob(server.URL))
+
+			assert.Equal(t, tt.expectedCalls, atomic.LoadInt32(&calls))
+			if tt.expectedDeadLetter {
+				letters := deadLetters.List()
+				require.Len(t, letters, 1)
+				assert.Equal(t, "job-1", letters[0].JobID)
+				assert.Equal(t, int(tt.expectedCalls), letters[0].Attempts)
+				assert.Contains(t, letters[0].Error, "callback responded with status")
+			} else {
+				assert.Empty(t, deadLetters.List())
+			}
+		})
+	}
+}
+
+func TestNotifier_Backoff(t *testing.T) {
+	n := NewNotifier(Options{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, nil)
+
+	assert.Equal(t, time.Second, n.backoff(1))
+	assert.Equal(t, 2*time.Second, n.backoff(2))
+	assert.Equal(t, 4*time.Second, n.backoff(3))
+	assert.Equal(t, 5*time.Second, n.backoff(4))
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+
+	"github.com/sijms/go-ora/v2/network"
+)
+
+var oraCodePattern = regexp.MustCompile(`ORA-\d{5}`)
+
+// connectionErrorCodes are raised when the session or the listener is unreachable, as during
+// a failover. They say nothing about whether the procedure itself ran.
+var connectionErrorCodes = map[string]bool{
+	"ORA-01033": true, // initialization or shutdown in progress
+	"ORA-01034": true, // ORACLE not available
+	"ORA-01089": true, // immediate shutdown in progress
+	"ORA-03113": true, // end-of-file on communication channel
+	"ORA-03114": true, // not connected to ORACLE
+	"ORA-03135": true, // connection lost contact
+	"ORA-12170": true, // TNS:Connect timeout occurred
+	"ORA-12514": true, // TNS:listener does not currently know of service
+	"ORA-12528": true, // TNS:listener: all appropriate instances are blocking new connections
+	"ORA-12537": true, // TNS:connection closed
+	"ORA-12541": true, // TNS:no listener
+	"ORA-12543": true, // TNS:destination host unreachable
+	"ORA-12547": true, // TNS:lost contact
+	"ORA-25408": true, // can not safely replay call
+}
+
+/*
+OraErrorCode returns the Oracle error code of err, for example ORA-01403.
+Driver errors are inspected first, then the first code found in the message.
+An empty string is returned when err carries no Oracle error code.
+*/
+func OraErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var oraErr *network.OracleError
+	if errors.As(err, &oraErr) && oraErr.ErrCode > 0 {
+		return fmt.Sprintf("ORA-%05d", oraErr.ErrCode)
+	}
+
+	return oraCodePattern.FindString(err.Error())
+}
+
+// IsConnectionError reports whether err means the database could not be reached, as
+// opposed to an error raised by the procedure.
+func IsConnectionError(err error) bool {
+	// context.DeadlineExceeded implements net.Error, but it is the caller giving up.
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if connectionErrorCodes[OraErrorCode(err)] {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, network.ErrConnReset) ||
+		errors.As(err, &netErr)
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/sijms/go-ora/v2/network"
+)
+
+func TestOraErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "nil error", err: nil, expected: ""},
+		{name: "driver error", err: network.NewOracleError(1403), expected: "ORA-01403"},
+		{name: "wrapped driver error", err: fmt.Errorf("execution failed: %w", network.NewOracleError(3113)), expected: "ORA-03113"},
+		{name: "code in message", err: errors.New("execution failed: ORA-20001: period is closed\nORA-06512: at line 1"), expected: "ORA-20001"},
+		{name: "no code", err: errors.New("context deadline exceeded"), expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OraErrorCode(tt.err); got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil error", err: nil, expected: false},
+		{name: "lost connection", err: fmt.Errorf("execution failed: %w", network.NewOracleError(3113)), expected: true},
+		{name: "no listener in message", err: errors.New("ORA-12541: TNS:no listener"), expected: true},
+		{name: "bad connection", err: fmt.Errorf("execution failed: %w", driver.ErrBadConn), expected: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, expected: true},
+		{name: "application error", err: errors.New("ORA-20001: period is closed"), expected: false},
+		{name: "no data found", err: network.NewOracleError(1403), expected: false},
+		{name: "timeout from caller", err: fmt.Errorf("execution failed: %w", context.DeadlineExceeded), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsConnectionError(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}